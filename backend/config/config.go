@@ -1,6 +1,10 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type App struct {
 	Port string `json:"app_port"`
@@ -16,13 +20,80 @@ type PostgresDB struct {
 }
 
 type Workers struct {
-	Count int `json:"count"`
+	Count            int           `json:"count"`
+	TypeConcurrency  string        `json:"type_concurrency"`
+	PriorityAging    time.Duration `json:"priority_aging"`
+	TypeTimeout      string        `json:"type_timeout"`
+	StallTimeout     time.Duration `json:"stall_timeout"`
+	TimeoutRequeues  int           `json:"timeout_requeues"`
+	WatchdogInterval time.Duration `json:"watchdog_interval"`
+}
+
+type Idempotency struct {
+	TTL time.Duration `json:"ttl"`
+}
+
+type Orders struct {
+	ReservationTTL           time.Duration `json:"reservation_ttl"`
+	ReservationSweepInterval time.Duration `json:"reservation_sweep_interval"`
+}
+
+type Admission struct {
+	Enabled       bool          `json:"enabled"`
+	Backend       string        `json:"backend"`
+	RatePerSecond int           `json:"rate_per_second"`
+	TokenTTL      time.Duration `json:"token_ttl"`
+}
+
+type Pricing struct {
+	TaxInclusive   bool   `json:"tax_inclusive"`
+	TaxRates       string `json:"tax_rates"`
+	TaxRounding    string `json:"tax_rounding"`
+	PlatformFee    string `json:"platform_fee"`
+	PlatformFeeBps int    `json:"platform_fee_bps"`
+}
+
+type Payments struct {
+	MerchantFeeBps        int           `json:"merchant_fee_bps"`
+	SimulatorDelay        time.Duration `json:"simulator_delay"`
+	SimulatorFailureRate  float64       `json:"simulator_failure_rate"`
+	SimulatorManualSettle bool          `json:"simulator_manual_settle"`
+}
+
+type Outbox struct {
+	Sinks          string        `json:"sinks"`
+	WebhookURL     string        `json:"webhook_url"`
+	WebhookTimeout time.Duration `json:"webhook_timeout"`
+	PollInterval   time.Duration `json:"poll_interval"`
+	BatchSize      int           `json:"batch_size"`
+	Lease          time.Duration `json:"lease"`
+}
+
+type Webhooks struct {
+	MaxAttempts    int           `json:"max_attempts"`
+	RetryBaseDelay time.Duration `json:"retry_base_delay"`
+	Timeout        time.Duration `json:"timeout"`
+	PollInterval   time.Duration `json:"poll_interval"`
+	Lease          time.Duration `json:"lease"`
+}
+
+type Scheduler struct {
+	PollInterval time.Duration `json:"poll_interval"`
+	MisfireGrace time.Duration `json:"misfire_grace"`
 }
 
 type Config struct {
-	App      App        `json:"app"`
-	Postgres PostgresDB `json:"postgres"`
-	WORKERS  Workers    `json:"workers"`
+	App         App         `json:"app"`
+	Postgres    PostgresDB  `json:"postgres"`
+	WORKERS     Workers     `json:"workers"`
+	Idempotency Idempotency `json:"idempotency"`
+	Orders      Orders      `json:"orders"`
+	Admission   Admission   `json:"admission"`
+	Pricing     Pricing     `json:"pricing"`
+	Payments    Payments    `json:"payments"`
+	Outbox      Outbox      `json:"outbox"`
+	Webhooks    Webhooks    `json:"webhooks"`
+	Scheduler   Scheduler   `json:"scheduler"`
 }
 
 func NewConfig() *Config {
@@ -40,7 +111,58 @@ func NewConfig() *Config {
 			DBMaxIdle: viper.GetInt("DATABASE_MAX_IDLE_CONNECTION"),
 		},
 		WORKERS: Workers{
-			Count: viper.GetInt("WORKERS_COUNT"),
+			Count:            viper.GetInt("WORKERS_COUNT"),
+			TypeConcurrency:  viper.GetString("WORKERS_TYPE_CONCURRENCY"),
+			PriorityAging:    viper.GetDuration("WORKERS_PRIORITY_AGING"),
+			TypeTimeout:      viper.GetString("WORKERS_TYPE_TIMEOUT"),
+			StallTimeout:     viper.GetDuration("WORKERS_STALL_TIMEOUT"),
+			TimeoutRequeues:  viper.GetInt("WORKERS_TIMEOUT_REQUEUES"),
+			WatchdogInterval: viper.GetDuration("WORKERS_WATCHDOG_INTERVAL"),
+		},
+		Idempotency: Idempotency{
+			TTL: viper.GetDuration("IDEMPOTENCY_KEY_TTL"),
+		},
+		Orders: Orders{
+			ReservationTTL:           viper.GetDuration("ORDER_RESERVATION_TTL"),
+			ReservationSweepInterval: viper.GetDuration("ORDER_RESERVATION_SWEEP_INTERVAL"),
+		},
+		Admission: Admission{
+			Enabled:       viper.GetBool("ADMISSION_ENABLED"),
+			Backend:       viper.GetString("ADMISSION_BACKEND"),
+			RatePerSecond: viper.GetInt("ADMISSION_RATE_PER_SECOND"),
+			TokenTTL:      viper.GetDuration("ADMISSION_TOKEN_TTL"),
+		},
+		Pricing: Pricing{
+			TaxInclusive:   viper.GetBool("TAX_INCLUSIVE"),
+			TaxRates:       viper.GetString("TAX_RATES"),
+			TaxRounding:    viper.GetString("TAX_ROUNDING"),
+			PlatformFee:    viper.GetString("PLATFORM_FEE"),
+			PlatformFeeBps: viper.GetInt("PLATFORM_FEE_BPS"),
+		},
+		Payments: Payments{
+			MerchantFeeBps:        viper.GetInt("PAYMENT_MERCHANT_FEE_BPS"),
+			SimulatorDelay:        viper.GetDuration("PAYMENT_SIMULATOR_DELAY"),
+			SimulatorFailureRate:  viper.GetFloat64("PAYMENT_SIMULATOR_FAILURE_RATE"),
+			SimulatorManualSettle: viper.GetBool("PAYMENT_SIMULATOR_MANUAL_SETTLE"),
+		},
+		Outbox: Outbox{
+			Sinks:          viper.GetString("OUTBOX_SINKS"),
+			WebhookURL:     viper.GetString("OUTBOX_WEBHOOK_URL"),
+			WebhookTimeout: viper.GetDuration("OUTBOX_WEBHOOK_TIMEOUT"),
+			PollInterval:   viper.GetDuration("OUTBOX_POLL_INTERVAL"),
+			BatchSize:      viper.GetInt("OUTBOX_BATCH_SIZE"),
+			Lease:          viper.GetDuration("OUTBOX_LEASE"),
+		},
+		Webhooks: Webhooks{
+			MaxAttempts:    viper.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+			RetryBaseDelay: viper.GetDuration("WEBHOOK_RETRY_BASE_DELAY"),
+			Timeout:        viper.GetDuration("WEBHOOK_TIMEOUT"),
+			PollInterval:   viper.GetDuration("WEBHOOK_POLL_INTERVAL"),
+			Lease:          viper.GetDuration("WEBHOOK_LEASE"),
+		},
+		Scheduler: Scheduler{
+			PollInterval: viper.GetDuration("SCHEDULER_POLL_INTERVAL"),
+			MisfireGrace: viper.GetDuration("SCHEDULER_MISFIRE_GRACE"),
 		},
 	}
 }
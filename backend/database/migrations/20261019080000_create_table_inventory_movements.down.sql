@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS trg_inventory_movements_append_only ON inventory_movements;
+
+DROP FUNCTION IF EXISTS inventory_movements_append_only;
+
+DROP INDEX IF EXISTS "idx_inventory_movements_product_id";
+
+DROP INDEX IF EXISTS "idx_inventory_movements_order_id";
+
+DROP TABLE IF EXISTS "inventory_movements";
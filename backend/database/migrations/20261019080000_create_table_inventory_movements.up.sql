@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS inventory_movements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    product_id INTEGER NOT NULL REFERENCES products (id),
+    order_id UUID,
+    type VARCHAR(50) NOT NULL,
+    quantity INTEGER NOT NULL,
+    stock_after INTEGER NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_product_id ON inventory_movements (product_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_order_id ON inventory_movements (order_id);
+
+CREATE OR REPLACE FUNCTION inventory_movements_append_only () RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'inventory_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_inventory_movements_append_only
+BEFORE UPDATE OR DELETE ON inventory_movements
+FOR EACH ROW EXECUTE FUNCTION inventory_movements_append_only ();
+
+INSERT INTO inventory_movements (product_id, type, quantity, stock_after, reason)
+SELECT id, 'ADJUSTMENT', stock, stock, 'opening balance'
+FROM products
+WHERE stock <> 0;
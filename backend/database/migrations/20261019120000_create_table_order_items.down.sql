@@ -0,0 +1,16 @@
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS product_id INTEGER REFERENCES products (id),
+    ADD COLUMN IF NOT EXISTS quantity INTEGER;
+
+UPDATE orders o
+SET product_id = i.product_id, quantity = i.quantity
+FROM (
+    SELECT DISTINCT ON (order_id) order_id, product_id, quantity
+    FROM order_items
+    ORDER BY order_id, product_id
+) i
+WHERE i.order_id = o.id;
+
+DROP INDEX IF EXISTS "idx_order_items_product_id";
+
+DROP TABLE IF EXISTS "order_items";
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS order_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    order_id UUID NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    product_id INTEGER NOT NULL REFERENCES products (id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price_cents INTEGER NOT NULL,
+    total_cents INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (order_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_items_product_id ON order_items (product_id);
+
+INSERT INTO order_items (order_id, product_id, quantity, unit_price_cents, total_cents, created_at)
+SELECT id, product_id, quantity, total_cents / quantity, total_cents, created_at
+FROM orders
+WHERE quantity > 0;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS product_id,
+    DROP COLUMN IF EXISTS quantity;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS cart_items (
+    buyer_id VARCHAR(255) NOT NULL,
+    product_id INTEGER NOT NULL REFERENCES products (id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (buyer_id, product_id)
+);
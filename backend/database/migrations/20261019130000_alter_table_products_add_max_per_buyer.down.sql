@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_orders_buyer_status";
+
+ALTER TABLE products DROP COLUMN IF EXISTS max_per_buyer;
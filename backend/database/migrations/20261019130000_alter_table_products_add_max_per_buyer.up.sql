@@ -0,0 +1,3 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS max_per_buyer INTEGER CHECK (max_per_buyer > 0);
+
+CREATE INDEX IF NOT EXISTS idx_orders_buyer_status ON orders (buyer_id, status);
@@ -0,0 +1,4 @@
+ALTER TABLE products
+    DROP CONSTRAINT IF EXISTS chk_products_sale_window,
+    DROP COLUMN IF EXISTS sale_starts_at,
+    DROP COLUMN IF EXISTS sale_ends_at;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_admission_tokens_status_seq";
+
+DROP TABLE IF EXISTS "admission_tokens";
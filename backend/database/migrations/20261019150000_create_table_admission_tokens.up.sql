@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS admission_tokens (
+    token UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    seq BIGSERIAL NOT NULL UNIQUE,
+    buyer_id VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'WAITING',
+    admitted_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admission_tokens_status_seq ON admission_tokens (status, seq);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_orders_buyer_created_at_id";
+
+DROP INDEX IF EXISTS "idx_orders_created_at_id";
@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_orders_created_at_id ON orders (created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_orders_buyer_created_at_id ON orders (buyer_id, created_at DESC, id DESC);
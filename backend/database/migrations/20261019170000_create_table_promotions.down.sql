@@ -0,0 +1,10 @@
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS promotion_id,
+    DROP COLUMN IF EXISTS discount_cents,
+    DROP COLUMN IF EXISTS gross_cents;
+
+DROP INDEX IF EXISTS "idx_promotion_redemptions_promotion_buyer";
+
+DROP TABLE IF EXISTS "promotion_redemptions";
+
+DROP TABLE IF EXISTS "promotions";
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS promotions (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    type VARCHAR(50) NOT NULL CHECK (type IN ('PERCENTAGE', 'FIXED')),
+    value INTEGER NOT NULL CHECK (value > 0),
+    product_id INTEGER REFERENCES products (id),
+    max_uses INTEGER CHECK (max_uses > 0),
+    max_uses_per_buyer INTEGER CHECK (max_uses_per_buyer > 0),
+    used_count INTEGER NOT NULL DEFAULT 0 CHECK (used_count >= 0),
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CHECK (type <> 'PERCENTAGE' OR value <= 100),
+    CHECK (starts_at IS NULL OR ends_at IS NULL OR starts_at < ends_at)
+);
+
+CREATE TABLE IF NOT EXISTS promotion_redemptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    promotion_id INTEGER NOT NULL REFERENCES promotions (id),
+    order_id UUID NOT NULL UNIQUE REFERENCES orders (id) ON DELETE CASCADE,
+    buyer_id VARCHAR(255) NOT NULL,
+    discount_cents INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotion_redemptions_promotion_buyer ON promotion_redemptions (promotion_id, buyer_id);
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS gross_cents INTEGER,
+    ADD COLUMN IF NOT EXISTS discount_cents INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS promotion_id INTEGER REFERENCES promotions (id);
+
+UPDATE orders SET gross_cents = total_cents WHERE gross_cents IS NULL;
+
+ALTER TABLE orders ALTER COLUMN gross_cents SET NOT NULL;
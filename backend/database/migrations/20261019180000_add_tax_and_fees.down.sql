@@ -0,0 +1,11 @@
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS tax_cents,
+    DROP COLUMN IF EXISTS tax_rate_bps,
+    DROP COLUMN IF EXISTS discount_cents;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS fee_cents,
+    DROP COLUMN IF EXISTS tax_inclusive,
+    DROP COLUMN IF EXISTS tax_cents;
+
+ALTER TABLE products DROP COLUMN IF EXISTS category;
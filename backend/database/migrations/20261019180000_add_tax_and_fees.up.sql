@@ -0,0 +1,11 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category VARCHAR(50) NOT NULL DEFAULT 'GENERAL';
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS tax_cents INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_inclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS fee_cents INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE order_items
+    ADD COLUMN IF NOT EXISTS discount_cents INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_rate_bps INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS tax_cents INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS "idx_settlements_merchant_date_currency";
+
+-- Settlements split by currency cannot share the old (merchant_id, date) key, so only one currency survives.
+DELETE FROM settlements WHERE currency <> 'IDR';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_settlements_merchant_date ON settlements (merchant_id, date);
+
+ALTER TABLE settlements DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE promotions DROP CONSTRAINT IF EXISTS promotions_fixed_currency_check;
+
+ALTER TABLE promotions DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,17 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+
+ALTER TABLE promotions ADD COLUMN IF NOT EXISTS currency VARCHAR(3);
+
+UPDATE promotions SET currency = 'IDR' WHERE type = 'FIXED' AND currency IS NULL;
+
+ALTER TABLE promotions ADD CONSTRAINT promotions_fixed_currency_check CHECK (type <> 'FIXED' OR currency IS NOT NULL);
+
+ALTER TABLE settlements ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+
+DROP INDEX IF EXISTS "idx_settlements_merchant_date";
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_settlements_merchant_date_currency ON settlements (merchant_id, date, currency);
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS "idx_transactions_payment_intent_id";
+
+DROP INDEX IF EXISTS "idx_transactions_order_id";
+
+ALTER TABLE transactions ALTER COLUMN paid_at SET DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS failure_reason,
+    DROP COLUMN IF EXISTS payment_intent_id,
+    DROP COLUMN IF EXISTS order_id;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_intent_id;
+
+ALTER TABLE products DROP COLUMN IF EXISTS merchant_id;
@@ -0,0 +1,15 @@
+ALTER TABLE products ADD COLUMN IF NOT EXISTS merchant_id VARCHAR(255) NOT NULL DEFAULT 'merchant_001';
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_intent_id VARCHAR(255);
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS order_id UUID REFERENCES orders (id),
+    ADD COLUMN IF NOT EXISTS payment_intent_id VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS failure_reason TEXT;
+
+-- paid_at is only known once the gateway reports the payment.
+ALTER TABLE transactions ALTER COLUMN paid_at DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_order_id ON transactions (order_id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_payment_intent_id ON transactions (payment_intent_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS job_throughput_stats;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS throughput;
@@ -0,0 +1,9 @@
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS throughput DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+-- Rows per second that finished jobs of each type sustained, smoothed across runs; QUEUED jobs are estimated from it.
+CREATE TABLE IF NOT EXISTS job_throughput_stats (
+    job_type VARCHAR(100) PRIMARY KEY,
+    rows_per_second DOUBLE PRECISION NOT NULL,
+    samples INTEGER NOT NULL DEFAULT 1,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS job_schedule_runs;
+
+DROP TABLE IF EXISTS job_schedules;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS job_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    name VARCHAR(255) NOT NULL,
+    cron_expression VARCHAR(100) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    job_type VARCHAR(100) NOT NULL,
+    date_range VARCHAR(50) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_schedules_due ON job_schedules (next_run_at) WHERE active;
+
+CREATE TABLE IF NOT EXISTS job_schedule_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    schedule_id UUID NOT NULL REFERENCES job_schedules (id) ON DELETE CASCADE,
+    scheduled_for TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(50) NOT NULL CHECK (status IN ('PENDING', 'FIRED', 'FAILED', 'MISSED')),
+    job_id UUID REFERENCES jobs (id),
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    -- Every occurrence is handled once, whichever replica gets to it.
+    UNIQUE (schedule_id, scheduled_for)
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_schedule_runs_schedule ON job_schedule_runs (schedule_id, scheduled_for);
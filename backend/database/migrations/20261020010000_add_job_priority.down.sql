@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_jobs_queued_priority;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS priority;
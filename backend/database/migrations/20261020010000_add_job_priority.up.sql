@@ -0,0 +1,4 @@
+-- Higher runs first, 0 to 9.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS priority SMALLINT NOT NULL DEFAULT 5 CHECK (priority BETWEEN 0 AND 9);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_queued_priority ON jobs (priority DESC, created_at) WHERE status = 'QUEUED';
@@ -0,0 +1,2 @@
+-- Where a PAUSED job stopped and what it had aggregated by then; cleared once the job completes.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS checkpoint JSONB;
@@ -0,0 +1,4 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS requeues;
+ALTER TABLE jobs DROP COLUMN IF EXISTS heartbeat_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS deadline_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS timeout_seconds;
@@ -0,0 +1,7 @@
+-- timeout_seconds overrides the max runtime of the job's type. deadline_at is when the current run must end and
+-- heartbeat_at when it last made progress; the watchdog fails or requeues RUNNING jobs past either, counting
+-- requeues.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS timeout_seconds INTEGER CHECK (timeout_seconds > 0);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS deadline_at TIMESTAMP;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMP;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS requeues INTEGER NOT NULL DEFAULT 0;
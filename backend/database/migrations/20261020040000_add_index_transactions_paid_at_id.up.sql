@@ -0,0 +1,2 @@
+-- Settlement jobs page through PAID transactions by (paid_at, id).
+CREATE INDEX IF NOT EXISTS idx_transactions_paid_at_id ON transactions (paid_at, id) WHERE status = 'PAID';
@@ -0,0 +1,2 @@
+-- The scheduler looks for runs left PENDING by an instance that stopped before creating their job.
+CREATE INDEX IF NOT EXISTS idx_job_schedule_runs_pending ON job_schedule_runs (created_at) WHERE status = 'PENDING';
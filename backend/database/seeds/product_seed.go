@@ -40,8 +40,28 @@ func (s *Seeder) SeedProducts(ctx context.Context) error {
 		Stock:      100,
 	}
 
-	if err := s.db.Create(product).Error; err != nil {
-		return fmt.Errorf("failed to seed product: %w", err)
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return fmt.Errorf("failed to seed product: %w", err)
+		}
+
+		reason := "initial stock"
+		movement := &model.InventoryMovementModel{
+			ProductID:  product.ID,
+			Type:       "RESTOCK",
+			Quantity:   product.Stock,
+			StockAfter: product.Stock,
+			Reason:     &reason,
+		}
+
+		if err := tx.Create(movement).Error; err != nil {
+			return fmt.Errorf("failed to seed inventory movement: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	log.Println("Products seeded successfully")
@@ -115,7 +135,9 @@ func (s *Seeder) SeedTransactions(ctx context.Context, count int) error {
 				AmountCents: amountCents,
 				FeeCents:    feeCents,
 				Status:      status,
-				PaidAt:      paidAt,
+			}
+			if status == "PAID" {
+				transactions[i].PaidAt = &paidAt
 			}
 		}
 
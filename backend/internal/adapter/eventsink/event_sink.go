@@ -0,0 +1,38 @@
+package eventsink
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventSinkInterface receives events from the outbox relay. Publish must be safe to repeat: an event whose
+// publication failed on any sink is offered to every sink again.
+type EventSinkInterface interface {
+	Name() string
+	Publish(ctx context.Context, event entity.OutboxEventEntity) error
+}
+
+// Envelope is the JSON form of an event as sinks hand it to consumers.
+type Envelope struct {
+	ID            uuid.UUID       `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+func NewEnvelope(event entity.OutboxEventEntity) Envelope {
+	return Envelope{
+		ID:            event.ID,
+		Type:          event.EventType,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		OccurredAt:    event.CreatedAt,
+		Data:          json.RawMessage(event.Payload),
+	}
+}
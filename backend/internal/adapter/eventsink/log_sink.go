@@ -0,0 +1,32 @@
+package eventsink
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LogSink writes every event to the application log.
+type LogSink struct{}
+
+// Name implements EventSinkInterface.
+func (l *LogSink) Name() string {
+	return "log"
+}
+
+// Publish implements EventSinkInterface.
+func (l *LogSink) Publish(ctx context.Context, event entity.OutboxEventEntity) error {
+	log.Info().
+		Str("event_id", event.ID.String()).
+		Str("event_type", event.EventType).
+		Str("aggregate_type", event.AggregateType).
+		Str("aggregate_id", event.AggregateID).
+		RawJSON("payload", []byte(event.Payload)).
+		Msg("Domain event published")
+	return nil
+}
+
+func NewLogSink() EventSinkInterface {
+	return &LogSink{}
+}
@@ -0,0 +1,65 @@
+package eventsink
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MemoryBus fans events out to in-process subscribers. It is meant for tests and local tooling: a subscriber
+// whose buffer is full misses the event rather than stalling the relay.
+type MemoryBus struct {
+	mu          sync.RWMutex
+	subscribers map[int]chan entity.OutboxEventEntity
+	nextID      int
+}
+
+// Name implements EventSinkInterface.
+func (m *MemoryBus) Name() string {
+	return "memory"
+}
+
+// Publish implements EventSinkInterface.
+func (m *MemoryBus) Publish(ctx context.Context, event entity.OutboxEventEntity) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for id, ch := range m.subscribers {
+		select {
+		case ch <- event:
+		default:
+			log.Warn().Int("subscriber", id).Str("event_id", event.ID.String()).Msg("[MemoryBus-1] Publish: subscriber buffer full, event dropped")
+		}
+	}
+
+	return nil
+}
+
+// Subscribe returns a channel receiving every event published from now on, and a function that ends the subscription.
+func (m *MemoryBus) Subscribe(buffer int) (<-chan entity.OutboxEventEntity, func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id := m.nextID
+	m.nextID++
+	ch := make(chan entity.OutboxEventEntity, buffer)
+	m.subscribers[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			delete(m.subscribers, id)
+			close(ch)
+		})
+	}
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{
+		subscribers: make(map[int]chan entity.OutboxEventEntity),
+	}
+}
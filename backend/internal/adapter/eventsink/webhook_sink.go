@@ -0,0 +1,60 @@
+package eventsink
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookSink POSTs every event as an Envelope to a single URL. Any non-2xx response counts as a failure.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// Name implements EventSinkInterface.
+func (w *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// Publish implements EventSinkInterface.
+func (w *WebhookSink) Publish(ctx context.Context, event entity.OutboxEventEntity) error {
+
+	body, err := json.Marshal(NewEnvelope(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", event.ID.String())
+	req.Header.Set("X-Event-Type", event.EventType)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func NewWebhookSink(url string, timeout time.Duration) EventSinkInterface {
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
@@ -0,0 +1,176 @@
+package gateway
+
+import (
+	"backend-service/config"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// PaymentResultHandler applies a gateway result. A non-nil error asks the gateway to deliver the result again later.
+type PaymentResultHandler func(ctx context.Context, result entity.PaymentResultEntity) error
+
+type PaymentGatewayInterface interface {
+	CreateIntent(ctx context.Context, orderID uuid.UUID, amount entity.Money) (*entity.PaymentIntentEntity, error)
+	CancelIntent(ctx context.Context, paymentIntentID string) error
+	OnResult(handler PaymentResultHandler)
+}
+
+// PaymentSimulatorInterface is a gateway whose results can be forced, for local development and demos.
+type PaymentSimulatorInterface interface {
+	PaymentGatewayInterface
+	Settle(ctx context.Context, paymentIntentID string, status string, reason *string) error
+}
+
+const (
+	simulatorDeliveryAttempts = 5
+	simulatorRetryBackoff     = time.Second
+)
+
+// PaymentSimulator stands in for a real payment gateway. Unless manual settlement is configured every intent is
+// settled after a delay, failing at the configured rate, and the result is delivered to the registered handler
+// with retries the way a gateway retries its webhooks.
+type PaymentSimulator struct {
+	mu          sync.Mutex
+	intents     map[string]*entity.PaymentIntentEntity
+	handler     PaymentResultHandler
+	delay       time.Duration
+	failureRate float64
+	manual      bool
+}
+
+// OnResult implements PaymentGatewayInterface.
+func (p *PaymentSimulator) OnResult(handler PaymentResultHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handler = handler
+}
+
+// CreateIntent implements PaymentGatewayInterface.
+func (p *PaymentSimulator) CreateIntent(ctx context.Context, orderID uuid.UUID, amount entity.Money) (*entity.PaymentIntentEntity, error) {
+	intent := &entity.PaymentIntentEntity{
+		ID:        "pi_sim_" + strings.ReplaceAll(uuid.NewString(), "-", ""),
+		OrderID:   orderID,
+		Amount:    amount,
+		Status:    "REQUIRES_PAYMENT",
+		CreatedAt: time.Now(),
+	}
+
+	p.mu.Lock()
+	p.intents[intent.ID] = intent
+	p.mu.Unlock()
+
+	if !p.manual {
+		time.AfterFunc(p.delay, func() {
+			status, reason := "PAID", (*string)(nil)
+			if rand.Float64() < p.failureRate {
+				declined := "card declined (simulated)"
+				status, reason = "FAILED", &declined
+			}
+
+			err := p.Settle(context.Background(), intent.ID, status, reason)
+			if err != nil && !errors.Is(err, errs.ErrPaymentIntentNotFound) {
+				log.Error().Err(err).Str("payment_intent_id", intent.ID).Msg("[PaymentSimulator-1] CreateIntent: failed to settle simulated payment")
+			}
+		})
+	}
+
+	created := *intent
+	return &created, nil
+}
+
+// CancelIntent implements PaymentGatewayInterface.
+// A cancelled intent is forgotten, so a pending simulated settlement for it never fires.
+func (p *PaymentSimulator) CancelIntent(ctx context.Context, paymentIntentID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	intent, ok := p.intents[paymentIntentID]
+	if !ok {
+		return errs.ErrPaymentIntentNotFound
+	}
+	if intent.Status != "REQUIRES_PAYMENT" {
+		return errs.ErrPaymentIntentAlreadySettled
+	}
+
+	delete(p.intents, paymentIntentID)
+	return nil
+}
+
+// Settle implements PaymentSimulatorInterface.
+// It returns the first delivery error; failed deliveries are retried in the background.
+func (p *PaymentSimulator) Settle(ctx context.Context, paymentIntentID string, status string, reason *string) error {
+	p.mu.Lock()
+	intent, ok := p.intents[paymentIntentID]
+	if !ok {
+		p.mu.Unlock()
+		return errs.ErrPaymentIntentNotFound
+	}
+	if intent.Status != "REQUIRES_PAYMENT" {
+		p.mu.Unlock()
+		return errs.ErrPaymentIntentAlreadySettled
+	}
+	intent.Status = status
+	p.mu.Unlock()
+
+	result := entity.PaymentResultEntity{
+		PaymentIntentID: paymentIntentID,
+		Status:          status,
+		FailureReason:   reason,
+		OccurredAt:      time.Now(),
+	}
+
+	return p.deliver(ctx, result, 1)
+}
+
+func (p *PaymentSimulator) deliver(ctx context.Context, result entity.PaymentResultEntity, attempt int) error {
+	p.mu.Lock()
+	handler := p.handler
+	p.mu.Unlock()
+
+	var err error
+	if handler != nil {
+		err = handler(ctx, result)
+	}
+
+	if err == nil || attempt >= simulatorDeliveryAttempts {
+		if err != nil {
+			log.Error().Err(err).Str("payment_intent_id", result.PaymentIntentID).Int("attempt", attempt).Msg("[PaymentSimulator-2] deliver: giving up on payment result")
+		}
+		p.mu.Lock()
+		delete(p.intents, result.PaymentIntentID)
+		p.mu.Unlock()
+		return err
+	}
+
+	log.Warn().Err(err).Str("payment_intent_id", result.PaymentIntentID).Int("attempt", attempt).Msg("[PaymentSimulator-3] deliver: payment result not accepted, retrying")
+	time.AfterFunc(simulatorRetryBackoff*time.Duration(1<<(attempt-1)), func() {
+		_ = p.deliver(context.Background(), result, attempt+1)
+	})
+
+	return err
+}
+
+func NewPaymentSimulator(cfg *config.Config) PaymentSimulatorInterface {
+
+	delay := 3 * time.Second
+	if cfg.Payments.SimulatorDelay > 0 {
+		delay = cfg.Payments.SimulatorDelay
+	}
+
+	return &PaymentSimulator{
+		intents:     make(map[string]*entity.PaymentIntentEntity),
+		delay:       delay,
+		failureRate: cfg.Payments.SimulatorFailureRate,
+		manual:      cfg.Payments.SimulatorManualSettle,
+	}
+}
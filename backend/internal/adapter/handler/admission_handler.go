@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"backend-service/internal/adapter/handler/request"
+	"backend-service/internal/adapter/handler/response"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/service"
+	v "backend-service/pkg/validator"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// QueueTokenHeader carries the admitted waiting room token on order and checkout requests.
+const QueueTokenHeader = "X-Queue-Token"
+
+type AdmissionHandlerInterface interface {
+	Enqueue(c *gin.Context)
+	GetStatus(c *gin.Context)
+}
+
+type AdmissionHandler struct {
+	admissionService service.AdmissionServiceInterface
+	validator        *v.Validator
+}
+
+// GetStatus implements AdmissionHandlerInterface.
+func (a *AdmissionHandler) GetStatus(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	if a.admissionService == nil {
+		c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, errs.ErrQueueDisabled.Error()))
+		return
+	}
+
+	token, err := uuid.Parse(c.Param("token"))
+	if err != nil {
+		log.Error().Err(err).Msg("[AdmissionHandler-1] GetStatus: Queue token must be a valid UUID")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "queue token must be a valid UUID"))
+		return
+	}
+
+	admission, err := a.admissionService.GetStatus(ctx, token)
+	if err != nil {
+		log.Error().Err(err).Msg("[AdmissionHandler-2] GetStatus")
+		if errors.Is(err, errs.ErrQueueTokenNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", toAdmissionResponse(*admission)))
+}
+
+// Enqueue implements AdmissionHandlerInterface.
+func (a *AdmissionHandler) Enqueue(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		req = request.EnqueueRequest{}
+	)
+
+	if a.admissionService == nil {
+		c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, errs.ErrQueueDisabled.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("[AdmissionHandler-3] Enqueue")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := a.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msg("[AdmissionHandler-4] Enqueue")
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return
+	}
+
+	admission, err := a.admissionService.Enqueue(ctx, req.BuyerID)
+	if err != nil {
+		log.Error().Err(err).Msg("[AdmissionHandler-5] Enqueue")
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.ResponseSuccess(http.StatusAccepted, "queued", toAdmissionResponse(*admission)))
+}
+
+// queueTokenFromHeader returns the token sent in QueueTokenHeader, or nil when the header is absent.
+func queueTokenFromHeader(c *gin.Context) (*uuid.UUID, error) {
+	raw := c.GetHeader(QueueTokenHeader)
+	if raw == "" {
+		return nil, nil
+	}
+
+	token, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, errors.New(QueueTokenHeader + " must be a valid UUID")
+	}
+
+	return &token, nil
+}
+
+func toAdmissionResponse(admission entity.AdmissionTokenEntity) response.AdmissionResponse {
+	return response.AdmissionResponse{
+		Token:      admission.Token,
+		BuyerID:    admission.BuyerID,
+		Status:     admission.Status,
+		Position:   admission.Position,
+		AdmittedAt: admission.AdmittedAt,
+		ExpiresAt:  admission.ExpiresAt,
+	}
+}
+
+func NewAdmissionHandler(admissionService service.AdmissionServiceInterface, validator *v.Validator) AdmissionHandlerInterface {
+	return &AdmissionHandler{admissionService: admissionService, validator: validator}
+}
@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"backend-service/internal/adapter/handler/request"
+	"backend-service/internal/adapter/handler/response"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/service"
+	v "backend-service/pkg/validator"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type CartHandlerInterface interface {
+	GetCart(c *gin.Context)
+	SetItem(c *gin.Context)
+	RemoveItem(c *gin.Context)
+	Checkout(c *gin.Context)
+}
+
+type CartHandler struct {
+	cartService service.CartServiceInterface
+	validator   *v.Validator
+}
+
+// Checkout implements CartHandlerInterface.
+func (h *CartHandler) Checkout(c *gin.Context) {
+
+	var (
+		ctx     = c.Request.Context()
+		req     = request.CheckoutRequest{}
+		buyerID = c.Param("buyerID")
+	)
+
+	queueToken, err := queueTokenFromHeader(c)
+	if err != nil {
+		log.Error().Err(err).Msg("[CartHandler-9] Checkout")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	// The body is optional; an empty checkout request orders the cart without a promotion.
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		log.Error().Err(err).Msg("[CartHandler-10] Checkout")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	order, err := h.cartService.Checkout(ctx, entity.OrderEntity{
+		BuyerID:    buyerID,
+		QueueToken: queueToken,
+		PromoCode:  req.PromoCode,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[CartHandler-1] Checkout")
+		if errors.Is(err, errs.ErrCartEmpty) {
+			c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrOutOfStock) {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPurchaseLimitExceeded) {
+			c.JSON(http.StatusForbidden, response.ResponseError(http.StatusForbidden, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrSaleNotStarted) || errors.Is(err, errs.ErrSaleEnded) {
+			c.JSON(http.StatusForbidden, response.ResponseError(http.StatusForbidden, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrQueueTokenRequired) || errors.Is(err, errs.ErrQueueTokenNotAdmitted) {
+			c.JSON(http.StatusForbidden, response.ResponseError(http.StatusForbidden, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrQueueTokenNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrQueueTokenExpired) {
+			c.JSON(http.StatusGone, response.ResponseError(http.StatusGone, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPromotionNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPromotionNotActive) || errors.Is(err, errs.ErrPromotionNotApplicable) {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPromotionExhausted) {
+			c.JSON(http.StatusConflict, response.ResponseError(http.StatusConflict, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPromotionBuyerLimitReached) {
+			c.JSON(http.StatusForbidden, response.ResponseError(http.StatusForbidden, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrCurrencyMismatch) || errors.Is(err, errs.ErrUnsupportedCurrency) {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, response.ResponseSuccess(http.StatusCreated, "success", toCreateOrderResponse(*order)))
+}
+
+// RemoveItem implements CartHandlerInterface.
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+
+	var (
+		ctx     = c.Request.Context()
+		buyerID = c.Param("buyerID")
+	)
+
+	productID, err := strconv.ParseUint(c.Param("productID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Msg("[CartHandler-2] RemoveItem: Product ID must be a valid number")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "product ID must be a valid number"))
+		return
+	}
+
+	if err := h.cartService.RemoveItem(ctx, buyerID, uint(productID)); err != nil {
+		log.Error().Err(err).Msg("[CartHandler-3] RemoveItem")
+		if errors.Is(err, errs.ErrCartItemNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "cart item removed", nil))
+}
+
+// SetItem implements CartHandlerInterface.
+func (h *CartHandler) SetItem(c *gin.Context) {
+
+	var (
+		ctx     = c.Request.Context()
+		req     = request.SetCartItemRequest{}
+		buyerID = c.Param("buyerID")
+	)
+
+	productID, err := strconv.ParseUint(c.Param("productID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Msg("[CartHandler-4] SetItem: Product ID must be a valid number")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "product ID must be a valid number"))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("[CartHandler-5] SetItem")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msg("[CartHandler-6] SetItem")
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return
+	}
+
+	err = h.cartService.SetItem(ctx, entity.CartItemEntity{
+		BuyerID:   buyerID,
+		ProductID: uint(productID),
+		Quantity:  req.Quantity,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[CartHandler-7] SetItem")
+		if errors.Is(err, errs.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "cart item saved", nil))
+}
+
+// GetCart implements CartHandlerInterface.
+func (h *CartHandler) GetCart(c *gin.Context) {
+
+	var (
+		ctx     = c.Request.Context()
+		buyerID = c.Param("buyerID")
+		res     = response.CartResponse{BuyerID: buyerID}
+	)
+
+	items, err := h.cartService.GetCart(ctx, buyerID)
+	if err != nil {
+		log.Error().Err(err).Msg("[CartHandler-8] GetCart")
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	// Items priced in different currencies are totalled separately rather than summed.
+	totals := make(map[string]entity.Money)
+	currencies := []string{}
+
+	res.Items = make([]response.CartItemResponse, len(items))
+	for i, item := range items {
+		total := item.Product.Price.Multiply(int64(item.Quantity))
+		res.Items[i] = response.CartItemResponse{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			Total:     toMoneyResponse(total),
+			Product: &response.ProductDetails{
+				ID:    item.Product.ID,
+				Name:  item.Product.Name,
+				Price: toMoneyResponse(item.Product.Price),
+			},
+		}
+
+		sum, ok := totals[total.Currency]
+		if !ok {
+			currencies = append(currencies, total.Currency)
+			sum = entity.NewMoney(0, total.Currency)
+		}
+		totals[total.Currency], _ = sum.Add(total)
+	}
+
+	res.Totals = make([]response.Money, len(currencies))
+	for i, currency := range currencies {
+		res.Totals[i] = toMoneyResponse(totals[currency])
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+}
+
+func NewCartHandler(cartService service.CartServiceInterface, validator *v.Validator) CartHandlerInterface {
+	return &CartHandler{cartService: cartService, validator: validator}
+}
@@ -3,22 +3,33 @@ package handler
 import (
 	"backend-service/internal/adapter/handler/request"
 	"backend-service/internal/adapter/handler/response"
+	"backend-service/internal/core/domain/entity"
 	errs "backend-service/internal/core/domain/error"
 	"backend-service/internal/core/service"
 	v "backend-service/pkg/validator"
+	"encoding/json"
 	"errors"
+	"io"
+	"math"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
+	"golang.org/x/net/websocket"
 )
 
 type JobHandlerInterface interface {
 	CreateSettlementJob(c *gin.Context)
 	GetJob(c *gin.Context)
+	GetQueue(c *gin.Context)
 	CancelJob(c *gin.Context)
+	PauseJob(c *gin.Context)
+	ResumeJob(c *gin.Context)
+	StreamJobEvents(c *gin.Context)
+	JobDashboard(c *gin.Context)
 }
 
 type JobHandler struct {
@@ -26,6 +37,288 @@ type JobHandler struct {
 	validator  *v.Validator
 }
 
+// JobDashboard implements JobHandlerInterface.
+// It upgrades to a WebSocket over which a dashboard subscribes to jobs by ID or by type and cancels them; see
+// request.JobDashboardCommand and response.JobDashboardMessage for the messages exchanged.
+func (j *JobHandler) JobDashboard(c *gin.Context) {
+	// websocket.Handler would reject clients that send no Origin header; the dashboard API has no origin policy.
+	server := websocket.Server{Handler: j.serveJobDashboard}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// dashboardInput is one message read from a dashboard connection, or the reason it could not be decoded.
+type dashboardInput struct {
+	command request.JobDashboardCommand
+	err     error
+}
+
+func (j *JobHandler) serveJobDashboard(ws *websocket.Conn) {
+	defer ws.Close()
+
+	var (
+		ctx    = ws.Request().Context()
+		jobIDs = map[uuid.UUID]bool{}
+		types  = map[string]bool{}
+		inputs = make(chan dashboardInput)
+		quit   = make(chan struct{})
+	)
+	defer close(quit)
+
+	updates, stop := j.jobService.WatchJobs(jobDashboardBuffer)
+	defer stop()
+
+	go func() {
+		defer close(inputs)
+		for {
+			var input dashboardInput
+			if err := websocket.JSON.Receive(ws, &input.command); err != nil {
+				var syntaxErr *json.SyntaxError
+				var typeErr *json.UnmarshalTypeError
+				if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) {
+					return
+				}
+				input.err = err
+			}
+
+			select {
+			case inputs <- input:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	send := func(msg response.JobDashboardMessage) bool {
+		ws.SetWriteDeadline(time.Now().Add(jobDashboardWriteTimeout))
+		if err := websocket.JSON.Send(ws, msg); err != nil {
+			log.Warn().Err(err).Msg("[JobHandler-3] JobDashboard: failed to write to dashboard")
+			return false
+		}
+		return true
+	}
+
+	subscriptions := func() response.JobDashboardMessage {
+		msg := response.JobDashboardMessage{Event: "subscribed", JobIDs: []uuid.UUID{}, Types: []string{}}
+		for id := range jobIDs {
+			msg.JobIDs = append(msg.JobIDs, id)
+		}
+		for jobType := range types {
+			msg.Types = append(msg.Types, jobType)
+		}
+		return msg
+	}
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			if !jobIDs[update.Job.ID] && !types[update.Job.Type] {
+				continue
+			}
+			job := toJobProgressResponse(update.Job)
+			if !send(response.JobDashboardMessage{Event: update.Event, Job: &job}) {
+				return
+			}
+
+		case input, ok := <-inputs:
+			if !ok {
+				return
+			}
+
+			if input.err != nil {
+				if !send(response.JobDashboardMessage{Event: "error", Error: input.err.Error()}) {
+					return
+				}
+				continue
+			}
+
+			cmd := input.command
+			if err := j.validator.Validate(cmd); err != nil {
+				var msg interface{} = err.Error()
+				if ve, ok := err.(v.ValidationError); ok {
+					msg = ve.Errors
+				}
+				if !send(response.JobDashboardMessage{Event: "error", Error: msg}) {
+					return
+				}
+				continue
+			}
+
+			switch cmd.Action {
+			case "subscribe":
+				for _, jobType := range cmd.Types {
+					types[strings.ToUpper(jobType)] = true
+				}
+				for _, id := range cmd.JobIDs {
+					// The current state goes out first so the dashboard can draw the job before its next update.
+					job, err := j.jobService.GetJob(ctx, id)
+					if err != nil {
+						jobID := id
+						if !send(response.JobDashboardMessage{Event: "error", JobID: &jobID, Error: err.Error()}) {
+							return
+						}
+						continue
+					}
+					jobIDs[id] = true
+					snapshot := toJobProgressResponse(*job)
+					if !send(response.JobDashboardMessage{Event: jobEventName(*job), Job: &snapshot}) {
+						return
+					}
+				}
+				if !send(subscriptions()) {
+					return
+				}
+
+			case "unsubscribe":
+				for _, jobType := range cmd.Types {
+					delete(types, strings.ToUpper(jobType))
+				}
+				for _, id := range cmd.JobIDs {
+					delete(jobIDs, id)
+				}
+				if !send(subscriptions()) {
+					return
+				}
+
+			case "cancel":
+				msg := response.JobDashboardMessage{Event: "cancel_requested", JobID: cmd.JobID}
+				if err := j.jobService.CancelJob(ctx, *cmd.JobID); err != nil {
+					log.Error().Err(err).Str("job_id", cmd.JobID.String()).Msg("[JobHandler-4] JobDashboard: failed to cancel job")
+					msg.Event = "error"
+					msg.Error = err.Error()
+				}
+				if !send(msg) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// StreamJobEvents implements JobHandlerInterface.
+// It sends the job's current state, then one event per lifecycle update (queued, started, progress, completed,
+// failed, cancelled), and ends the stream once the job reaches a terminal state.
+func (j *JobHandler) StreamJobEvents(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+	)
+
+	jobID, err := uuid.Parse(c.Param("jobID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[JobHandler-1] StreamJobEvents: Job ID must be a valid UUID")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "Job ID must be a valid UUID"))
+		return
+	}
+
+	job, updates, unsubscribe, err := j.jobService.SubscribeJob(ctx, jobID)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobHandler-2] StreamJobEvents: failed to subscribe to job")
+		if errors.Is(err, errs.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+			return
+		}
+	}
+	defer unsubscribe()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent(jobEventName(*job), toJobProgressResponse(*job))
+	c.Writer.Flush()
+	if job.IsTerminal() {
+		return
+	}
+
+	heartbeat := time.NewTicker(jobEventsHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case update, ok := <-updates:
+			if !ok {
+				return false
+			}
+			c.SSEvent(update.Event, toJobProgressResponse(update.Job))
+			return !update.Job.IsTerminal()
+		case <-heartbeat.C:
+			// A comment line keeps proxies from closing an idle stream while a large batch is being read.
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
+}
+
+// ResumeJob implements JobHandlerInterface.
+func (j *JobHandler) ResumeJob(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	jobID, err := uuid.Parse(c.Param("jobID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[JobHandler-6] ResumeJob: Job ID must be a valid UUID")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "Job ID must be a valid UUID"))
+		return
+	}
+
+	job, err := j.jobService.ResumeJob(ctx, jobID)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobHandler-7] ResumeJob: failed to resume job")
+		if errors.Is(err, errs.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrJobCannotBeResumed) {
+			c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusAccepted, response.ResponseSuccess(http.StatusAccepted, "job resumed", response.CreateJobResponse{
+		JobID:  job.ID,
+		Status: job.Status,
+	}))
+}
+
+// PauseJob implements JobHandlerInterface.
+func (j *JobHandler) PauseJob(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	jobID, err := uuid.Parse(c.Param("jobID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[JobHandler-8] PauseJob: Job ID must be a valid UUID")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "Job ID must be a valid UUID"))
+		return
+	}
+
+	if err := j.jobService.PauseJob(ctx, jobID); err != nil {
+		log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobHandler-9] PauseJob: failed to pause job")
+		if errors.Is(err, errs.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrJobCannotBePaused) {
+			c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusAccepted, response.ResponseSuccess(http.StatusAccepted, "job pause requested", nil))
+}
+
 // CancelJob implements JobHandlerInterface.
 func (j *JobHandler) CancelJob(c *gin.Context) {
 	var (
@@ -58,6 +351,50 @@ func (j *JobHandler) CancelJob(c *gin.Context) {
 	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "job cancellation requested", nil))
 }
 
+// GetQueue implements JobHandlerInterface.
+func (j *JobHandler) GetQueue(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	stats, err := j.jobService.GetQueueStats(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[JobHandler-5] GetQueue: failed to get queue stats")
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	res := response.JobQueueResponse{
+		Priorities: make([]response.JobPriorityDepthResponse, len(stats.Priorities)),
+		Types:      make([]response.JobTypeDepthResponse, len(stats.Types)),
+		Watchdog: response.JobWatchdogResponse{
+			TimedOut: stats.Watchdog.TimedOut,
+			Stalled:  stats.Watchdog.Stalled,
+			Requeued: stats.Watchdog.Requeued,
+			Failed:   stats.Watchdog.Failed,
+		},
+	}
+	for i, depth := range stats.Priorities {
+		res.Priorities[i] = response.JobPriorityDepthResponse{
+			Priority:   depth.Priority,
+			Queued:     depth.Queued,
+			QueuedRows: depth.QueuedRows,
+		}
+	}
+	for i, depth := range stats.Types {
+		res.Types[i] = response.JobTypeDepthResponse{
+			Type:    depth.Type,
+			Queued:  depth.Queued,
+			Running: depth.Running,
+		}
+		if depth.Limit > 0 {
+			limit := depth.Limit
+			res.Types[i].Limit = &limit
+		}
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+}
+
 // GetJob implements JobHandlerInterface.
 func (j *JobHandler) GetJob(c *gin.Context) {
 
@@ -87,14 +424,27 @@ func (j *JobHandler) GetJob(c *gin.Context) {
 
 	res.JobID = job.ID
 	res.Status = job.Status
+	res.Priority = job.Priority
 	res.Total = job.Total
 	res.Progress = job.Progress
 	res.Processed = job.Processed
-
-	if job.Status == "COMPLETED" && job.ResultPath != nil {
-		downloadURL := "/downloads/" + strings.TrimSuffix(strings.TrimPrefix(*job.ResultPath, "/tmp/settlements/"), ".csv") + ".csv"
-		res.DownloadURL = &downloadURL
+	res.RowsPerSecond = roundRate(job.Throughput)
+	res.ElapsedSeconds = int64(job.Elapsed(time.Now()).Seconds())
+	res.StartedAt = job.StartedAt
+	res.CompletedAt = job.CompletedAt
+	res.EstimatedStartAt = job.EstimatedStartAt
+	res.EstimatedCompletionAt = job.EstimatedCompletionAt
+	res.Requeues = job.Requeues
+	res.ErrorMessage = job.ErrorMessage
+	if job.Timeout > 0 {
+		timeoutSeconds := int64(job.Timeout.Seconds())
+		res.TimeoutSeconds = &timeoutSeconds
 	}
+	if job.Status == "RUNNING" {
+		res.DeadlineAt = job.DeadlineAt
+	}
+
+	res.DownloadURL = toDownloadURL(*job)
 
 	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
 }
@@ -127,12 +477,25 @@ func (j *JobHandler) CreateSettlementJob(c *gin.Context) {
 		return
 	}
 
-	job, err := j.jobService.CreateSettlementJob(ctx, req.From, req.To)
+	priority := entity.JobPriorityDefault
+	if req.Priority != nil {
+		priority = *req.Priority
+	}
+
+	var timeout time.Duration
+	if req.TimeoutSeconds != nil {
+		timeout = time.Duration(*req.TimeoutSeconds) * time.Second
+	}
+
+	job, created, err := j.jobService.CreateSettlementJob(ctx, req.From, req.To, priority, timeout, req.Force)
 	if err != nil {
 		log.Error().Err(err).Msg("[OrderHandler-3] CreateSettlementJob")
-		if errors.Is(err, errs.ErrInvalidDateRange) {
+		if errors.Is(err, errs.ErrInvalidDateRange) || errors.Is(err, errs.ErrInvalidJobPriority) || errors.Is(err, errs.ErrInvalidJobTimeout) {
 			c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
 			return
+		} else if errors.Is(err, errs.ErrSettlementJobOverlaps) {
+			c.JSON(http.StatusConflict, response.ResponseError(http.StatusConflict, err.Error()))
+			return
 		} else {
 			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
 			return
@@ -142,10 +505,63 @@ func (j *JobHandler) CreateSettlementJob(c *gin.Context) {
 	res.JobID = job.ID
 	res.Status = job.Status
 
+	if !created {
+		res.Existing = true
+		c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "an active job already covers this range", res))
+		return
+	}
+
 	c.JSON(http.StatusAccepted, response.ResponseSuccess(http.StatusAccepted, "success", res))
 
 }
 
+const (
+	jobEventsHeartbeat       = 15 * time.Second
+	jobDashboardBuffer       = 256
+	jobDashboardWriteTimeout = 10 * time.Second
+)
+
+// jobEventName names the event carrying a job snapshot read from the database rather than published by a worker.
+func jobEventName(job entity.JobEntity) string {
+	if job.Status == "RUNNING" {
+		return entity.JobUpdateProgress
+	}
+	return strings.ToLower(job.Status)
+}
+
+func toJobProgressResponse(job entity.JobEntity) response.JobProgressResponse {
+	return response.JobProgressResponse{
+		JobID:                 job.ID,
+		Type:                  job.Type,
+		Status:                job.Status,
+		Priority:              job.Priority,
+		Progress:              job.Progress,
+		Processed:             job.Processed,
+		Total:                 job.Total,
+		RowsPerSecond:         roundRate(job.Throughput),
+		ElapsedSeconds:        int64(job.Elapsed(time.Now()).Seconds()),
+		StartedAt:             job.StartedAt,
+		CompletedAt:           job.CompletedAt,
+		EstimatedStartAt:      job.EstimatedStartAt,
+		EstimatedCompletionAt: job.EstimatedCompletionAt,
+		ErrorMessage:          job.ErrorMessage,
+		DownloadURL:           toDownloadURL(job),
+	}
+}
+
+// roundRate keeps rows per second to one decimal place; more is noise.
+func roundRate(rowsPerSecond float64) float64 {
+	return math.Round(rowsPerSecond*10) / 10
+}
+
+func toDownloadURL(job entity.JobEntity) *string {
+	if job.Status != "COMPLETED" || job.ResultPath == nil {
+		return nil
+	}
+	downloadURL := "/downloads/" + strings.TrimSuffix(strings.TrimPrefix(*job.ResultPath, "/tmp/settlements/"), ".csv") + ".csv"
+	return &downloadURL
+}
+
 func NewJobHandler(jobService service.JobServiceInterface, validator *v.Validator) JobHandlerInterface {
 	return &JobHandler{
 		jobService: jobService,
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"backend-service/internal/adapter/handler/request"
+	"backend-service/internal/adapter/handler/response"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/service"
+	v "backend-service/pkg/validator"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type JobScheduleHandlerInterface interface {
+	CreateSchedule(c *gin.Context)
+	ListSchedules(c *gin.Context)
+	GetSchedule(c *gin.Context)
+	UpdateSchedule(c *gin.Context)
+	DeleteSchedule(c *gin.Context)
+	ListRuns(c *gin.Context)
+}
+
+type JobScheduleHandler struct {
+	scheduleService service.JobScheduleServiceInterface
+	validator       *v.Validator
+}
+
+// ListRuns implements JobScheduleHandlerInterface.
+// ?status=MISSED lists the occurrences that were never fired.
+func (j *JobScheduleHandler) ListRuns(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		req = request.ListJobScheduleRunsRequest{}
+	)
+
+	scheduleID, err := uuid.Parse(c.Param("scheduleID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-1] ListRuns")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "schedule ID must be a valid UUID"))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-2] ListRuns")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := j.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-3] ListRuns")
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 50
+	}
+
+	runs, err := j.scheduleService.ListRuns(ctx, scheduleID, req.Status, req.Limit)
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-4] ListRuns")
+		j.respondError(c, err)
+		return
+	}
+
+	res := make([]response.JobScheduleRunResponse, len(runs))
+	for i, run := range runs {
+		res[i] = response.JobScheduleRunResponse{
+			ID:           run.ID,
+			ScheduleID:   run.ScheduleID,
+			ScheduledFor: run.ScheduledFor,
+			Status:       run.Status,
+			JobID:        run.JobID,
+			Error:        run.Error,
+			CreatedAt:    run.CreatedAt,
+		}
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+}
+
+// DeleteSchedule implements JobScheduleHandlerInterface.
+func (j *JobScheduleHandler) DeleteSchedule(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	scheduleID, err := uuid.Parse(c.Param("scheduleID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-5] DeleteSchedule")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "schedule ID must be a valid UUID"))
+		return
+	}
+
+	if err := j.scheduleService.DeleteSchedule(ctx, scheduleID); err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-6] DeleteSchedule")
+		j.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "schedule deleted", nil))
+}
+
+// UpdateSchedule implements JobScheduleHandlerInterface.
+func (j *JobScheduleHandler) UpdateSchedule(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	scheduleID, err := uuid.Parse(c.Param("scheduleID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-7] UpdateSchedule")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "schedule ID must be a valid UUID"))
+		return
+	}
+
+	schedule, ok := j.bindSchedule(c, "UpdateSchedule")
+	if !ok {
+		return
+	}
+	schedule.ID = scheduleID
+
+	updated, err := j.scheduleService.UpdateSchedule(ctx, schedule)
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-8] UpdateSchedule")
+		j.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", toJobScheduleResponse(*updated)))
+}
+
+// GetSchedule implements JobScheduleHandlerInterface.
+func (j *JobScheduleHandler) GetSchedule(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	scheduleID, err := uuid.Parse(c.Param("scheduleID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-9] GetSchedule")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "schedule ID must be a valid UUID"))
+		return
+	}
+
+	schedule, err := j.scheduleService.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-10] GetSchedule")
+		j.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", toJobScheduleResponse(*schedule)))
+}
+
+// ListSchedules implements JobScheduleHandlerInterface.
+func (j *JobScheduleHandler) ListSchedules(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	schedules, err := j.scheduleService.ListSchedules(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-11] ListSchedules")
+		j.respondError(c, err)
+		return
+	}
+
+	res := make([]response.JobScheduleResponse, len(schedules))
+	for i, schedule := range schedules {
+		res[i] = toJobScheduleResponse(schedule)
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+}
+
+// CreateSchedule implements JobScheduleHandlerInterface.
+func (j *JobScheduleHandler) CreateSchedule(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	schedule, ok := j.bindSchedule(c, "CreateSchedule")
+	if !ok {
+		return
+	}
+
+	created, err := j.scheduleService.CreateSchedule(ctx, schedule)
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleHandler-12] CreateSchedule")
+		j.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.ResponseSuccess(http.StatusCreated, "success", toJobScheduleResponse(*created)))
+}
+
+// bindSchedule binds and validates a JobScheduleRequest, writing the error response itself when it fails.
+func (j *JobScheduleHandler) bindSchedule(c *gin.Context, method string) (entity.JobScheduleEntity, bool) {
+
+	var req request.JobScheduleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msgf("[JobScheduleHandler-13] %s", method)
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return entity.JobScheduleEntity{}, false
+	}
+
+	if err := j.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msgf("[JobScheduleHandler-14] %s", method)
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return entity.JobScheduleEntity{}, false
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return entity.JobScheduleEntity{}, false
+	}
+
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	return entity.JobScheduleEntity{
+		Name:           req.Name,
+		CronExpression: req.CronExpression,
+		Timezone:       req.Timezone,
+		JobType:        req.JobType,
+		DateRange:      req.DateRange,
+		Active:         active,
+	}, true
+}
+
+func (j *JobScheduleHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, errs.ErrJobScheduleNotFound) {
+		c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+	} else if errors.Is(err, errs.ErrInvalidJobSchedule) {
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+	} else {
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+	}
+}
+
+func toJobScheduleResponse(schedule entity.JobScheduleEntity) response.JobScheduleResponse {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	res := response.JobScheduleResponse{
+		ID:             schedule.ID,
+		Name:           schedule.Name,
+		CronExpression: schedule.CronExpression,
+		Timezone:       schedule.Timezone,
+		JobType:        schedule.JobType,
+		DateRange:      schedule.DateRange,
+		Active:         schedule.Active,
+		NextRunAt:      schedule.NextRunAt.In(loc),
+		CreatedAt:      schedule.CreatedAt,
+		UpdatedAt:      schedule.UpdatedAt,
+	}
+
+	if schedule.LastRunAt != nil {
+		lastRunAt := schedule.LastRunAt.In(loc)
+		res.LastRunAt = &lastRunAt
+	}
+
+	return res
+}
+
+func NewJobScheduleHandler(scheduleService service.JobScheduleServiceInterface, validator *v.Validator) JobScheduleHandlerInterface {
+	return &JobScheduleHandler{
+		scheduleService: scheduleService,
+		validator:       validator,
+	}
+}
@@ -8,8 +8,11 @@ import (
 	"backend-service/internal/core/service"
 
 	v "backend-service/pkg/validator"
+	"encoding/base64"
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,18 +22,163 @@ import (
 type OrderHandlerInterface interface {
 	CreateOrder(c *gin.Context)
 	GetOrderByID(c *gin.Context)
+	CancelOrder(c *gin.Context)
+	ListOrders(c *gin.Context)
+	ListBuyerOrders(c *gin.Context)
 }
 type OrderHandler struct {
 	orderService service.OrderServiceInterface
 	validator    *v.Validator
 }
 
+// ListBuyerOrders implements OrderHandlerInterface.
+func (o *OrderHandler) ListBuyerOrders(c *gin.Context) {
+	o.listOrders(c, c.Param("buyerID"))
+}
+
+// ListOrders implements OrderHandlerInterface.
+func (o *OrderHandler) ListOrders(c *gin.Context) {
+	o.listOrders(c, c.Query("buyer_id"))
+}
+
+// listOrders serves one page of orders; a non-empty buyerID restricts the page to that buyer.
+func (o *OrderHandler) listOrders(c *gin.Context, buyerID string) {
+
+	var (
+		ctx = c.Request.Context()
+		req = request.ListOrdersRequest{}
+		res = response.ListOrdersResponse{}
+	)
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		log.Error().Err(err).Msg("[OrderHandler-13] ListOrders")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := o.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msg("[OrderHandler-14] ListOrders")
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return
+	}
+
+	filter := entity.OrderFilterEntity{
+		BuyerID:     buyerID,
+		ProductID:   req.ProductID,
+		Status:      req.Status,
+		CreatedFrom: req.CreatedFrom,
+		CreatedTo:   req.CreatedTo,
+		Limit:       20,
+	}
+	if req.Limit > 0 {
+		filter.Limit = req.Limit
+	}
+
+	if req.Cursor != "" {
+		cursor, err := decodeOrderCursor(req.Cursor)
+		if err != nil {
+			log.Error().Err(err).Msg("[OrderHandler-15] ListOrders")
+			c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "cursor is invalid"))
+			return
+		}
+		filter.After = cursor
+	}
+
+	page, err := o.orderService.ListOrders(ctx, filter)
+	if err != nil {
+		log.Error().Err(err).Msg("[OrderHandler-16] ListOrders")
+		if errors.Is(err, errs.ErrInvalidDateRange) {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	res.Orders = make([]response.GetOrderByIDResponse, len(page.Orders))
+	for i, order := range page.Orders {
+		res.Orders[i] = toOrderResponse(order)
+	}
+	if page.NextCursor != nil {
+		res.NextCursor = encodeOrderCursor(*page.NextCursor)
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+
+}
+
+// CancelOrder implements OrderHandlerInterface.
+func (o *OrderHandler) CancelOrder(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		req = request.CancelOrderRequest{}
+		res = response.CancelOrderResponse{}
+	)
+
+	orderID, err := uuid.Parse(c.Param("orderID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[OrderHandler-6] CancelOrder")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "order ID must be a valid UUID"))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("[OrderHandler-7] CancelOrder")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := o.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msg("[OrderHandler-8] CancelOrder")
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return
+	}
+
+	order, err := o.orderService.CancelOrder(ctx, orderID, req.Reason)
+	if err != nil {
+		log.Error().Err(err).Msg("[OrderHandler-9] CancelOrder")
+		if errors.Is(err, errs.ErrOrderNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrOrderAlreadyCancelled) {
+			c.JSON(http.StatusConflict, response.ResponseError(http.StatusConflict, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrOrderCannotBeCancelled) {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+			return
+		}
+	}
+
+	res.OrderID = order.ID
+	res.Status = order.Status
+	res.CancelReason = order.CancelReason
+	res.CancelledAt = order.CancelledAt
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+
+}
+
 // GetOrder implements OrderHandlerInterface.
 func (o *OrderHandler) GetOrderByID(c *gin.Context) {
 
 	var (
 		ctx = c.Request.Context()
-		res = response.GetOrderByIDResponse{}
 	)
 
 	orderID, err := uuid.Parse(c.Param("orderID"))
@@ -51,22 +199,7 @@ func (o *OrderHandler) GetOrderByID(c *gin.Context) {
 		return
 	}
 
-	res.OrderID = order.ID
-	res.ProductID = order.ProductID
-	res.BuyerID = order.BuyerID
-	res.Quantity = order.Quantity
-	res.TotalCents = order.TotalCents
-	res.Status = order.Status
-
-	if order.Product != nil {
-		res.Product = &response.ProductDetails{
-			ID:         order.Product.ID,
-			Name:       order.Product.Name,
-			PriceCents: order.Product.PriceCents,
-		}
-	}
-
-	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", toOrderResponse(*order)))
 
 }
 
@@ -76,7 +209,6 @@ func (o *OrderHandler) CreateOrder(c *gin.Context) {
 	var (
 		req = request.CreateOrderRequest{}
 		ctx = c.Request.Context()
-		res = response.CreateOrderResponse{}
 	)
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -97,10 +229,24 @@ func (o *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	queueToken, err := queueTokenFromHeader(c)
+	if err != nil {
+		log.Error().Err(err).Msg("[OrderHandler-12] Create")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
 	request := entity.OrderEntity{
-		ProductID: req.ProductID,
-		BuyerID:   req.BuyerID,
-		Quantity:  req.Quantity,
+		BuyerID:    req.BuyerID,
+		Items:      make([]entity.OrderItemEntity, len(req.Items)),
+		QueueToken: queueToken,
+		PromoCode:  req.PromoCode,
+	}
+	for i, item := range req.Items {
+		request.Items[i] = entity.OrderItemEntity{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
 	}
 
 	order, err := o.orderService.CreateOrder(ctx, request)
@@ -112,17 +258,140 @@ func (o *OrderHandler) CreateOrder(c *gin.Context) {
 		} else if errors.Is(err, errs.ErrOutOfStock) {
 			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
 			return
+		} else if errors.Is(err, errs.ErrPurchaseLimitExceeded) {
+			c.JSON(http.StatusForbidden, response.ResponseError(http.StatusForbidden, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrSaleNotStarted) || errors.Is(err, errs.ErrSaleEnded) {
+			c.JSON(http.StatusForbidden, response.ResponseError(http.StatusForbidden, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrQueueTokenRequired) || errors.Is(err, errs.ErrQueueTokenNotAdmitted) {
+			c.JSON(http.StatusForbidden, response.ResponseError(http.StatusForbidden, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrQueueTokenNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrQueueTokenExpired) {
+			c.JSON(http.StatusGone, response.ResponseError(http.StatusGone, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPromotionNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPromotionNotActive) || errors.Is(err, errs.ErrPromotionNotApplicable) {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPromotionExhausted) {
+			c.JSON(http.StatusConflict, response.ResponseError(http.StatusConflict, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPromotionBuyerLimitReached) {
+			c.JSON(http.StatusForbidden, response.ResponseError(http.StatusForbidden, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrCurrencyMismatch) || errors.Is(err, errs.ErrUnsupportedCurrency) {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+			return
 		} else {
 			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
 			return
 		}
 	}
 
-	res.OrderID = order.ID
-	res.Status = order.Status
+	c.JSON(http.StatusCreated, response.ResponseSuccess(http.StatusCreated, "success", toCreateOrderResponse(*order)))
+
+}
 
-	c.JSON(http.StatusCreated, response.ResponseSuccess(http.StatusCreated, "success", res))
+func toCreateOrderResponse(order entity.OrderEntity) response.CreateOrderResponse {
+	return response.CreateOrderResponse{
+		OrderID:         order.ID,
+		Status:          order.Status,
+		Gross:           toMoneyResponse(order.Gross),
+		Discount:        toMoneyResponse(order.Discount),
+		Tax:             toMoneyResponse(order.Tax),
+		TaxInclusive:    order.TaxInclusive,
+		Fee:             toMoneyResponse(order.Fee),
+		Total:           toMoneyResponse(order.Total),
+		ExpiresAt:       order.ExpiresAt,
+		PaymentIntentID: order.PaymentIntentID,
+	}
+}
 
+func toMoneyResponse(money entity.Money) response.Money {
+	return response.Money{
+		Amount:   money.Amount,
+		Currency: money.Currency,
+		Display:  money.Decimal(),
+	}
+}
+
+func toOrderResponse(order entity.OrderEntity) response.GetOrderByIDResponse {
+	return response.GetOrderByIDResponse{
+		OrderID:         order.ID,
+		BuyerID:         order.BuyerID,
+		Currency:        order.Currency,
+		Gross:           toMoneyResponse(order.Gross),
+		Discount:        toMoneyResponse(order.Discount),
+		Tax:             toMoneyResponse(order.Tax),
+		TaxInclusive:    order.TaxInclusive,
+		Fee:             toMoneyResponse(order.Fee),
+		Total:           toMoneyResponse(order.Total),
+		Status:          order.Status,
+		PaymentIntentID: order.PaymentIntentID,
+		CancelReason:    order.CancelReason,
+		CancelledAt:     order.CancelledAt,
+		ExpiresAt:       order.ExpiresAt,
+		CreatedAt:       order.CreatedAt,
+		Items:           toOrderItemResponses(order.Items),
+	}
+}
+
+// encodeOrderCursor renders a cursor as an opaque URL-safe token.
+func encodeOrderCursor(cursor entity.OrderCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeOrderCursor(token string) (*entity.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found {
+		return nil, errors.New("cursor is malformed")
+	}
+
+	cursor := entity.OrderCursor{}
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, err
+	}
+	if cursor.ID, err = uuid.Parse(id); err != nil {
+		return nil, err
+	}
+
+	return &cursor, nil
+}
+
+func toOrderItemResponses(items []entity.OrderItemEntity) []response.OrderItemResponse {
+	res := make([]response.OrderItemResponse, len(items))
+	for i, item := range items {
+		res[i] = response.OrderItemResponse{
+			ProductID:  item.ProductID,
+			Quantity:   item.Quantity,
+			UnitPrice:  toMoneyResponse(item.UnitPrice),
+			Total:      toMoneyResponse(item.Total),
+			Discount:   toMoneyResponse(item.Discount),
+			TaxRateBps: item.TaxRateBps,
+			Tax:        toMoneyResponse(item.Tax),
+		}
+
+		if item.Product != nil {
+			res[i].Product = &response.ProductDetails{
+				ID:    item.Product.ID,
+				Name:  item.Product.Name,
+				Price: toMoneyResponse(item.Product.Price),
+			}
+		}
+	}
+	return res
 }
 
 func NewOrderHandler(orderService service.OrderServiceInterface, validator *v.Validator) OrderHandlerInterface {
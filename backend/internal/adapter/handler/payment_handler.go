@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"backend-service/internal/adapter/handler/request"
+	"backend-service/internal/adapter/handler/response"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/service"
+	v "backend-service/pkg/validator"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type PaymentHandlerInterface interface {
+	SimulatePayment(c *gin.Context)
+}
+
+type PaymentHandler struct {
+	paymentService service.PaymentServiceInterface
+	validator      *v.Validator
+}
+
+// SimulatePayment implements PaymentHandlerInterface.
+// It plays the gateway's part for a pending intent, settling it as PAID or FAILED.
+func (p *PaymentHandler) SimulatePayment(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		req = request.SimulatePaymentRequest{}
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("[PaymentHandler-1] SimulatePayment")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := p.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msg("[PaymentHandler-2] SimulatePayment")
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return
+	}
+
+	intentID := c.Param("intentID")
+	if err := p.paymentService.SimulatePayment(ctx, intentID, req.Status, req.Reason); err != nil {
+		log.Error().Err(err).Msg("[PaymentHandler-3] SimulatePayment")
+		if errors.Is(err, errs.ErrPaymentIntentNotFound) || errors.Is(err, errs.ErrPaymentSimulatorDisabled) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPaymentIntentAlreadySettled) {
+			c.JSON(http.StatusConflict, response.ResponseError(http.StatusConflict, err.Error()))
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", response.PaymentResultResponse{
+		PaymentIntentID: intentID,
+		Status:          req.Status,
+		FailureReason:   req.Reason,
+	}))
+
+}
+
+func NewPaymentHandler(paymentService service.PaymentServiceInterface, validator *v.Validator) PaymentHandlerInterface {
+	return &PaymentHandler{
+		paymentService: paymentService,
+		validator:      validator,
+	}
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"backend-service/internal/adapter/handler/request"
+	"backend-service/internal/adapter/handler/response"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/service"
+	v "backend-service/pkg/validator"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type ProductHandlerInterface interface {
+	GetStockHistory(c *gin.Context)
+	AdjustStock(c *gin.Context)
+	SetPurchaseLimit(c *gin.Context)
+	GetProduct(c *gin.Context)
+	SetSaleWindow(c *gin.Context)
+}
+
+type ProductHandler struct {
+	productService service.ProductServiceInterface
+	validator      *v.Validator
+}
+
+// GetProduct implements ProductHandlerInterface.
+func (p *ProductHandler) GetProduct(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		now = time.Now()
+	)
+
+	productID, err := strconv.ParseUint(c.Param("productID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-11] GetProduct: Product ID must be a valid number")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "product ID must be a valid number"))
+		return
+	}
+
+	product, err := p.productService.GetProduct(ctx, uint(productID))
+	if err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-12] GetProduct")
+		if errors.Is(err, errs.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	res := response.ProductResponse{
+		ID:           product.ID,
+		Name:         product.Name,
+		MerchantID:   product.MerchantID,
+		Category:     product.Category,
+		Price:        toMoneyResponse(product.Price),
+		Stock:        product.Stock,
+		MaxPerBuyer:  product.MaxPerBuyer,
+		SaleStartsAt: product.SaleStartsAt,
+		SaleEndsAt:   product.SaleEndsAt,
+		SaleState:    product.SaleState(now),
+		ServerTime:   now,
+	}
+
+	switch res.SaleState {
+	case "UPCOMING":
+		startsIn := int64(product.SaleStartsAt.Sub(now).Seconds())
+		res.StartsInSeconds = &startsIn
+	case "LIVE":
+		if product.SaleEndsAt != nil {
+			endsIn := int64(product.SaleEndsAt.Sub(now).Seconds())
+			res.EndsInSeconds = &endsIn
+		}
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+}
+
+// SetSaleWindow implements ProductHandlerInterface.
+func (p *ProductHandler) SetSaleWindow(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		req = request.SetSaleWindowRequest{}
+	)
+
+	productID, err := strconv.ParseUint(c.Param("productID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-13] SetSaleWindow: Product ID must be a valid number")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "product ID must be a valid number"))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-14] SetSaleWindow")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := p.productService.SetSaleWindow(ctx, uint(productID), req.SaleStartsAt, req.SaleEndsAt); err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-15] SetSaleWindow")
+		if errors.Is(err, errs.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrInvalidSaleWindow) {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "sale window updated", nil))
+}
+
+// SetPurchaseLimit implements ProductHandlerInterface.
+func (p *ProductHandler) SetPurchaseLimit(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		req = request.SetPurchaseLimitRequest{}
+	)
+
+	productID, err := strconv.ParseUint(c.Param("productID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-7] SetPurchaseLimit: Product ID must be a valid number")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "product ID must be a valid number"))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-8] SetPurchaseLimit")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := p.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-9] SetPurchaseLimit")
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return
+	}
+
+	if err := p.productService.SetPurchaseLimit(ctx, uint(productID), req.MaxPerBuyer); err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-10] SetPurchaseLimit")
+		if errors.Is(err, errs.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "purchase limit updated", nil))
+}
+
+// AdjustStock implements ProductHandlerInterface.
+func (p *ProductHandler) AdjustStock(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		req = request.AdjustStockRequest{}
+	)
+
+	productID, err := strconv.ParseUint(c.Param("productID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-1] AdjustStock: Product ID must be a valid number")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "product ID must be a valid number"))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-2] AdjustStock")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := p.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-3] AdjustStock")
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return
+	}
+
+	movement, err := p.productService.AdjustStock(ctx, entity.InventoryMovementEntity{
+		ProductID: uint(productID),
+		Type:      req.Type,
+		Quantity:  req.Quantity,
+		Reason:    &req.Reason,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-4] AdjustStock")
+		if errors.Is(err, errs.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrOutOfStock) || errors.Is(err, errs.ErrInvalidStockAdjustment) {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, response.ResponseSuccess(http.StatusCreated, "success", toInventoryMovementResponse(*movement)))
+}
+
+// GetStockHistory implements ProductHandlerInterface.
+func (p *ProductHandler) GetStockHistory(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		res = response.StockHistoryResponse{}
+	)
+
+	productID, err := strconv.ParseUint(c.Param("productID"), 10, 64)
+	if err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-5] GetStockHistory: Product ID must be a valid number")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "product ID must be a valid number"))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 1000 {
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "limit must be a number between 1 and 1000"))
+		return
+	}
+
+	history, err := p.productService.GetStockHistory(ctx, uint(productID), limit)
+	if err != nil {
+		log.Error().Err(err).Msg("[ProductHandler-6] GetStockHistory")
+		if errors.Is(err, errs.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	res.ProductID = history.ProductID
+	res.CurrentStock = history.CurrentStock
+	res.LedgerStock = history.LedgerStock
+	res.Consistent = history.CurrentStock == history.LedgerStock
+	res.Movements = make([]response.InventoryMovementResponse, len(history.Movements))
+	for i, movement := range history.Movements {
+		res.Movements[i] = toInventoryMovementResponse(movement)
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+}
+
+func toInventoryMovementResponse(movement entity.InventoryMovementEntity) response.InventoryMovementResponse {
+	return response.InventoryMovementResponse{
+		ID:         movement.ID,
+		OrderID:    movement.OrderID,
+		Type:       movement.Type,
+		Quantity:   movement.Quantity,
+		StockAfter: movement.StockAfter,
+		Reason:     movement.Reason,
+		CreatedAt:  movement.CreatedAt,
+	}
+}
+
+func NewProductHandler(productService service.ProductServiceInterface, validator *v.Validator) ProductHandlerInterface {
+	return &ProductHandler{productService: productService, validator: validator}
+}
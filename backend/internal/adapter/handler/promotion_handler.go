@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"backend-service/internal/adapter/handler/request"
+	"backend-service/internal/adapter/handler/response"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/service"
+	v "backend-service/pkg/validator"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type PromotionHandlerInterface interface {
+	CreatePromotion(c *gin.Context)
+	GetPromotion(c *gin.Context)
+}
+
+type PromotionHandler struct {
+	promotionService service.PromotionServiceInterface
+	validator        *v.Validator
+}
+
+// GetPromotion implements PromotionHandlerInterface.
+func (p *PromotionHandler) GetPromotion(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	promotion, err := p.promotionService.GetPromotion(ctx, c.Param("code"))
+	if err != nil {
+		log.Error().Err(err).Msg("[PromotionHandler-1] GetPromotion")
+		if errors.Is(err, errs.ErrPromotionNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", toPromotionResponse(*promotion)))
+}
+
+// CreatePromotion implements PromotionHandlerInterface.
+func (p *PromotionHandler) CreatePromotion(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		req = request.CreatePromotionRequest{}
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("[PromotionHandler-2] CreatePromotion")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := p.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msg("[PromotionHandler-3] CreatePromotion")
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return
+	}
+
+	promotion, err := p.promotionService.CreatePromotion(ctx, entity.PromotionEntity{
+		Code:            req.Code,
+		Type:            req.Type,
+		Value:           req.Value,
+		Currency:        req.Currency,
+		ProductID:       req.ProductID,
+		MaxUses:         req.MaxUses,
+		MaxUsesPerBuyer: req.MaxUsesPerBuyer,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[PromotionHandler-4] CreatePromotion")
+		if errors.Is(err, errs.ErrInvalidPromotion) || errors.Is(err, errs.ErrCurrencyMismatch) {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+			return
+		} else if errors.Is(err, errs.ErrPromotionCodeTaken) {
+			c.JSON(http.StatusConflict, response.ResponseError(http.StatusConflict, err.Error()))
+			return
+		} else {
+			c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, response.ResponseSuccess(http.StatusCreated, "success", toPromotionResponse(*promotion)))
+}
+
+func toPromotionResponse(promotion entity.PromotionEntity) response.PromotionResponse {
+	return response.PromotionResponse{
+		ID:              promotion.ID,
+		Code:            promotion.Code,
+		Type:            promotion.Type,
+		Value:           promotion.Value,
+		Currency:        promotion.Currency,
+		ProductID:       promotion.ProductID,
+		MaxUses:         promotion.MaxUses,
+		MaxUsesPerBuyer: promotion.MaxUsesPerBuyer,
+		UsedCount:       promotion.UsedCount,
+		StartsAt:        promotion.StartsAt,
+		EndsAt:          promotion.EndsAt,
+	}
+}
+
+func NewPromotionHandler(promotionService service.PromotionServiceInterface, validator *v.Validator) PromotionHandlerInterface {
+	return &PromotionHandler{promotionService: promotionService, validator: validator}
+}
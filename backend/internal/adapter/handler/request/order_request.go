@@ -1,12 +1,118 @@
 package request
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type CreateOrderRequest struct {
-	ProductID uint   `json:"product_id" validate:"required"`
-	Quantity  int    `json:"quantity" validate:"required,min=1"`
-	BuyerID   string `json:"buyer_id" validate:"required"`
+	BuyerID   string             `json:"buyer_id" validate:"required"`
+	Items     []OrderItemRequest `json:"items" validate:"required,min=1,max=50,dive"`
+	PromoCode string             `json:"promo_code" validate:"max=64"`
+}
+
+type OrderItemRequest struct {
+	ProductID uint `json:"product_id" validate:"required"`
+	Quantity  int  `json:"quantity" validate:"required,min=1"`
+}
+
+type SetPurchaseLimitRequest struct {
+	// MaxPerBuyer removes the cap when null.
+	MaxPerBuyer *int `json:"max_per_buyer" validate:"omitempty,min=1"`
+}
+
+type SetSaleWindowRequest struct {
+	SaleStartsAt *time.Time `json:"sale_starts_at"`
+	SaleEndsAt   *time.Time `json:"sale_ends_at"`
 }
 
+type SetCartItemRequest struct {
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// CreateSettlementJobRequest defaults Priority to 5; higher priorities run first. Force queues a new job even
+// when an active one covers the same days, to run once that one finishes. TimeoutSeconds caps each run of the
+// job, in place of the max runtime configured for settlement jobs.
 type CreateSettlementJobRequest struct {
-	From string `json:"from" validate:"required"`
-	To   string `json:"to" validate:"required"`
+	From           string `json:"from" validate:"required"`
+	To             string `json:"to" validate:"required"`
+	Priority       *int   `json:"priority" validate:"omitempty,min=0,max=9"`
+	Force          bool   `json:"force"`
+	TimeoutSeconds *int   `json:"timeout_seconds" validate:"omitempty,min=1"`
+}
+
+type AdjustStockRequest struct {
+	Type     string `json:"type" validate:"required,oneof=RESTOCK ADJUSTMENT"`
+	Quantity int    `json:"quantity" validate:"required"`
+	Reason   string `json:"reason" validate:"required"`
+}
+
+type EnqueueRequest struct {
+	BuyerID string `json:"buyer_id" validate:"required"`
+}
+
+type ListOrdersRequest struct {
+	BuyerID     string     `form:"buyer_id"`
+	ProductID   uint       `form:"product_id"`
+	Status      string     `form:"status" validate:"omitempty,oneof=PENDING COMPLETED CANCELLED EXPIRED"`
+	CreatedFrom *time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo   *time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Cursor      string     `form:"cursor"`
+	Limit       int        `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type SimulatePaymentRequest struct {
+	Status string  `json:"status" validate:"required,oneof=PAID FAILED"`
+	Reason *string `json:"reason" validate:"omitempty,max=255"`
+}
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" validate:"required,url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"required,min=1,dive,required"`
+	Secret     string   `json:"secret" validate:"omitempty,min=16,max=255"`
+}
+
+// JobScheduleRequest creates or replaces a schedule. Active defaults to true.
+type JobScheduleRequest struct {
+	Name           string `json:"name" validate:"required,max=255"`
+	CronExpression string `json:"cron" validate:"required,max=100"`
+	Timezone       string `json:"timezone" validate:"omitempty,max=64"`
+	JobType        string `json:"job_type" validate:"omitempty,oneof=SETTLEMENT"`
+	DateRange      string `json:"date_range" validate:"required,oneof=yesterday last_7_days last_full_week last_full_month"`
+	Active         *bool  `json:"active"`
+}
+
+type ListJobScheduleRunsRequest struct {
+	Status string `form:"status" validate:"omitempty,oneof=PENDING FIRED FAILED MISSED"`
+	Limit  int    `form:"limit" validate:"omitempty,min=1,max=500"`
+}
+
+type CheckoutRequest struct {
+	PromoCode string `json:"promo_code"`
+}
+
+type CreatePromotionRequest struct {
+	Code            string     `json:"code" validate:"required,max=64"`
+	Type            string     `json:"type" validate:"required,oneof=PERCENTAGE FIXED"`
+	Value           int        `json:"value" validate:"required,gt=0"`
+	Currency        *string    `json:"currency" validate:"required_if=Type FIXED,omitempty,len=3"`
+	ProductID       *uint      `json:"product_id"`
+	MaxUses         *int       `json:"max_uses" validate:"omitempty,gt=0"`
+	MaxUsesPerBuyer *int       `json:"max_uses_per_buyer" validate:"omitempty,gt=0"`
+	StartsAt        *time.Time `json:"starts_at"`
+	EndsAt          *time.Time `json:"ends_at"`
+}
+
+// JobDashboardCommand is a message a dashboard sends over GET /ws/jobs. subscribe and unsubscribe take any mix of
+// job IDs and job types; cancel takes a single job ID.
+type JobDashboardCommand struct {
+	Action string      `json:"action" validate:"required,oneof=subscribe unsubscribe cancel"`
+	JobIDs []uuid.UUID `json:"job_ids"`
+	Types  []string    `json:"types" validate:"dive,required"`
+	JobID  *uuid.UUID  `json:"job_id" validate:"required_if=Action cancel"`
 }
@@ -1,38 +1,298 @@
 package response
 
-import "github.com/google/uuid"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Money is an amount in minor units of Currency; Display is the same amount in major units, e.g. "10.50".
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Display  string `json:"display"`
+}
 
 type CreateOrderResponse struct {
-	OrderID uuid.UUID `json:"order_id"`
-	Status  string    `json:"status"`
+	OrderID         uuid.UUID  `json:"order_id"`
+	Status          string     `json:"status"`
+	Gross           Money      `json:"gross"`
+	Discount        Money      `json:"discount"`
+	Tax             Money      `json:"tax"`
+	TaxInclusive    bool       `json:"tax_inclusive"`
+	Fee             Money      `json:"fee"`
+	Total           Money      `json:"total"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+	PaymentIntentID *string    `json:"payment_intent_id,omitempty"`
 }
 
 type GetOrderByIDResponse struct {
-	OrderID    uuid.UUID       `json:"order_id"`
+	OrderID         uuid.UUID           `json:"order_id"`
+	BuyerID         string              `json:"buyer_id"`
+	Currency        string              `json:"currency"`
+	Gross           Money               `json:"gross"`
+	Discount        Money               `json:"discount"`
+	Tax             Money               `json:"tax"`
+	TaxInclusive    bool                `json:"tax_inclusive"`
+	Fee             Money               `json:"fee"`
+	Total           Money               `json:"total"`
+	Status          string              `json:"status"`
+	PaymentIntentID *string             `json:"payment_intent_id,omitempty"`
+	CancelReason    *string             `json:"cancel_reason,omitempty"`
+	CancelledAt     *time.Time          `json:"cancelled_at,omitempty"`
+	ExpiresAt       *time.Time          `json:"expires_at,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	Items           []OrderItemResponse `json:"items"`
+}
+
+type ListOrdersResponse struct {
+	Orders     []GetOrderByIDResponse `json:"orders"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+}
+
+type OrderItemResponse struct {
 	ProductID  uint            `json:"product_id"`
-	BuyerID    string          `json:"buyer_id"`
 	Quantity   int             `json:"quantity"`
-	TotalCents int             `json:"total_cents"`
-	Status     string          `json:"status"`
+	UnitPrice  Money           `json:"unit_price"`
+	Total      Money           `json:"total"`
+	Discount   Money           `json:"discount"`
+	TaxRateBps int             `json:"tax_rate_bps"`
+	Tax        Money           `json:"tax"`
 	Product    *ProductDetails `json:"product,omitempty"`
 }
 
+type PaymentResultResponse struct {
+	PaymentIntentID string  `json:"payment_intent_id"`
+	Status          string  `json:"status"`
+	FailureReason   *string `json:"failure_reason,omitempty"`
+}
+
+type CartItemResponse struct {
+	ProductID uint            `json:"product_id"`
+	Quantity  int             `json:"quantity"`
+	Total     Money           `json:"total"`
+	Product   *ProductDetails `json:"product,omitempty"`
+}
+
+type CartResponse struct {
+	BuyerID string             `json:"buyer_id"`
+	Items   []CartItemResponse `json:"items"`
+	// Totals holds one entry per currency present in the cart.
+	Totals []Money `json:"totals"`
+}
+
+type CancelOrderResponse struct {
+	OrderID      uuid.UUID  `json:"order_id"`
+	Status       string     `json:"status"`
+	CancelReason *string    `json:"cancel_reason"`
+	CancelledAt  *time.Time `json:"cancelled_at"`
+}
+
+type ProductResponse struct {
+	ID              uint       `json:"id"`
+	Name            string     `json:"name"`
+	MerchantID      string     `json:"merchant_id"`
+	Category        string     `json:"category"`
+	Price           Money      `json:"price"`
+	Stock           int        `json:"stock"`
+	MaxPerBuyer     *int       `json:"max_per_buyer"`
+	SaleStartsAt    *time.Time `json:"sale_starts_at"`
+	SaleEndsAt      *time.Time `json:"sale_ends_at"`
+	SaleState       string     `json:"sale_state"`
+	StartsInSeconds *int64     `json:"starts_in_seconds,omitempty"`
+	EndsInSeconds   *int64     `json:"ends_in_seconds,omitempty"`
+	ServerTime      time.Time  `json:"server_time"`
+}
+
 type ProductDetails struct {
-	ID         uint   `json:"id"`
-	Name       string `json:"name"`
-	PriceCents int    `json:"price_cents"`
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Price Money  `json:"price"`
 }
 
+// CreateJobResponse has Existing set when an active job with the same parameters was returned instead.
 type CreateJobResponse struct {
-	JobID  uuid.UUID `json:"job_id"`
-	Status string    `json:"status"`
+	JobID    uuid.UUID `json:"job_id"`
+	Status   string    `json:"status"`
+	Existing bool      `json:"existing"`
 }
 
 type JobStatusResponse struct {
-	JobID       uuid.UUID `json:"job_id"`
-	Status      string    `json:"status"`
-	Progress    int       `json:"progress"`
-	Processed   int64     `json:"processed"`
-	Total       int64     `json:"total"`
-	DownloadURL *string   `json:"download_url,omitempty"`
+	JobID                 uuid.UUID  `json:"job_id"`
+	Status                string     `json:"status"`
+	Priority              int        `json:"priority"`
+	Progress              int        `json:"progress"`
+	Processed             int64      `json:"processed"`
+	Total                 int64      `json:"total"`
+	RowsPerSecond         float64    `json:"rows_per_second"`
+	ElapsedSeconds        int64      `json:"elapsed_seconds"`
+	StartedAt             *time.Time `json:"started_at"`
+	CompletedAt           *time.Time `json:"completed_at"`
+	EstimatedStartAt      *time.Time `json:"estimated_start_at,omitempty"`
+	EstimatedCompletionAt *time.Time `json:"estimated_completion_at,omitempty"`
+	TimeoutSeconds        *int64     `json:"timeout_seconds,omitempty"`
+	DeadlineAt            *time.Time `json:"deadline_at,omitempty"`
+	Requeues              int        `json:"requeues"`
+	ErrorMessage          *string    `json:"error_message,omitempty"`
+	DownloadURL           *string    `json:"download_url,omitempty"`
+}
+
+// JobProgressResponse is the data of each event on GET /jobs/:jobID/events.
+type JobProgressResponse struct {
+	JobID                 uuid.UUID  `json:"job_id"`
+	Type                  string     `json:"type"`
+	Status                string     `json:"status"`
+	Priority              int        `json:"priority"`
+	Progress              int        `json:"progress"`
+	Processed             int64      `json:"processed"`
+	Total                 int64      `json:"total"`
+	RowsPerSecond         float64    `json:"rows_per_second"`
+	ElapsedSeconds        int64      `json:"elapsed_seconds"`
+	StartedAt             *time.Time `json:"started_at"`
+	CompletedAt           *time.Time `json:"completed_at"`
+	EstimatedStartAt      *time.Time `json:"estimated_start_at,omitempty"`
+	EstimatedCompletionAt *time.Time `json:"estimated_completion_at,omitempty"`
+	ErrorMessage          *string    `json:"error_message,omitempty"`
+	DownloadURL           *string    `json:"download_url,omitempty"`
+}
+
+// JobDashboardMessage is a message sent over GET /ws/jobs: a job lifecycle event carrying Job, a "subscribed"
+// acknowledgement listing everything the connection follows, "cancel_requested", or "error".
+type JobDashboardMessage struct {
+	Event  string               `json:"event"`
+	Job    *JobProgressResponse `json:"job,omitempty"`
+	JobIDs []uuid.UUID          `json:"job_ids,omitempty"`
+	Types  []string             `json:"types,omitempty"`
+	JobID  *uuid.UUID           `json:"job_id,omitempty"`
+	Error  interface{}          `json:"error,omitempty"`
+}
+
+type InventoryMovementResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	OrderID    *uuid.UUID `json:"order_id,omitempty"`
+	Type       string     `json:"type"`
+	Quantity   int        `json:"quantity"`
+	StockAfter int        `json:"stock_after"`
+	Reason     *string    `json:"reason,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type StockHistoryResponse struct {
+	ProductID    uint                        `json:"product_id"`
+	CurrentStock int                         `json:"current_stock"`
+	LedgerStock  int                         `json:"ledger_stock"`
+	Consistent   bool                        `json:"consistent"`
+	Movements    []InventoryMovementResponse `json:"movements"`
+}
+
+type AdmissionResponse struct {
+	Token      uuid.UUID  `json:"token"`
+	BuyerID    string     `json:"buyer_id"`
+	Status     string     `json:"status"`
+	Position   int64      `json:"position,omitempty"`
+	AdmittedAt *time.Time `json:"admitted_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+}
+
+type PromotionResponse struct {
+	ID              uint       `json:"id"`
+	Code            string     `json:"code"`
+	Type            string     `json:"type"`
+	Value           int        `json:"value"`
+	Currency        *string    `json:"currency,omitempty"`
+	ProductID       *uint      `json:"product_id,omitempty"`
+	MaxUses         *int       `json:"max_uses,omitempty"`
+	MaxUsesPerBuyer *int       `json:"max_uses_per_buyer,omitempty"`
+	UsedCount       int        `json:"used_count"`
+	StartsAt        *time.Time `json:"starts_at,omitempty"`
+	EndsAt          *time.Time `json:"ends_at,omitempty"`
+}
+
+// WebhookSubscriptionResponse carries Secret only when the subscription is created.
+type WebhookSubscriptionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID                        `json:"id"`
+	SubscriptionID uuid.UUID                        `json:"subscription_id"`
+	EventID        uuid.UUID                        `json:"event_id"`
+	EventType      string                           `json:"event_type"`
+	Status         string                           `json:"status"`
+	Attempts       int                              `json:"attempts"`
+	NextAttemptAt  *time.Time                       `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int                             `json:"last_status_code,omitempty"`
+	LastError      *string                          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time                       `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time                        `json:"created_at"`
+	Payload        json.RawMessage                  `json:"payload,omitempty"`
+	AttemptLog     []WebhookDeliveryAttemptResponse `json:"attempt_log,omitempty"`
+}
+
+type WebhookDeliveryAttemptResponse struct {
+	StatusCode   *int      `json:"status_code,omitempty"`
+	Error        *string   `json:"error,omitempty"`
+	ResponseBody *string   `json:"response_body,omitempty"`
+	DurationMs   int64     `json:"duration_ms"`
+	AttemptedAt  time.Time `json:"attempted_at"`
+}
+
+// JobScheduleResponse reports next_run_at and last_run_at in the schedule's time zone.
+type JobScheduleResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	Name           string     `json:"name"`
+	CronExpression string     `json:"cron"`
+	Timezone       string     `json:"timezone"`
+	JobType        string     `json:"job_type"`
+	DateRange      string     `json:"date_range"`
+	Active         bool       `json:"active"`
+	NextRunAt      time.Time  `json:"next_run_at"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+type JobScheduleRunResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	ScheduleID   uuid.UUID  `json:"schedule_id"`
+	ScheduledFor time.Time  `json:"scheduled_for"`
+	Status       string     `json:"status"`
+	JobID        *uuid.UUID `json:"job_id,omitempty"`
+	Error        *string    `json:"error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// JobQueueResponse is the body of GET /jobs/queue.
+type JobQueueResponse struct {
+	Priorities []JobPriorityDepthResponse `json:"priorities"`
+	Types      []JobTypeDepthResponse     `json:"types"`
+	Watchdog   JobWatchdogResponse        `json:"watchdog"`
+}
+
+// JobWatchdogResponse counts the jobs this replica's watchdog has timed out since it started.
+type JobWatchdogResponse struct {
+	TimedOut int64 `json:"timed_out"`
+	Stalled  int64 `json:"stalled"`
+	Requeued int64 `json:"requeued"`
+	Failed   int64 `json:"failed"`
+}
+
+type JobPriorityDepthResponse struct {
+	Priority   int   `json:"priority"`
+	Queued     int64 `json:"queued"`
+	QueuedRows int64 `json:"queued_rows"`
+}
+
+// JobTypeDepthResponse omits Limit for types that may use every worker.
+type JobTypeDepthResponse struct {
+	Type    string `json:"type"`
+	Queued  int64  `json:"queued"`
+	Running int64  `json:"running"`
+	Limit   *int   `json:"limit,omitempty"`
 }
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"backend-service/internal/adapter/handler/request"
+	"backend-service/internal/adapter/handler/response"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/service"
+	v "backend-service/pkg/validator"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type WebhookHandlerInterface interface {
+	CreateWebhook(c *gin.Context)
+	ListWebhooks(c *gin.Context)
+	GetWebhook(c *gin.Context)
+	DeleteWebhook(c *gin.Context)
+	ListDeliveries(c *gin.Context)
+	GetDelivery(c *gin.Context)
+	Redeliver(c *gin.Context)
+}
+
+type WebhookHandler struct {
+	webhookService service.WebhookServiceInterface
+	validator      *v.Validator
+}
+
+// Redeliver implements WebhookHandlerInterface.
+func (w *WebhookHandler) Redeliver(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-1] Redeliver")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "delivery ID must be a valid UUID"))
+		return
+	}
+
+	delivery, err := w.webhookService.Redeliver(ctx, deliveryID)
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-2] Redeliver")
+		w.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.ResponseSuccess(http.StatusAccepted, "redelivery queued", toWebhookDeliveryResponse(*delivery, true)))
+}
+
+// GetDelivery implements WebhookHandlerInterface.
+func (w *WebhookHandler) GetDelivery(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-3] GetDelivery")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "delivery ID must be a valid UUID"))
+		return
+	}
+
+	delivery, err := w.webhookService.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-4] GetDelivery")
+		w.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", toWebhookDeliveryResponse(*delivery, true)))
+}
+
+// ListDeliveries implements WebhookHandlerInterface.
+func (w *WebhookHandler) ListDeliveries(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	subscriptionID, err := uuid.Parse(c.Param("webhookID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-5] ListDeliveries")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "webhook ID must be a valid UUID"))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "limit must be a number between 1 and 500"))
+		return
+	}
+
+	deliveries, err := w.webhookService.ListDeliveries(ctx, subscriptionID, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-6] ListDeliveries")
+		w.respondError(c, err)
+		return
+	}
+
+	res := make([]response.WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		res[i] = toWebhookDeliveryResponse(delivery, false)
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+}
+
+// DeleteWebhook implements WebhookHandlerInterface.
+func (w *WebhookHandler) DeleteWebhook(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	subscriptionID, err := uuid.Parse(c.Param("webhookID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-7] DeleteWebhook")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "webhook ID must be a valid UUID"))
+		return
+	}
+
+	if err := w.webhookService.DeleteSubscription(ctx, subscriptionID); err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-8] DeleteWebhook")
+		w.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "webhook deactivated", nil))
+}
+
+// GetWebhook implements WebhookHandlerInterface.
+func (w *WebhookHandler) GetWebhook(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	subscriptionID, err := uuid.Parse(c.Param("webhookID"))
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-9] GetWebhook")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "webhook ID must be a valid UUID"))
+		return
+	}
+
+	subscription, err := w.webhookService.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-10] GetWebhook")
+		w.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", toWebhookSubscriptionResponse(*subscription)))
+}
+
+// ListWebhooks implements WebhookHandlerInterface.
+func (w *WebhookHandler) ListWebhooks(c *gin.Context) {
+
+	ctx := c.Request.Context()
+
+	subscriptions, err := w.webhookService.ListSubscriptions(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-11] ListWebhooks")
+		w.respondError(c, err)
+		return
+	}
+
+	res := make([]response.WebhookSubscriptionResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		res[i] = toWebhookSubscriptionResponse(subscription)
+	}
+
+	c.JSON(http.StatusOK, response.ResponseSuccess(http.StatusOK, "success", res))
+}
+
+// CreateWebhook implements WebhookHandlerInterface.
+func (w *WebhookHandler) CreateWebhook(c *gin.Context) {
+
+	var (
+		ctx = c.Request.Context()
+		req = request.CreateWebhookRequest{}
+	)
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-12] CreateWebhook")
+		c.JSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+		return
+	}
+
+	if err := w.validator.Validate(req); err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-13] CreateWebhook")
+
+		if ve, ok := err.(v.ValidationError); ok {
+			c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, ve.Errors))
+			return
+		}
+
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+		return
+	}
+
+	subscription, err := w.webhookService.CreateSubscription(ctx, entity.WebhookSubscriptionEntity{
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     req.Secret,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookHandler-14] CreateWebhook")
+		w.respondError(c, err)
+		return
+	}
+
+	res := toWebhookSubscriptionResponse(*subscription)
+	res.Secret = subscription.Secret
+
+	c.JSON(http.StatusCreated, response.ResponseSuccess(http.StatusCreated, "success", res))
+}
+
+func (w *WebhookHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, errs.ErrWebhookNotFound) || errors.Is(err, errs.ErrWebhookDeliveryNotFound) {
+		c.JSON(http.StatusNotFound, response.ResponseError(http.StatusNotFound, err.Error()))
+	} else if errors.Is(err, errs.ErrInvalidWebhook) {
+		c.JSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+	} else {
+		c.JSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+	}
+}
+
+func toWebhookSubscriptionResponse(subscription entity.WebhookSubscriptionEntity) response.WebhookSubscriptionResponse {
+	return response.WebhookSubscriptionResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+// toWebhookDeliveryResponse includes the payload and attempt log only when detailed is set.
+func toWebhookDeliveryResponse(delivery entity.WebhookDeliveryEntity, detailed bool) response.WebhookDeliveryResponse {
+	res := response.WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+
+	if delivery.Status == "PENDING" {
+		res.NextAttemptAt = &delivery.NextAttemptAt
+	}
+
+	if detailed {
+		res.Payload = json.RawMessage(delivery.Payload)
+		res.AttemptLog = make([]response.WebhookDeliveryAttemptResponse, len(delivery.AttemptLog))
+		for i, attempt := range delivery.AttemptLog {
+			res.AttemptLog[i] = response.WebhookDeliveryAttemptResponse{
+				StatusCode:   attempt.StatusCode,
+				Error:        attempt.Error,
+				ResponseBody: attempt.ResponseBody,
+				DurationMs:   attempt.Duration.Milliseconds(),
+				AttemptedAt:  attempt.CreatedAt,
+			}
+		}
+	}
+
+	return res
+}
+
+func NewWebhookHandler(webhookService service.WebhookServiceInterface, validator *v.Validator) WebhookHandlerInterface {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		validator:      validator,
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"backend-service/internal/adapter/handler"
+	"backend-service/internal/adapter/handler/response"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/service"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// Idempotency replays the stored response for a repeated Idempotency-Key and rejects a key
+// reused with a different request. Requests without the header pass through unchanged.
+func Idempotency(idempotencyService service.IdempotencyServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > 255 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "Idempotency-Key must be at most 255 characters"))
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			log.Error().Err(err).Msg("[IdempotencyMiddleware-1] failed to read request body")
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, err.Error()))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		// The scope is the request path rather than the route template, so the same key sent for two buyers is two
+		// requests. The queue token is part of the fingerprint, as it decides whether the request is admitted.
+		scope := c.Request.Method + " " + c.Request.URL.Path
+		if len(scope) > 255 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, response.ResponseError(http.StatusBadRequest, "request path is too long for an Idempotency-Key"))
+			return
+		}
+		hash := sha256.New()
+		hash.Write([]byte(scope + "\n" + c.GetHeader(handler.QueueTokenHeader) + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		ctx := c.Request.Context()
+		stored, err := idempotencyService.Begin(ctx, key, scope, requestHash)
+		if err != nil {
+			log.Error().Err(err).Str("idempotency_key", key).Msg("[IdempotencyMiddleware-2] failed to begin idempotent request")
+			if errors.Is(err, errs.ErrIdempotencyKeyReused) {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, response.ResponseError(http.StatusUnprocessableEntity, err.Error()))
+				return
+			} else if errors.Is(err, errs.ErrIdempotencyKeyInProgress) {
+				c.AbortWithStatusJSON(http.StatusConflict, response.ResponseError(http.StatusConflict, err.Error()))
+				return
+			} else {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, response.ResponseError(http.StatusInternalServerError, err.Error()))
+				return
+			}
+		}
+
+		if stored != nil {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.ResponseCode, "application/json; charset=utf-8", stored.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+
+		// The request outcome is already decided, so a cancelled client must not prevent storing it.
+		storeCtx := context.WithoutCancel(ctx)
+
+		// Server errors are not cached so the client can retry with the same key.
+		release := func() {
+			if err := idempotencyService.Release(storeCtx, key, scope); err != nil {
+				log.Error().Err(err).Str("idempotency_key", key).Msg("[IdempotencyMiddleware-3] failed to release idempotency key")
+			}
+		}
+
+		// A panicking handler ends in a 500 from the recovery middleware, so its key is released like any server error.
+		defer func() {
+			if r := recover(); r != nil {
+				release()
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			release()
+			return
+		}
+
+		if err := idempotencyService.Complete(storeCtx, key, scope, c.Writer.Status(), recorder.body.Bytes()); err != nil {
+			log.Error().Err(err).Str("idempotency_key", key).Msg("[IdempotencyMiddleware-4] failed to store idempotent response")
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/service"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryIdempotencyService keeps keys in memory with the reserve, replay and reuse rules of IdempotencyService.
+type memoryIdempotencyService struct {
+	service.IdempotencyServiceInterface
+	mu   sync.Mutex
+	keys map[string]*entity.IdempotencyKeyEntity
+}
+
+func newMemoryIdempotencyService() *memoryIdempotencyService {
+	return &memoryIdempotencyService{keys: make(map[string]*entity.IdempotencyKeyEntity)}
+}
+
+func (s *memoryIdempotencyService) Begin(_ context.Context, key string, scope string, requestHash string) (*entity.IdempotencyKeyEntity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.keys[scope+" "+key]
+	if !ok {
+		s.keys[scope+" "+key] = &entity.IdempotencyKeyEntity{Key: key, Scope: scope, RequestHash: requestHash, Status: "IN_PROGRESS"}
+		return nil, nil
+	}
+	if existing.RequestHash != requestHash {
+		return nil, errs.ErrIdempotencyKeyReused
+	}
+	if existing.Status != "COMPLETED" {
+		return nil, errs.ErrIdempotencyKeyInProgress
+	}
+	return existing, nil
+}
+
+func (s *memoryIdempotencyService) Complete(_ context.Context, key string, scope string, responseCode int, responseBody []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := s.keys[scope+" "+key]
+	record.Status, record.ResponseCode, record.ResponseBody = "COMPLETED", responseCode, responseBody
+	return nil
+}
+
+func (s *memoryIdempotencyService) Release(_ context.Context, key string, scope string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, scope+" "+key)
+	return nil
+}
+
+func checkout(engine *gin.Engine, buyerID string, key string, queueToken string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/buyers/"+buyerID+"/cart/checkout", nil)
+	req.Header.Set(IdempotencyKeyHeader, key)
+	if queueToken != "" {
+		req.Header.Set("X-Queue-Token", queueToken)
+	}
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+// Two buyers sending the same key with an empty body each get their own checkout, never the other's response.
+func TestIdempotencyScopesKeyToRequestPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	checkouts := make(map[string]int)
+	engine := gin.New()
+	engine.POST("/buyers/:buyerID/cart/checkout", Idempotency(newMemoryIdempotencyService()), func(c *gin.Context) {
+		checkouts[c.Param("buyerID")]++
+		c.String(http.StatusCreated, "order for "+c.Param("buyerID"))
+	})
+
+	for _, buyerID := range []string{"buyer_001", "buyer_002"} {
+		rec := checkout(engine, buyerID, "key-1", "")
+		if rec.Code != http.StatusCreated || rec.Body.String() != "order for "+buyerID {
+			t.Errorf("%s got %d %q, want its own order", buyerID, rec.Code, rec.Body.String())
+		}
+		if rec.Header().Get("Idempotent-Replayed") != "" {
+			t.Errorf("%s was replayed a stored response", buyerID)
+		}
+	}
+
+	rec := checkout(engine, "buyer_001", "key-1", "")
+	if rec.Header().Get("Idempotent-Replayed") != "true" || rec.Body.String() != "order for buyer_001" {
+		t.Errorf("retry got %q, want buyer_001's order replayed", rec.Body.String())
+	}
+	if checkouts["buyer_001"] != 1 || checkouts["buyer_002"] != 1 {
+		t.Errorf("checkouts = %v, want one per buyer", checkouts)
+	}
+}
+
+func TestIdempotencyRejectsKeyReusedWithAnotherQueueToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.POST("/buyers/:buyerID/cart/checkout", Idempotency(newMemoryIdempotencyService()), func(c *gin.Context) {
+		c.String(http.StatusCreated, "order")
+	})
+
+	if rec := checkout(engine, "buyer_001", "key-1", "11111111-1111-1111-1111-111111111111"); rec.Code != http.StatusCreated {
+		t.Fatalf("first checkout got %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if rec := checkout(engine, "buyer_001", "key-1", "22222222-2222-2222-2222-222222222222"); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("checkout with another queue token got %d, want %d", rec.Code, http.StatusUnprocessableEntity)
+	}
+}
+
+// A handler that panics leaves its key free, so the retry runs again instead of being refused as in progress.
+func TestIdempotencyReleasesKeyWhenHandlerPanics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	engine := gin.New()
+	engine.Use(gin.CustomRecovery(func(c *gin.Context, _ any) { c.AbortWithStatus(http.StatusInternalServerError) }))
+	engine.POST("/buyers/:buyerID/cart/checkout", Idempotency(newMemoryIdempotencyService()), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("checkout failed")
+		}
+		c.String(http.StatusCreated, "order")
+	})
+
+	if rec := checkout(engine, "buyer_001", "key-1", ""); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("panicking checkout got %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if rec := checkout(engine, "buyer_001", "key-1", ""); rec.Code != http.StatusCreated {
+		t.Errorf("retry got %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if calls != 2 {
+		t.Errorf("handler ran %d times, want 2", calls)
+	}
+}
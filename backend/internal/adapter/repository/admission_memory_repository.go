@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type admissionMemoryToken struct {
+	entity.AdmissionTokenEntity
+	seq int64
+}
+
+// AdmissionMemoryRepository keeps the waiting room in process memory. It suits a single instance;
+// use AdmissionRepository when several replicas must share one queue.
+type AdmissionMemoryRepository struct {
+	mu      sync.Mutex
+	tokens  map[uuid.UUID]*admissionMemoryToken
+	waiting []uuid.UUID
+	nextSeq int64
+}
+
+// Restore implements AdmissionRepositoryInterface.
+func (a *AdmissionMemoryRepository) Restore(ctx context.Context, token uuid.UUID) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if current, ok := a.tokens[token]; ok && current.Status == "USED" {
+		current.Status = "ADMITTED"
+	}
+
+	return nil
+}
+
+// Consume implements AdmissionRepositoryInterface.
+func (a *AdmissionMemoryRepository) Consume(ctx context.Context, token uuid.UUID, buyerID string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	current, ok := a.tokens[token]
+	if !ok {
+		return errs.ErrQueueTokenNotFound
+	}
+
+	a.expire(current, time.Now())
+	if current.BuyerID == buyerID && current.Status == "ADMITTED" {
+		current.Status = "USED"
+		return nil
+	}
+
+	return admissionConsumeError(&current.AdmissionTokenEntity, buyerID)
+}
+
+// AdmitNext implements AdmissionRepositoryInterface.
+func (a *AdmissionMemoryRepository) AdmitNext(ctx context.Context, count int, ttl time.Duration) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	for _, current := range a.tokens {
+		a.expire(current, now)
+	}
+
+	if count > len(a.waiting) {
+		count = len(a.waiting)
+	}
+
+	expiresAt := now.Add(ttl)
+	for _, token := range a.waiting[:count] {
+		current := a.tokens[token]
+		current.Status = "ADMITTED"
+		current.AdmittedAt = &now
+		current.ExpiresAt = &expiresAt
+	}
+	a.waiting = a.waiting[count:]
+
+	return count, nil
+}
+
+// Get implements AdmissionRepositoryInterface.
+func (a *AdmissionMemoryRepository) Get(ctx context.Context, token uuid.UUID) (*entity.AdmissionTokenEntity, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	current, ok := a.tokens[token]
+	if !ok {
+		return nil, errs.ErrQueueTokenNotFound
+	}
+
+	a.expire(current, time.Now())
+	result := current.AdmissionTokenEntity
+	if result.Status == "WAITING" {
+		// Tokens are admitted strictly in order, so the waiting ones form a contiguous seq range.
+		result.Position = current.seq - a.tokens[a.waiting[0]].seq + 1
+	}
+
+	return &result, nil
+}
+
+// Enqueue implements AdmissionRepositoryInterface.
+func (a *AdmissionMemoryRepository) Enqueue(ctx context.Context, buyerID string) (*entity.AdmissionTokenEntity, error) {
+	a.mu.Lock()
+	a.nextSeq++
+	token := &admissionMemoryToken{
+		AdmissionTokenEntity: entity.AdmissionTokenEntity{
+			Token:     uuid.New(),
+			BuyerID:   buyerID,
+			Status:    "WAITING",
+			CreatedAt: time.Now(),
+		},
+		seq: a.nextSeq,
+	}
+	a.tokens[token.Token] = token
+	a.waiting = append(a.waiting, token.Token)
+	a.mu.Unlock()
+
+	return a.Get(ctx, token.Token)
+}
+
+// expire marks an admitted token whose window has passed; expired and used tokens are dropped after a day.
+func (a *AdmissionMemoryRepository) expire(current *admissionMemoryToken, now time.Time) {
+	if current.ExpiresAt == nil || now.Before(*current.ExpiresAt) {
+		return
+	}
+
+	if current.Status == "ADMITTED" {
+		current.Status = "EXPIRED"
+	}
+
+	if now.Sub(*current.ExpiresAt) > 24*time.Hour {
+		delete(a.tokens, current.Token)
+	}
+}
+
+func NewAdmissionMemoryRepository() AdmissionRepositoryInterface {
+	return &AdmissionMemoryRepository{
+		tokens: make(map[uuid.UUID]*admissionMemoryToken),
+	}
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/domain/model"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AdmissionRepositoryInterface interface {
+	Enqueue(ctx context.Context, buyerID string) (*entity.AdmissionTokenEntity, error)
+	Get(ctx context.Context, token uuid.UUID) (*entity.AdmissionTokenEntity, error)
+	AdmitNext(ctx context.Context, count int, ttl time.Duration) (int, error)
+	Consume(ctx context.Context, token uuid.UUID, buyerID string) error
+	Restore(ctx context.Context, token uuid.UUID) error
+}
+
+// admissionLockKey serializes AdmitNext across replicas so the configured rate is not multiplied by the instance count.
+const admissionLockKey = 7_340_034
+
+type AdmissionRepository struct {
+	db *gorm.DB
+}
+
+// Restore implements AdmissionRepositoryInterface.
+func (a *AdmissionRepository) Restore(ctx context.Context, token uuid.UUID) error {
+
+	err := dbFromContext(ctx, a.db).
+		Model(&model.AdmissionTokenModel{}).
+		Where("token = ? AND status = ?", token, "USED").
+		Updates(map[string]interface{}{
+			"status":  "ADMITTED",
+			"used_at": nil,
+		}).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("token", token.String()).Msg("[AdmissionRepository-1] Restore: failed to restore admission token")
+		return err
+	}
+
+	return nil
+}
+
+// Consume implements AdmissionRepositoryInterface.
+func (a *AdmissionRepository) Consume(ctx context.Context, token uuid.UUID, buyerID string) error {
+
+	result := dbFromContext(ctx, a.db).
+		Model(&model.AdmissionTokenModel{}).
+		Where("token = ? AND buyer_id = ? AND status = ? AND expires_at > NOW()", token, buyerID, "ADMITTED").
+		Updates(map[string]interface{}{
+			"status":  "USED",
+			"used_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("token", token.String()).Msg("[AdmissionRepository-2] Consume: failed to consume admission token")
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	current, err := a.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return admissionConsumeError(current, buyerID)
+}
+
+// AdmitNext implements AdmissionRepositoryInterface.
+func (a *AdmissionRepository) AdmitNext(ctx context.Context, count int, ttl time.Duration) (int, error) {
+
+	var admitted int64
+
+	err := dbFromContext(ctx, a.db).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", admissionLockKey).Scan(&locked).Error; err != nil {
+			return err
+		}
+
+		// Another replica is admitting this tick.
+		if !locked {
+			return nil
+		}
+
+		now := time.Now()
+		if err := tx.Model(&model.AdmissionTokenModel{}).
+			Where("status = ? AND expires_at <= ?", "ADMITTED", now).
+			Update("status", "EXPIRED").Error; err != nil {
+			return err
+		}
+
+		next := tx.Model(&model.AdmissionTokenModel{}).
+			Select("token").
+			Where("status = ?", "WAITING").
+			Order("seq ASC").
+			Limit(count).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+		expiresAt := now.Add(ttl)
+		result := tx.Model(&model.AdmissionTokenModel{}).
+			Where("token IN (?)", next).
+			Updates(map[string]interface{}{
+				"status":      "ADMITTED",
+				"admitted_at": now,
+				"expires_at":  expiresAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		admitted = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[AdmissionRepository-3] AdmitNext: failed to admit waiting tokens")
+		return 0, err
+	}
+
+	return int(admitted), nil
+}
+
+// Get implements AdmissionRepositoryInterface.
+func (a *AdmissionRepository) Get(ctx context.Context, token uuid.UUID) (*entity.AdmissionTokenEntity, error) {
+
+	tokenModel := model.AdmissionTokenModel{}
+	if err := dbFromContext(ctx, a.db).First(&tokenModel, "token = ?", token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrQueueTokenNotFound
+		}
+		log.Error().Err(err).Msg("[AdmissionRepository-4] Get: failed to get admission token")
+		return nil, err
+	}
+
+	result := &entity.AdmissionTokenEntity{
+		Token:      tokenModel.Token,
+		BuyerID:    tokenModel.BuyerID,
+		Status:     tokenModel.Status,
+		AdmittedAt: tokenModel.AdmittedAt,
+		ExpiresAt:  tokenModel.ExpiresAt,
+		CreatedAt:  tokenModel.CreatedAt,
+	}
+
+	if result.Status == "ADMITTED" && result.ExpiresAt != nil && !time.Now().Before(*result.ExpiresAt) {
+		result.Status = "EXPIRED"
+	}
+
+	if result.Status == "WAITING" {
+		err := dbFromContext(ctx, a.db).
+			Model(&model.AdmissionTokenModel{}).
+			Where("status = ? AND seq <= ?", "WAITING", tokenModel.Seq).
+			Count(&result.Position).Error
+		if err != nil {
+			log.Error().Err(err).Msg("[AdmissionRepository-5] Get: failed to compute queue position")
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// Enqueue implements AdmissionRepositoryInterface.
+func (a *AdmissionRepository) Enqueue(ctx context.Context, buyerID string) (*entity.AdmissionTokenEntity, error) {
+
+	request := model.AdmissionTokenModel{
+		BuyerID: buyerID,
+		Status:  "WAITING",
+	}
+
+	if err := dbFromContext(ctx, a.db).Clauses(clause.Returning{}).Create(&request).Error; err != nil {
+		log.Error().Err(err).Str("buyer_id", buyerID).Msg("[AdmissionRepository-6] Enqueue: failed to enqueue buyer")
+		return nil, err
+	}
+
+	return a.Get(ctx, request.Token)
+}
+
+// admissionConsumeError explains why token could not be consumed by buyerID.
+func admissionConsumeError(token *entity.AdmissionTokenEntity, buyerID string) error {
+	switch {
+	case token.BuyerID != buyerID:
+		return errs.ErrQueueTokenNotFound
+	case token.Status == "WAITING":
+		return errs.ErrQueueTokenNotAdmitted
+	default:
+		return errs.ErrQueueTokenExpired
+	}
+}
+
+func NewAdmissionRepository(db *gorm.DB) AdmissionRepositoryInterface {
+	return &AdmissionRepository{db: db}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/domain/model"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CartRepositoryInterface interface {
+	GetItems(ctx context.Context, buyerID string) ([]entity.CartItemEntity, error)
+	GetItemsForUpdate(ctx context.Context, buyerID string) ([]entity.CartItemEntity, error)
+	UpsertItem(ctx context.Context, item entity.CartItemEntity) error
+	DeleteItem(ctx context.Context, buyerID string, productID uint) error
+	Clear(ctx context.Context, buyerID string) error
+}
+
+type CartRepository struct {
+	db *gorm.DB
+}
+
+// Clear implements CartRepositoryInterface.
+func (c *CartRepository) Clear(ctx context.Context, buyerID string) error {
+
+	err := dbFromContext(ctx, c.db).
+		Where("buyer_id = ?", buyerID).
+		Delete(&model.CartItemModel{}).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("buyer_id", buyerID).Msg("[CartRepository-1] Clear: failed to clear cart")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteItem implements CartRepositoryInterface.
+func (c *CartRepository) DeleteItem(ctx context.Context, buyerID string, productID uint) error {
+
+	result := dbFromContext(ctx, c.db).
+		Where("buyer_id = ? AND product_id = ?", buyerID, productID).
+		Delete(&model.CartItemModel{})
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("buyer_id", buyerID).Msg("[CartRepository-2] DeleteItem: failed to delete cart item")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrCartItemNotFound
+	}
+
+	return nil
+}
+
+// UpsertItem implements CartRepositoryInterface.
+func (c *CartRepository) UpsertItem(ctx context.Context, item entity.CartItemEntity) error {
+
+	request := model.CartItemModel{
+		BuyerID:   item.BuyerID,
+		ProductID: item.ProductID,
+		Quantity:  item.Quantity,
+	}
+
+	err := dbFromContext(ctx, c.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "buyer_id"}, {Name: "product_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"quantity":   item.Quantity,
+				"updated_at": time.Now(),
+			}),
+		}).
+		Create(&request).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("buyer_id", item.BuyerID).Msg("[CartRepository-3] UpsertItem: failed to upsert cart item")
+		return err
+	}
+
+	return nil
+}
+
+// GetItemsForUpdate implements CartRepositoryInterface.
+// The cart rows stay locked until the surrounding transaction ends, so a concurrent checkout waits and then sees an empty cart.
+func (c *CartRepository) GetItemsForUpdate(ctx context.Context, buyerID string) ([]entity.CartItemEntity, error) {
+	return c.getItems(dbFromContext(ctx, c.db).Clauses(clause.Locking{Strength: "UPDATE"}), buyerID)
+}
+
+// GetItems implements CartRepositoryInterface.
+func (c *CartRepository) GetItems(ctx context.Context, buyerID string) ([]entity.CartItemEntity, error) {
+	return c.getItems(dbFromContext(ctx, c.db), buyerID)
+}
+
+func (c *CartRepository) getItems(db *gorm.DB, buyerID string) ([]entity.CartItemEntity, error) {
+
+	var items []model.CartItemModel
+	err := db.
+		Preload("Product").
+		Where("buyer_id = ?", buyerID).
+		Order("product_id ASC").
+		Find(&items).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("buyer_id", buyerID).Msg("[CartRepository-4] GetItems: failed to get cart items")
+		return nil, err
+	}
+
+	entities := make([]entity.CartItemEntity, len(items))
+	for i, item := range items {
+		entities[i] = entity.CartItemEntity{
+			BuyerID:   item.BuyerID,
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UpdatedAt: item.UpdatedAt,
+			Product: &entity.ProductEntity{
+				ID:    item.Product.ID,
+				Name:  item.Product.Name,
+				Price: entity.NewMoney(int64(item.Product.PriceCents), item.Product.Currency),
+				Stock: item.Product.Stock,
+			},
+		}
+	}
+
+	return entities, nil
+}
+
+func NewCartRepository(db *gorm.DB) CartRepositoryInterface {
+	return &CartRepository{db: db}
+}
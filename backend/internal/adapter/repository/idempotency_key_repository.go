@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"backend-service/internal/core/domain/model"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeyRepositoryInterface interface {
+	Reserve(ctx context.Context, key entity.IdempotencyKeyEntity) (bool, error)
+	Get(ctx context.Context, key string, scope string) (*entity.IdempotencyKeyEntity, error)
+	Complete(ctx context.Context, key string, scope string, responseCode int, responseBody []byte) error
+	Delete(ctx context.Context, key string, scope string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type IdempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+// Reserve implements IdempotencyKeyRepositoryInterface.
+// It returns false when an unexpired record for the same key and scope already exists.
+func (i *IdempotencyKeyRepository) Reserve(ctx context.Context, key entity.IdempotencyKeyEntity) (bool, error) {
+
+	request := model.IdempotencyKeyModel{
+		Key:         key.Key,
+		Scope:       key.Scope,
+		RequestHash: key.RequestHash,
+		Status:      "IN_PROGRESS",
+		ExpiresAt:   key.ExpiresAt,
+	}
+
+	// An expired record is taken over in place, a live one is left untouched.
+	result := dbFromContext(ctx, i.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}, {Name: "scope"}},
+			Where: clause.Where{Exprs: []clause.Expression{
+				clause.Expr{SQL: "idempotency_keys.expires_at < NOW()"},
+			}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"request_hash":  key.RequestHash,
+				"status":        "IN_PROGRESS",
+				"response_code": nil,
+				"response_body": nil,
+				"expires_at":    key.ExpiresAt,
+				"created_at":    time.Now(),
+				"updated_at":    time.Now(),
+			}),
+		}).
+		Create(&request)
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("[IdempotencyKeyRepository-1] Reserve: failed to reserve idempotency key")
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Get implements IdempotencyKeyRepositoryInterface.
+func (i *IdempotencyKeyRepository) Get(ctx context.Context, key string, scope string) (*entity.IdempotencyKeyEntity, error) {
+
+	modelKey := model.IdempotencyKeyModel{}
+	if err := dbFromContext(ctx, i.db).First(&modelKey, "key = ? AND scope = ?", key, scope).Error; err != nil {
+		log.Error().Err(err).Msg("[IdempotencyKeyRepository-2] Get: failed to get idempotency key")
+		return nil, err
+	}
+
+	responseCode := 0
+	if modelKey.ResponseCode != nil {
+		responseCode = *modelKey.ResponseCode
+	}
+
+	return &entity.IdempotencyKeyEntity{
+		Key:          modelKey.Key,
+		Scope:        modelKey.Scope,
+		RequestHash:  modelKey.RequestHash,
+		Status:       modelKey.Status,
+		ResponseCode: responseCode,
+		ResponseBody: modelKey.ResponseBody,
+		ExpiresAt:    modelKey.ExpiresAt,
+	}, nil
+}
+
+// Complete implements IdempotencyKeyRepositoryInterface.
+func (i *IdempotencyKeyRepository) Complete(ctx context.Context, key string, scope string, responseCode int, responseBody []byte) error {
+
+	err := dbFromContext(ctx, i.db).
+		Model(&model.IdempotencyKeyModel{}).
+		Where("key = ? AND scope = ?", key, scope).
+		Updates(map[string]interface{}{
+			"status":        "COMPLETED",
+			"response_code": responseCode,
+			"response_body": responseBody,
+			"updated_at":    time.Now(),
+		}).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("[IdempotencyKeyRepository-3] Complete: failed to store idempotent response")
+		return err
+	}
+
+	return nil
+}
+
+// Delete implements IdempotencyKeyRepositoryInterface.
+func (i *IdempotencyKeyRepository) Delete(ctx context.Context, key string, scope string) error {
+
+	err := dbFromContext(ctx, i.db).
+		Where("key = ? AND scope = ?", key, scope).
+		Delete(&model.IdempotencyKeyModel{}).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("[IdempotencyKeyRepository-4] Delete: failed to delete idempotency key")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExpired implements IdempotencyKeyRepositoryInterface.
+func (i *IdempotencyKeyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+
+	result := dbFromContext(ctx, i.db).
+		Where("expires_at < NOW()").
+		Delete(&model.IdempotencyKeyModel{})
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("[IdempotencyKeyRepository-5] DeleteExpired: failed to delete expired idempotency keys")
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepositoryInterface {
+	return &IdempotencyKeyRepository{db: db}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/domain/model"
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type InventoryMovementRepositoryInterface interface {
+	GetStockHistory(ctx context.Context, productID uint, limit int) (*entity.StockHistoryEntity, error)
+}
+
+type InventoryMovementRepository struct {
+	db *gorm.DB
+}
+
+// GetStockHistory implements InventoryMovementRepositoryInterface.
+func (i *InventoryMovementRepository) GetStockHistory(ctx context.Context, productID uint, limit int) (*entity.StockHistoryEntity, error) {
+
+	// Stock and ledger sum are read in a single statement so both come from the same snapshot.
+	var balance struct {
+		Stock       int
+		LedgerStock int
+	}
+
+	err := dbFromContext(ctx, i.db).
+		Table("products p").
+		Select("p.stock AS stock, COALESCE(SUM(m.quantity), 0) AS ledger_stock").
+		Joins("LEFT JOIN inventory_movements m ON m.product_id = p.id").
+		Where("p.id = ?", productID).
+		Group("p.id, p.stock").
+		Take(&balance).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Error().Err(err).Msg("[InventoryMovementRepository-1] GetStockHistory: product not found")
+			return nil, errs.ErrProductNotFound
+		}
+		log.Error().Err(err).Msg("[InventoryMovementRepository-2] GetStockHistory: failed to get stock balance")
+		return nil, err
+	}
+
+	var movements []model.InventoryMovementModel
+	err = dbFromContext(ctx, i.db).
+		Where("product_id = ?", productID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&movements).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("[InventoryMovementRepository-3] GetStockHistory: failed to get inventory movements")
+		return nil, err
+	}
+
+	entities := make([]entity.InventoryMovementEntity, len(movements))
+	for idx, movement := range movements {
+		entities[idx] = entity.InventoryMovementEntity{
+			ID:         movement.ID,
+			ProductID:  movement.ProductID,
+			OrderID:    movement.OrderID,
+			Type:       movement.Type,
+			Quantity:   movement.Quantity,
+			StockAfter: movement.StockAfter,
+			Reason:     movement.Reason,
+			CreatedAt:  movement.CreatedAt,
+		}
+	}
+
+	return &entity.StockHistoryEntity{
+		ProductID:    productID,
+		CurrentStock: balance.Stock,
+		LedgerStock:  balance.LedgerStock,
+		Movements:    entities,
+	}, nil
+
+}
+
+func NewInventoryMovementRepository(db *gorm.DB) InventoryMovementRepositoryInterface {
+	return &InventoryMovementRepository{db: db}
+}
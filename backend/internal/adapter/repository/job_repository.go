@@ -11,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type JobRepositoryInterface interface {
@@ -18,20 +19,282 @@ type JobRepositoryInterface interface {
 	GetByID(ctx context.Context, jobID uuid.UUID) (*entity.JobEntity, error)
 	UpdateStatus(ctx context.Context, jobID uuid.UUID, status string, errorMessage *string) error
 	UpdateStartedAt(ctx context.Context, jobID uuid.UUID, startedAt *time.Time) error
-	UpdateProgress(ctx context.Context, jobID uuid.UUID, progress int, processed int64) error
-	Complete(ctx context.Context, jobID uuid.UUID, resultPath string, completedAt *time.Time) error
+	UpdateProgress(ctx context.Context, jobID uuid.UUID, progress int, processed int64, throughput float64) error
+	Complete(ctx context.Context, jobID uuid.UUID, resultPath string, completedAt *time.Time, throughput float64) error
 	UpdateCompletedAt(ctx context.Context, jobID uuid.UUID, completedAt *time.Time) error
 	UpdateCancelledFlag(ctx context.Context, jobID uuid.UUID, cancelled bool) error
+	RecordThroughput(ctx context.Context, jobType string, rowsPerSecond float64, weight float64) error
+	GetThroughputStats(ctx context.Context) (map[string]float64, error)
+	GetBacklogAhead(ctx context.Context, job entity.JobEntity) (map[string]int64, error)
+	GetQueueDepth(ctx context.Context) ([]entity.JobQueueDepthEntity, error)
+	TransitionStatus(ctx context.Context, jobID uuid.UUID, fromStatus string, toStatus string) (bool, error)
+	Pause(ctx context.Context, jobID uuid.UUID, checkpoint string, progress int, processed int64) error
+	LockSettlementJobs(ctx context.Context) error
+	GetActiveSettlementOverlaps(ctx context.Context, from time.Time, to time.Time) ([]entity.JobEntity, error)
+	UpdateDeadline(ctx context.Context, jobID uuid.UUID, deadlineAt *time.Time) error
+	Requeue(ctx context.Context, jobID uuid.UUID, checkpoint *string, progress int, processed int64) (bool, error)
+	GetOverdue(ctx context.Context, now time.Time, stalledBefore time.Time) ([]entity.JobEntity, error)
 }
 
+// settlementJobLockKey serializes settlement job creation across replicas, so two requests for the same range
+// cannot both find no active job and both create one.
+const settlementJobLockKey = 7_340_035
+
 type JobRepository struct {
 	db *gorm.DB
 }
 
+// GetOverdue implements JobRepositoryInterface.
+// It returns the RUNNING jobs past their deadline or without a heartbeat since stalledBefore. Jobs started before
+// heartbeats were recorded are judged by when they started.
+func (j *JobRepository) GetOverdue(ctx context.Context, now time.Time, stalledBefore time.Time) ([]entity.JobEntity, error) {
+
+	var jobModels []model.JobModel
+	err := dbFromContext(ctx, j.db).
+		Where("status = ?", "RUNNING").
+		Where("deadline_at < ? OR COALESCE(heartbeat_at, started_at, updated_at) < ?", now, stalledBefore).
+		Order("created_at ASC").
+		Find(&jobModels).Error
+	if err != nil {
+		log.Error().Err(err).Msg("[JobRepository-13] GetOverdue: failed to find overdue jobs")
+		return nil, err
+	}
+
+	jobs := make([]entity.JobEntity, len(jobModels))
+	for i, jobModel := range jobModels {
+		jobs[i] = toJobEntity(jobModel)
+	}
+
+	return jobs, nil
+}
+
+// Requeue implements JobRepositoryInterface.
+// A RUNNING job goes back to QUEUED, counting the requeue; checkpoint, when given, replaces the one it resumes
+// from. It reports whether the job was still RUNNING.
+func (j *JobRepository) Requeue(ctx context.Context, jobID uuid.UUID, checkpoint *string, progress int, processed int64) (bool, error) {
+
+	updates := map[string]interface{}{
+		"status":      "QUEUED",
+		"progress":    progress,
+		"processed":   processed,
+		"requeues":    gorm.Expr("requeues + 1"),
+		"deadline_at": nil,
+	}
+	if checkpoint != nil {
+		updates["checkpoint"] = *checkpoint
+	}
+
+	result := dbFromContext(ctx, j.db).
+		Model(&model.JobModel{}).
+		Where("id = ? AND status = ?", jobID, "RUNNING").
+		Updates(updates)
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("job_id", jobID.String()).Msg("[JobRepository-12] Requeue: failed to requeue job")
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateDeadline implements JobRepositoryInterface.
+// It is called as a run starts, so it records the first heartbeat too.
+func (j *JobRepository) UpdateDeadline(ctx context.Context, jobID uuid.UUID, deadlineAt *time.Time) error {
+
+	err := dbFromContext(ctx, j.db).
+		Model(&model.JobModel{}).
+		Where("id = ?", jobID).
+		Updates(map[string]interface{}{
+			"deadline_at":  deadlineAt,
+			"heartbeat_at": time.Now(),
+		}).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobRepository-11] UpdateDeadline: failed to update deadline")
+		return err
+	}
+
+	return nil
+}
+
+// Pause implements JobRepositoryInterface.
+// Only a RUNNING job is paused; it returns ErrJobNotRunning when the job has already left that status, as when
+// the watchdog has requeued or failed it.
+func (j *JobRepository) Pause(ctx context.Context, jobID uuid.UUID, checkpoint string, progress int, processed int64) error {
+
+	result := dbFromContext(ctx, j.db).
+		Model(&model.JobModel{}).
+		Where("id = ? AND status = ?", jobID, "RUNNING").
+		Updates(map[string]interface{}{
+			"status":     "PAUSED",
+			"checkpoint": checkpoint,
+			"progress":   progress,
+			"processed":  processed,
+		})
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("job_id", jobID.String()).Msg("[JobRepository-10] Pause: failed to save checkpoint")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrJobNotRunning
+	}
+
+	return nil
+}
+
+// TransitionStatus implements JobRepositoryInterface.
+// The status only changes if it is still fromStatus; it reports whether it did.
+func (j *JobRepository) TransitionStatus(ctx context.Context, jobID uuid.UUID, fromStatus string, toStatus string) (bool, error) {
+
+	result := dbFromContext(ctx, j.db).
+		Model(&model.JobModel{}).
+		Where("id = ? AND status = ?", jobID, fromStatus).
+		Update("status", toStatus)
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("job_id", jobID.String()).Msg("[JobRepository-9] TransitionStatus: failed to update job status")
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// GetActiveSettlementOverlaps implements JobRepositoryInterface.
+// It returns the QUEUED, RUNNING and PAUSED settlement jobs whose range shares at least one instant with from
+// to to; both ends are inclusive, as they are when the jobs read transactions.
+func (j *JobRepository) GetActiveSettlementOverlaps(ctx context.Context, from time.Time, to time.Time) ([]entity.JobEntity, error) {
+
+	var jobModels []model.JobModel
+	err := dbFromContext(ctx, j.db).
+		Where("type = ? AND status IN ?", "SETTLEMENT", []string{"QUEUED", "RUNNING", "PAUSED"}).
+		Where("(params->>'From')::date <= ? AND (params->>'To')::date >= ?", to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Order("created_at ASC").
+		Find(&jobModels).Error
+	if err != nil {
+		log.Error().Err(err).Msg("[JobRepository-8] GetActiveSettlementOverlaps: failed to find overlapping jobs")
+		return nil, err
+	}
+
+	jobs := make([]entity.JobEntity, len(jobModels))
+	for i, jobModel := range jobModels {
+		jobs[i] = toJobEntity(jobModel)
+	}
+
+	return jobs, nil
+}
+
+// LockSettlementJobs implements JobRepositoryInterface.
+// It must be called within a transaction; the lock is held until the transaction ends.
+func (j *JobRepository) LockSettlementJobs(ctx context.Context) error {
+
+	if err := dbFromContext(ctx, j.db).Exec("SELECT pg_advisory_xact_lock(?)", settlementJobLockKey).Error; err != nil {
+		log.Error().Err(err).Msg("[JobRepository-7] LockSettlementJobs: failed to take settlement job lock")
+		return err
+	}
+
+	return nil
+}
+
+// GetQueueDepth implements JobRepositoryInterface.
+func (j *JobRepository) GetQueueDepth(ctx context.Context) ([]entity.JobQueueDepthEntity, error) {
+
+	var depths []entity.JobQueueDepthEntity
+	err := dbFromContext(ctx, j.db).
+		Model(&model.JobModel{}).
+		Select("type, priority, status, COUNT(*) AS jobs, COALESCE(SUM(GREATEST(total - processed, 0)), 0) AS remaining_rows").
+		Where("status IN ?", []string{"QUEUED", "RUNNING"}).
+		Group("type, priority, status").
+		Order("priority DESC, type ASC").
+		Scan(&depths).Error
+	if err != nil {
+		log.Error().Err(err).Msg("[JobRepository-6] GetQueueDepth: failed to count queued jobs")
+		return nil, err
+	}
+
+	return depths, nil
+}
+
+// GetBacklogAhead implements JobRepositoryInterface.
+// It returns, per job type, the rows still to process by RUNNING jobs and by QUEUED jobs that start before job:
+// those of a higher priority, or of the same priority created earlier.
+func (j *JobRepository) GetBacklogAhead(ctx context.Context, job entity.JobEntity) (map[string]int64, error) {
+
+	var rows []struct {
+		Type      string
+		Remaining int64
+	}
+
+	err := dbFromContext(ctx, j.db).
+		Model(&model.JobModel{}).
+		Select("type, COALESCE(SUM(GREATEST(total - processed, 0)), 0) AS remaining").
+		Where("id <> ?", job.ID).
+		Where("status = 'RUNNING' OR (status = 'QUEUED' AND (priority > ? OR (priority = ? AND created_at < ?)))", job.Priority, job.Priority, job.CreatedAt).
+		Group("type").
+		Scan(&rows).Error
+	if err != nil {
+		log.Error().Err(err).Str("job_id", job.ID.String()).Msg("[JobRepository-5] GetBacklogAhead: failed to sum queued rows")
+		return nil, err
+	}
+
+	backlog := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		backlog[row.Type] = row.Remaining
+	}
+
+	return backlog, nil
+}
+
+// GetThroughputStats implements JobRepositoryInterface.
+// It returns the smoothed rows per second of every job type that has completed at least once.
+func (j *JobRepository) GetThroughputStats(ctx context.Context) (map[string]float64, error) {
+
+	var statModels []model.JobThroughputStatModel
+	if err := dbFromContext(ctx, j.db).Find(&statModels).Error; err != nil {
+		log.Error().Err(err).Msg("[JobRepository-4] GetThroughputStats: failed to get throughput stats")
+		return nil, err
+	}
+
+	stats := make(map[string]float64, len(statModels))
+	for _, stat := range statModels {
+		stats[stat.JobType] = stat.RowsPerSecond
+	}
+
+	return stats, nil
+}
+
+// RecordThroughput implements JobRepositoryInterface.
+// The stored rate moves towards rowsPerSecond by weight, an exponential moving average across runs; the first
+// run of a type is taken as is.
+func (j *JobRepository) RecordThroughput(ctx context.Context, jobType string, rowsPerSecond float64, weight float64) error {
+
+	err := dbFromContext(ctx, j.db).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "job_type"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"rows_per_second": gorm.Expr("job_throughput_stats.rows_per_second * ? + EXCLUDED.rows_per_second * ?", 1-weight, weight),
+				"samples":         gorm.Expr("job_throughput_stats.samples + 1"),
+				"updated_at":      gorm.Expr("NOW()"),
+			}),
+		}).
+		Create(&model.JobThroughputStatModel{
+			JobType:       jobType,
+			RowsPerSecond: rowsPerSecond,
+			Samples:       1,
+		}).Error
+	if err != nil {
+		log.Error().Err(err).Str("job_type", jobType).Msg("[JobRepository-3] RecordThroughput: failed to record throughput")
+		return err
+	}
+
+	return nil
+}
+
 // UpdateCancelledFlag implements JobRepositoryInterface.
 func (j *JobRepository) UpdateCancelledFlag(ctx context.Context, jobID uuid.UUID, cancelled bool) error {
 
-	result := j.db.WithContext(ctx).Model(&model.JobModel{}).
+	result := dbFromContext(ctx, j.db).Model(&model.JobModel{}).
 		Where("id = ?", jobID).
 		Updates(map[string]interface{}{
 			"cancelled":  cancelled,
@@ -55,7 +318,7 @@ func (j *JobRepository) UpdateCancelledFlag(ctx context.Context, jobID uuid.UUID
 // UpdateCompletedAt implements JobRepositoryInterface.
 func (j *JobRepository) UpdateCompletedAt(ctx context.Context, jobID uuid.UUID, completedAt *time.Time) error {
 
-	err := j.db.WithContext(ctx).
+	err := dbFromContext(ctx, j.db).
 		Model(&model.JobModel{}).
 		Where("id = ?", jobID).
 		Update("completed_at", completedAt).Error
@@ -70,21 +333,29 @@ func (j *JobRepository) UpdateCompletedAt(ctx context.Context, jobID uuid.UUID,
 }
 
 // Complete implements JobRepositoryInterface.
-func (j *JobRepository) Complete(ctx context.Context, jobID uuid.UUID, resultPath string, completedAt *time.Time) error {
+// Only a RUNNING job is completed; it returns ErrJobNotRunning when the watchdog has already requeued or failed
+// the job, so the transaction writing its settlements rolls back.
+func (j *JobRepository) Complete(ctx context.Context, jobID uuid.UUID, resultPath string, completedAt *time.Time, throughput float64) error {
 
-	err := j.db.WithContext(ctx).
+	result := dbFromContext(ctx, j.db).
 		Model(&model.JobModel{}).
-		Where("id = ?", jobID).
+		Where("id = ? AND status = ?", jobID, "RUNNING").
 		Updates(map[string]interface{}{
 			"status":       "COMPLETED",
 			"progress":     100,
 			"result_path":  resultPath,
 			"completed_at": completedAt,
-		}).Error
+			"throughput":   throughput,
+			"checkpoint":   nil,
+		})
 
-	if err != nil {
-		log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobRepository] Complete: failed to mark job as completed")
-		return err
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("job_id", jobID.String()).Msg("[JobRepository] Complete: failed to mark job as completed")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrJobNotRunning
 	}
 
 	return nil
@@ -92,13 +363,15 @@ func (j *JobRepository) Complete(ctx context.Context, jobID uuid.UUID, resultPat
 }
 
 // UpdateProgress implements JobRepositoryInterface.
-func (j *JobRepository) UpdateProgress(ctx context.Context, jobID uuid.UUID, progress int, processed int64) error {
-	err := j.db.WithContext(ctx).
+func (j *JobRepository) UpdateProgress(ctx context.Context, jobID uuid.UUID, progress int, processed int64, throughput float64) error {
+	err := dbFromContext(ctx, j.db).
 		Model(&model.JobModel{}).
 		Where("id = ?", jobID).
 		Updates(map[string]interface{}{
-			"progress":  progress,
-			"processed": processed,
+			"progress":     progress,
+			"processed":    processed,
+			"throughput":   throughput,
+			"heartbeat_at": time.Now(),
 		}).Error
 
 	if err != nil {
@@ -112,7 +385,7 @@ func (j *JobRepository) UpdateProgress(ctx context.Context, jobID uuid.UUID, pro
 // UpdateStartedAt implements JobRepositoryInterface.
 func (j *JobRepository) UpdateStartedAt(ctx context.Context, jobID uuid.UUID, startedAt *time.Time) error {
 
-	err := j.db.WithContext(ctx).
+	err := dbFromContext(ctx, j.db).
 		Model(&model.JobModel{}).
 		Where("id = ?", jobID).
 		Update("started_at", startedAt).Error
@@ -137,7 +410,7 @@ func (j *JobRepository) UpdateStatus(ctx context.Context, jobID uuid.UUID, statu
 		updates["error_message"] = *errorMessage
 	}
 
-	err := j.db.WithContext(ctx).
+	err := dbFromContext(ctx, j.db).
 		Model(&model.JobModel{}).
 		Where("id = ?", jobID).
 		Updates(updates).Error
@@ -155,7 +428,7 @@ func (j *JobRepository) UpdateStatus(ctx context.Context, jobID uuid.UUID, statu
 func (j *JobRepository) GetByID(ctx context.Context, jobID uuid.UUID) (*entity.JobEntity, error) {
 
 	modelJob := model.JobModel{}
-	if err := j.db.WithContext(ctx).First(&modelJob, "id = ?", jobID).Error; err != nil {
+	if err := dbFromContext(ctx, j.db).First(&modelJob, "id = ?", jobID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Error().Err(err).Msg("[JobRepository-1] GetByID: job not found")
 			return nil, errs.ErrJobNotFound
@@ -163,17 +436,8 @@ func (j *JobRepository) GetByID(ctx context.Context, jobID uuid.UUID) (*entity.J
 		log.Error().Err(err).Msg("[JobRepository-2] GetByID: failed to get job by ID")
 		return nil, err
 	}
-	return &entity.JobEntity{
-		ID:          modelJob.ID,
-		Type:        modelJob.Type,
-		Status:      modelJob.Status,
-		Total:       modelJob.Total,
-		Progress:    modelJob.Progress,
-		Processed:   modelJob.Processed,
-		Params:      modelJob.Params,
-		UniqueRunID: modelJob.UniqueRunID,
-		ResultPath:  modelJob.ResultPath,
-	}, nil
+	job := toJobEntity(modelJob)
+	return &job, nil
 
 }
 
@@ -182,12 +446,17 @@ func (j *JobRepository) Create(ctx context.Context, job *entity.JobEntity) (uuid
 	request := model.JobModel{
 		Type:        job.Type,
 		Status:      job.Status,
+		Priority:    job.Priority,
 		Total:       job.Total,
 		Params:      job.Params,
 		UniqueRunID: job.UniqueRunID,
 	}
+	if job.Timeout > 0 {
+		timeoutSeconds := int(job.Timeout / time.Second)
+		request.TimeoutSeconds = &timeoutSeconds
+	}
 
-	if err := j.db.WithContext(ctx).Create(&request).Error; err != nil {
+	if err := dbFromContext(ctx, j.db).Create(&request).Error; err != nil {
 		log.Error().Err(err).Msg("[JobRepository-1] Create: failed to create job")
 		return uuid.Nil, err
 	}
@@ -195,6 +464,35 @@ func (j *JobRepository) Create(ctx context.Context, job *entity.JobEntity) (uuid
 	return request.ID, nil
 }
 
+func toJobEntity(jobModel model.JobModel) entity.JobEntity {
+	job := entity.JobEntity{
+		ID:           jobModel.ID,
+		Type:         jobModel.Type,
+		Status:       jobModel.Status,
+		Priority:     jobModel.Priority,
+		Total:        jobModel.Total,
+		Progress:     jobModel.Progress,
+		Processed:    jobModel.Processed,
+		Params:       jobModel.Params,
+		UniqueRunID:  jobModel.UniqueRunID,
+		ResultPath:   jobModel.ResultPath,
+		ErrorMessage: jobModel.ErrorMessage,
+		Cancelled:    jobModel.Cancelled,
+		Checkpoint:   jobModel.Checkpoint,
+		Throughput:   jobModel.Throughput,
+		StartedAt:    jobModel.StartedAt,
+		CompletedAt:  jobModel.CompletedAt,
+		CreatedAt:    jobModel.CreatedAt,
+		DeadlineAt:   jobModel.DeadlineAt,
+		HeartbeatAt:  jobModel.HeartbeatAt,
+		Requeues:     jobModel.Requeues,
+	}
+	if jobModel.TimeoutSeconds != nil {
+		job.Timeout = time.Duration(*jobModel.TimeoutSeconds) * time.Second
+	}
+	return job
+}
+
 func NewJobRepository(db *gorm.DB) JobRepositoryInterface {
 	return &JobRepository{db: db}
 }
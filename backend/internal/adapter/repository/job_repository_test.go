@@ -0,0 +1,96 @@
+package repository
+
+import (
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/domain/model"
+	"backend-service/internal/testdb"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func seedJob(t *testing.T, db *gorm.DB, status string) uuid.UUID {
+	t.Helper()
+
+	job := model.JobModel{Type: "SETTLEMENT", Status: status, Params: "{}"}
+	if err := db.Create(&job).Error; err != nil {
+		t.Fatalf("seed job: %v", err)
+	}
+	return job.ID
+}
+
+func readJob(t *testing.T, db *gorm.DB, jobID uuid.UUID) model.JobModel {
+	t.Helper()
+
+	var job model.JobModel
+	if err := db.First(&job, "id = ?", jobID).Error; err != nil {
+		t.Fatalf("read job: %v", err)
+	}
+	return job
+}
+
+// A pause recorded after the watchdog has requeued or failed the job leaves the job as the watchdog left it.
+func TestPauseKeepsJobThatLeftRunning(t *testing.T) {
+	tests := []struct {
+		status string
+		want   error
+	}{
+		{"RUNNING", nil},
+		{"QUEUED", errs.ErrJobNotRunning},
+		{"FAILED", errs.ErrJobNotRunning},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			db := testdb.Open(t)
+			jobID := seedJob(t, db, tt.status)
+
+			err := NewJobRepository(db).Pause(context.Background(), jobID, `{"processed":5}`, 50, 5)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Pause error = %v, want %v", err, tt.want)
+			}
+
+			job := readJob(t, db, jobID)
+			if tt.want == nil && (job.Status != "PAUSED" || job.Checkpoint == nil) {
+				t.Errorf("job is %s with checkpoint %v, want PAUSED with one", job.Status, job.Checkpoint)
+			}
+			if tt.want != nil && (job.Status != tt.status || job.Checkpoint != nil) {
+				t.Errorf("job is %s with checkpoint %v, want %s without one", job.Status, job.Checkpoint, tt.status)
+			}
+		})
+	}
+}
+
+// A completion recorded after the watchdog has requeued the job rolls back with the settlements written alongside
+// it, so the job is settled once, by the run that completes it.
+func TestCompleteRollsBackForJobThatLeftRunning(t *testing.T) {
+	db := testdb.Open(t)
+	jobRepo := NewJobRepository(db)
+	jobID := seedJob(t, db, "QUEUED")
+	completedAt := time.Now()
+
+	err := NewTxManager(db).WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := jobRepo.RecordThroughput(ctx, "SETTLEMENT", 100, 0.3); err != nil {
+			return err
+		}
+		return jobRepo.Complete(ctx, jobID, "/tmp/settlement.csv", &completedAt, 100)
+	})
+	if !errors.Is(err, errs.ErrJobNotRunning) {
+		t.Fatalf("WithinTransaction error = %v, want %v", err, errs.ErrJobNotRunning)
+	}
+
+	if job := readJob(t, db, jobID); job.Status != "QUEUED" || job.ResultPath != nil {
+		t.Errorf("job is %s with result %v, want QUEUED without one", job.Status, job.ResultPath)
+	}
+	stats, err := jobRepo.GetThroughputStats(context.Background())
+	if err != nil {
+		t.Fatalf("GetThroughputStats: %v", err)
+	}
+	if _, ok := stats["SETTLEMENT"]; ok {
+		t.Error("throughput of the rolled back completion was recorded")
+	}
+}
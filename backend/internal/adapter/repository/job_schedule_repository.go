@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/domain/model"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobScheduleRepositoryInterface interface {
+	Create(ctx context.Context, schedule entity.JobScheduleEntity) (*entity.JobScheduleEntity, error)
+	GetByID(ctx context.Context, scheduleID uuid.UUID) (*entity.JobScheduleEntity, error)
+	List(ctx context.Context) ([]entity.JobScheduleEntity, error)
+	Update(ctx context.Context, schedule entity.JobScheduleEntity) error
+	Delete(ctx context.Context, scheduleID uuid.UUID) error
+	GetDue(ctx context.Context, now time.Time, limit int) ([]entity.JobScheduleEntity, error)
+	Advance(ctx context.Context, scheduleID uuid.UUID, nextRunAt time.Time, lastRunAt *time.Time) error
+	CreateRun(ctx context.Context, run entity.JobScheduleRunEntity) (*entity.JobScheduleRunEntity, error)
+	UpdateRun(ctx context.Context, run entity.JobScheduleRunEntity) (bool, error)
+	ListRuns(ctx context.Context, scheduleID uuid.UUID, status string, limit int) ([]entity.JobScheduleRunEntity, error)
+	MissStaleRuns(ctx context.Context, claimedBefore time.Time, message string) (int64, error)
+}
+
+type JobScheduleRepository struct {
+	db *gorm.DB
+}
+
+// MissStaleRuns implements JobScheduleRepositoryInterface.
+// PENDING runs claimed before claimedBefore are marked MISSED with message; it returns how many were.
+func (j *JobScheduleRepository) MissStaleRuns(ctx context.Context, claimedBefore time.Time, message string) (int64, error) {
+
+	result := dbFromContext(ctx, j.db).
+		Model(&model.JobScheduleRunModel{}).
+		Where("status = ? AND created_at < ?", entity.ScheduleRunPending, claimedBefore).
+		Updates(map[string]interface{}{
+			"status": entity.ScheduleRunMissed,
+			"error":  message,
+		})
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("[JobScheduleRepository-11] MissStaleRuns: failed to mark stale runs as missed")
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+// ListRuns implements JobScheduleRepositoryInterface.
+// Runs are returned newest occurrence first; an empty status returns runs of every status.
+func (j *JobScheduleRepository) ListRuns(ctx context.Context, scheduleID uuid.UUID, status string, limit int) ([]entity.JobScheduleRunEntity, error) {
+
+	query := dbFromContext(ctx, j.db).Where("schedule_id = ?", scheduleID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var runModels []model.JobScheduleRunModel
+	if err := query.Order("scheduled_for DESC").Limit(limit).Find(&runModels).Error; err != nil {
+		log.Error().Err(err).Str("schedule_id", scheduleID.String()).Msg("[JobScheduleRepository-10] ListRuns: failed to list runs")
+		return nil, err
+	}
+
+	runs := make([]entity.JobScheduleRunEntity, len(runModels))
+	for i, runModel := range runModels {
+		runs[i] = toJobScheduleRunEntity(runModel)
+	}
+
+	return runs, nil
+}
+
+// UpdateRun implements JobScheduleRepositoryInterface.
+// Only a PENDING run is updated, so a run already recorded as MISSED keeps that outcome; it reports whether the
+// run was still PENDING.
+func (j *JobScheduleRepository) UpdateRun(ctx context.Context, run entity.JobScheduleRunEntity) (bool, error) {
+
+	result := dbFromContext(ctx, j.db).
+		Model(&model.JobScheduleRunModel{}).
+		Where("id = ? AND status = ?", run.ID, entity.ScheduleRunPending).
+		Updates(map[string]interface{}{
+			"status": run.Status,
+			"job_id": run.JobID,
+			"error":  run.Error,
+		})
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("run_id", run.ID.String()).Msg("[JobScheduleRepository-9] UpdateRun: failed to update run")
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// CreateRun implements JobScheduleRepositoryInterface.
+// It returns nil without error when the occurrence already has a run, so a run is only ever recorded once.
+func (j *JobScheduleRepository) CreateRun(ctx context.Context, run entity.JobScheduleRunEntity) (*entity.JobScheduleRunEntity, error) {
+
+	runModel := model.JobScheduleRunModel{
+		ID:           uuid.New(),
+		ScheduleID:   run.ScheduleID,
+		ScheduledFor: run.ScheduledFor,
+		Status:       run.Status,
+		JobID:        run.JobID,
+		Error:        run.Error,
+	}
+
+	result := dbFromContext(ctx, j.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&runModel)
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("schedule_id", run.ScheduleID.String()).Msg("[JobScheduleRepository-8] CreateRun: failed to record run")
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	created := toJobScheduleRunEntity(runModel)
+	return &created, nil
+}
+
+// Advance implements JobScheduleRepositoryInterface.
+// lastRunAt is left as it is when nil.
+func (j *JobScheduleRepository) Advance(ctx context.Context, scheduleID uuid.UUID, nextRunAt time.Time, lastRunAt *time.Time) error {
+
+	updates := map[string]interface{}{
+		"next_run_at": nextRunAt,
+	}
+	if lastRunAt != nil {
+		updates["last_run_at"] = lastRunAt
+	}
+
+	err := dbFromContext(ctx, j.db).
+		Model(&model.JobScheduleModel{}).
+		Where("id = ?", scheduleID).
+		Updates(updates).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("schedule_id", scheduleID.String()).Msg("[JobScheduleRepository-7] Advance: failed to advance schedule")
+		return err
+	}
+
+	return nil
+}
+
+// GetDue implements JobScheduleRepositoryInterface.
+// The schedules are locked until the surrounding transaction ends; other instances skip them.
+func (j *JobScheduleRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]entity.JobScheduleEntity, error) {
+
+	var scheduleModels []model.JobScheduleModel
+	err := dbFromContext(ctx, j.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("active AND next_run_at <= ?", now).
+		Order("next_run_at ASC, id ASC").
+		Limit(limit).
+		Find(&scheduleModels).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleRepository-6] GetDue: failed to get due schedules")
+		return nil, err
+	}
+
+	schedules := make([]entity.JobScheduleEntity, len(scheduleModels))
+	for i, scheduleModel := range scheduleModels {
+		schedules[i] = toJobScheduleEntity(scheduleModel)
+	}
+
+	return schedules, nil
+}
+
+// Delete implements JobScheduleRepositoryInterface.
+// The schedule's runs are deleted with it.
+func (j *JobScheduleRepository) Delete(ctx context.Context, scheduleID uuid.UUID) error {
+
+	result := dbFromContext(ctx, j.db).Delete(&model.JobScheduleModel{}, "id = ?", scheduleID)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("schedule_id", scheduleID.String()).Msg("[JobScheduleRepository-5] Delete: failed to delete schedule")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrJobScheduleNotFound
+	}
+
+	return nil
+}
+
+// Update implements JobScheduleRepositoryInterface.
+func (j *JobScheduleRepository) Update(ctx context.Context, schedule entity.JobScheduleEntity) error {
+
+	result := dbFromContext(ctx, j.db).
+		Model(&model.JobScheduleModel{}).
+		Where("id = ?", schedule.ID).
+		Updates(map[string]interface{}{
+			"name":            schedule.Name,
+			"cron_expression": schedule.CronExpression,
+			"timezone":        schedule.Timezone,
+			"job_type":        schedule.JobType,
+			"date_range":      schedule.DateRange,
+			"active":          schedule.Active,
+			"next_run_at":     schedule.NextRunAt,
+		})
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("schedule_id", schedule.ID.String()).Msg("[JobScheduleRepository-4] Update: failed to update schedule")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrJobScheduleNotFound
+	}
+
+	return nil
+}
+
+// List implements JobScheduleRepositoryInterface.
+func (j *JobScheduleRepository) List(ctx context.Context) ([]entity.JobScheduleEntity, error) {
+
+	var scheduleModels []model.JobScheduleModel
+	if err := dbFromContext(ctx, j.db).Order("created_at ASC").Find(&scheduleModels).Error; err != nil {
+		log.Error().Err(err).Msg("[JobScheduleRepository-3] List: failed to list schedules")
+		return nil, err
+	}
+
+	schedules := make([]entity.JobScheduleEntity, len(scheduleModels))
+	for i, scheduleModel := range scheduleModels {
+		schedules[i] = toJobScheduleEntity(scheduleModel)
+	}
+
+	return schedules, nil
+}
+
+// GetByID implements JobScheduleRepositoryInterface.
+func (j *JobScheduleRepository) GetByID(ctx context.Context, scheduleID uuid.UUID) (*entity.JobScheduleEntity, error) {
+
+	var scheduleModel model.JobScheduleModel
+	if err := dbFromContext(ctx, j.db).First(&scheduleModel, "id = ?", scheduleID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrJobScheduleNotFound
+		}
+		log.Error().Err(err).Str("schedule_id", scheduleID.String()).Msg("[JobScheduleRepository-2] GetByID: failed to get schedule")
+		return nil, err
+	}
+
+	schedule := toJobScheduleEntity(scheduleModel)
+	return &schedule, nil
+}
+
+// Create implements JobScheduleRepositoryInterface.
+func (j *JobScheduleRepository) Create(ctx context.Context, schedule entity.JobScheduleEntity) (*entity.JobScheduleEntity, error) {
+
+	scheduleModel := model.JobScheduleModel{
+		Name:           schedule.Name,
+		CronExpression: schedule.CronExpression,
+		Timezone:       schedule.Timezone,
+		JobType:        schedule.JobType,
+		DateRange:      schedule.DateRange,
+		Active:         schedule.Active,
+		NextRunAt:      schedule.NextRunAt,
+	}
+
+	if err := dbFromContext(ctx, j.db).Create(&scheduleModel).Error; err != nil {
+		log.Error().Err(err).Msg("[JobScheduleRepository-1] Create: failed to create schedule")
+		return nil, err
+	}
+
+	created := toJobScheduleEntity(scheduleModel)
+	return &created, nil
+}
+
+func toJobScheduleEntity(scheduleModel model.JobScheduleModel) entity.JobScheduleEntity {
+	return entity.JobScheduleEntity{
+		ID:             scheduleModel.ID,
+		Name:           scheduleModel.Name,
+		CronExpression: scheduleModel.CronExpression,
+		Timezone:       scheduleModel.Timezone,
+		JobType:        scheduleModel.JobType,
+		DateRange:      scheduleModel.DateRange,
+		Active:         scheduleModel.Active,
+		NextRunAt:      scheduleModel.NextRunAt,
+		LastRunAt:      scheduleModel.LastRunAt,
+		CreatedAt:      scheduleModel.CreatedAt,
+		UpdatedAt:      scheduleModel.UpdatedAt,
+	}
+}
+
+func toJobScheduleRunEntity(runModel model.JobScheduleRunModel) entity.JobScheduleRunEntity {
+	return entity.JobScheduleRunEntity{
+		ID:           runModel.ID,
+		ScheduleID:   runModel.ScheduleID,
+		ScheduledFor: runModel.ScheduledFor,
+		Status:       runModel.Status,
+		JobID:        runModel.JobID,
+		Error:        runModel.Error,
+		CreatedAt:    runModel.CreatedAt,
+	}
+}
+
+func NewJobScheduleRepository(db *gorm.DB) JobScheduleRepositoryInterface {
+	return &JobScheduleRepository{db: db}
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"backend-service/internal/testdb"
+	"context"
+	"testing"
+	"time"
+)
+
+// A run another instance recorded as missed while its job was being created keeps that outcome.
+func TestUpdateRunKeepsRunRecordedAsMissed(t *testing.T) {
+	tests := []struct {
+		name    string
+		missed  bool
+		updated bool
+		status  string
+	}{
+		{"pending", false, true, entity.ScheduleRunFired},
+		{"missed", true, false, entity.ScheduleRunMissed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testdb.Open(t)
+			repo := NewJobScheduleRepository(db)
+			ctx := context.Background()
+
+			schedule, err := repo.Create(ctx, entity.JobScheduleEntity{Name: "nightly", CronExpression: "0 2 * * *", Timezone: "UTC", JobType: "SETTLEMENT", DateRange: entity.ScheduleRangeYesterday, Active: true, NextRunAt: time.Now()})
+			if err != nil {
+				t.Fatalf("create schedule: %v", err)
+			}
+			run, err := repo.CreateRun(ctx, entity.JobScheduleRunEntity{ScheduleID: schedule.ID, ScheduledFor: time.Now(), Status: entity.ScheduleRunPending})
+			if err != nil || run == nil {
+				t.Fatalf("CreateRun = %v, %v; want a run", run, err)
+			}
+			if tt.missed {
+				if _, err := repo.MissStaleRuns(ctx, time.Now().Add(time.Second), "not fired in time"); err != nil {
+					t.Fatalf("MissStaleRuns: %v", err)
+				}
+			}
+
+			jobID := seedJob(t, db, "QUEUED")
+			run.Status, run.JobID = entity.ScheduleRunFired, &jobID
+			updated, err := repo.UpdateRun(ctx, *run)
+			if err != nil {
+				t.Fatalf("UpdateRun: %v", err)
+			}
+			if updated != tt.updated {
+				t.Errorf("UpdateRun updated = %v, want %v", updated, tt.updated)
+			}
+
+			runs, err := repo.ListRuns(ctx, schedule.ID, "", 10)
+			if err != nil || len(runs) != 1 {
+				t.Fatalf("ListRuns = %d runs, %v; want 1", len(runs), err)
+			}
+			if runs[0].Status != tt.status || (runs[0].JobID != nil) == tt.missed {
+				t.Errorf("run is %s with job %v, want %s", runs[0].Status, runs[0].JobID, tt.status)
+			}
+		})
+	}
+}
@@ -6,25 +6,185 @@ import (
 	"backend-service/internal/core/domain/model"
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepositoryInterface interface {
 	Create(ctx context.Context, order entity.OrderEntity) (uuid.UUID, error)
 	GetOrderByID(ctx context.Context, orderID uuid.UUID) (*entity.OrderEntity, error)
+	Cancel(ctx context.Context, orderID uuid.UUID, fromStatus string, reason string, cancelledAt time.Time) error
+	UpdateStatus(ctx context.Context, orderID uuid.UUID, fromStatus string, toStatus string) error
+	GetExpiredPending(ctx context.Context, now time.Time, limit int) ([]entity.OrderEntity, error)
+	SumBuyerQuantity(ctx context.Context, buyerID string, productID uint) (int, error)
+	ListOrders(ctx context.Context, filter entity.OrderFilterEntity) (*entity.OrderPageEntity, error)
 }
 type OrderRepository struct {
 	db *gorm.DB
 }
 
+// ListOrders implements OrderRepositoryInterface.
+// Pages are keyed on (created_at, id) rather than offsets, so orders placed while paging do not shift later pages.
+func (o *OrderRepository) ListOrders(ctx context.Context, filter entity.OrderFilterEntity) (*entity.OrderPageEntity, error) {
+
+	query := dbFromContext(ctx, o.db).
+		Preload("Items", orderItemsByProduct).
+		Preload("Items.Product")
+
+	if filter.BuyerID != "" {
+		query = query.Where("buyer_id = ?", filter.BuyerID)
+	}
+	if filter.ProductID != 0 {
+		query = query.Where("EXISTS (SELECT 1 FROM order_items WHERE order_items.order_id = orders.id AND order_items.product_id = ?)", filter.ProductID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if filter.After != nil {
+		query = query.Where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
+
+	// One extra row tells whether another page follows.
+	var orders []model.OrderModel
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(filter.Limit + 1).
+		Find(&orders).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list orders")
+		return nil, err
+	}
+
+	page := &entity.OrderPageEntity{}
+	if len(orders) > filter.Limit {
+		orders = orders[:filter.Limit]
+		last := orders[len(orders)-1]
+		page.NextCursor = &entity.OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	page.Orders = make([]entity.OrderEntity, len(orders))
+	for i, order := range orders {
+		page.Orders[i] = toOrderEntity(order)
+	}
+
+	return page, nil
+
+}
+
+// SumBuyerQuantity implements OrderRepositoryInterface.
+// It counts units of productID held by buyerID in pending and completed orders.
+func (o *OrderRepository) SumBuyerQuantity(ctx context.Context, buyerID string, productID uint) (int, error) {
+
+	var total int
+	err := dbFromContext(ctx, o.db).
+		Model(&model.OrderItemModel{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.buyer_id = ? AND order_items.product_id = ? AND orders.status IN ?", buyerID, productID, []string{"PENDING", "COMPLETED"}).
+		Select("COALESCE(SUM(order_items.quantity), 0)").
+		Scan(&total).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("buyer_id", buyerID).Msg("failed to sum buyer quantity")
+		return 0, err
+	}
+
+	return total, nil
+
+}
+
+// GetExpiredPending implements OrderRepositoryInterface.
+// Rows locked by another sweeper are skipped, so several instances can sweep concurrently.
+func (o *OrderRepository) GetExpiredPending(ctx context.Context, now time.Time, limit int) ([]entity.OrderEntity, error) {
+
+	var orders []model.OrderModel
+	err := dbFromContext(ctx, o.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Preload("Items", orderItemsByProduct).
+		Where("status = ? AND expires_at <= ?", "PENDING", now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get expired pending orders")
+		return nil, err
+	}
+
+	entities := make([]entity.OrderEntity, len(orders))
+	for i, order := range orders {
+		entities[i] = toOrderEntity(order)
+	}
+
+	return entities, nil
+
+}
+
+// UpdateStatus implements OrderRepositoryInterface.
+// The update only applies while the order is still in fromStatus.
+func (o *OrderRepository) UpdateStatus(ctx context.Context, orderID uuid.UUID, fromStatus string, toStatus string) error {
+
+	result := dbFromContext(ctx, o.db).
+		Model(&model.OrderModel{}).
+		Where("id = ? AND status = ?", orderID, fromStatus).
+		Update("status", toStatus)
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("order_id", orderID.String()).Msg("failed to update order status")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Error().Str("order_id", orderID.String()).Msg("order status changed before update")
+		return errs.ErrOrderStatusConflict
+	}
+
+	return nil
+
+}
+
+// Cancel implements OrderRepositoryInterface.
+// The update only applies while the order is still in fromStatus, so concurrent cancellations cannot both succeed.
+func (o *OrderRepository) Cancel(ctx context.Context, orderID uuid.UUID, fromStatus string, reason string, cancelledAt time.Time) error {
+
+	result := dbFromContext(ctx, o.db).
+		Model(&model.OrderModel{}).
+		Where("id = ? AND status = ?", orderID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":        "CANCELLED",
+			"cancel_reason": reason,
+			"cancelled_at":  cancelledAt,
+		})
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("order_id", orderID.String()).Msg("failed to cancel order")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Error().Str("order_id", orderID.String()).Msg("order status changed before cancellation")
+		return errs.ErrOrderStatusConflict
+	}
+
+	return nil
+
+}
+
 // GetOrderByID implements OrderRepositoryInterface.
 func (o *OrderRepository) GetOrderByID(ctx context.Context, orderID uuid.UUID) (*entity.OrderEntity, error) {
 
 	orderModel := model.OrderModel{}
-	if err := o.db.WithContext(ctx).Preload("Product").First(&orderModel, "id = ?", orderID).Error; err != nil {
+	if err := dbFromContext(ctx, o.db).Preload("Items", orderItemsByProduct).Preload("Items.Product").First(&orderModel, "id = ?", orderID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			log.Error().Err(err).Msg("order not found")
 			return nil, errs.ErrOrderNotFound
@@ -33,33 +193,43 @@ func (o *OrderRepository) GetOrderByID(ctx context.Context, orderID uuid.UUID) (
 		return nil, err
 	}
 
-	return &entity.OrderEntity{
-		ID:         orderID,
-		ProductID:  orderModel.ProductID,
-		BuyerID:    orderModel.BuyerID,
-		Quantity:   orderModel.Quantity,
-		TotalCents: orderModel.TotalCents,
-		Status:     orderModel.Status,
-		Product: &entity.ProductEntity{
-			ID:         orderModel.Product.ID,
-			Name:       orderModel.Product.Name,
-			PriceCents: orderModel.Product.PriceCents,
-		},
-	}, nil
+	order := toOrderEntity(orderModel)
+	return &order, nil
 
 }
 
 // Create implements OrderRepositoryInterface.
 func (o *OrderRepository) Create(ctx context.Context, order entity.OrderEntity) (uuid.UUID, error) {
 	modelOrder := model.OrderModel{
-		ProductID:  order.ProductID,
-		BuyerID:    order.BuyerID,
-		Quantity:   order.Quantity,
-		TotalCents: order.TotalCents,
-		Status:     order.Status,
+		ID:              order.ID,
+		BuyerID:         order.BuyerID,
+		Currency:        order.Currency,
+		GrossCents:      int(order.Gross.Amount),
+		DiscountCents:   int(order.Discount.Amount),
+		TaxCents:        int(order.Tax.Amount),
+		TaxInclusive:    order.TaxInclusive,
+		FeeCents:        int(order.Fee.Amount),
+		TotalCents:      int(order.Total.Amount),
+		PromotionID:     order.PromotionID,
+		PaymentIntentID: order.PaymentIntentID,
+		Status:          order.Status,
+		ExpiresAt:       order.ExpiresAt,
+		Items:           make([]model.OrderItemModel, len(order.Items)),
 	}
 
-	if err := o.db.Create(&modelOrder).Error; err != nil {
+	for i, item := range order.Items {
+		modelOrder.Items[i] = model.OrderItemModel{
+			ProductID:      item.ProductID,
+			Quantity:       item.Quantity,
+			UnitPriceCents: int(item.UnitPrice.Amount),
+			TotalCents:     int(item.Total.Amount),
+			DiscountCents:  int(item.Discount.Amount),
+			TaxRateBps:     item.TaxRateBps,
+			TaxCents:       int(item.Tax.Amount),
+		}
+	}
+
+	if err := dbFromContext(ctx, o.db).Create(&modelOrder).Error; err != nil {
 		log.Error().Err(err).
 			Str("buyer_id", order.BuyerID).
 			Msg("failed to create order")
@@ -70,6 +240,60 @@ func (o *OrderRepository) Create(ctx context.Context, order entity.OrderEntity)
 
 }
 
+// orderItemsByProduct keeps items in product ID order, the same order stock rows are locked in.
+func orderItemsByProduct(db *gorm.DB) *gorm.DB {
+	return db.Order("product_id ASC")
+}
+
+// toOrderEntity maps an order row; order_items carry no currency of their own and are in the order's currency.
+func toOrderEntity(orderModel model.OrderModel) entity.OrderEntity {
+	currency := orderModel.Currency
+	items := make([]entity.OrderItemEntity, len(orderModel.Items))
+	for i, item := range orderModel.Items {
+		items[i] = entity.OrderItemEntity{
+			ID:         item.ID,
+			OrderID:    item.OrderID,
+			ProductID:  item.ProductID,
+			Quantity:   item.Quantity,
+			UnitPrice:  entity.NewMoney(int64(item.UnitPriceCents), currency),
+			Total:      entity.NewMoney(int64(item.TotalCents), currency),
+			Discount:   entity.NewMoney(int64(item.DiscountCents), currency),
+			TaxRateBps: item.TaxRateBps,
+			Tax:        entity.NewMoney(int64(item.TaxCents), currency),
+		}
+		if item.Product.ID != 0 {
+			items[i].Product = &entity.ProductEntity{
+				ID:         item.Product.ID,
+				Name:       item.Product.Name,
+				MerchantID: item.Product.MerchantID,
+				Category:   item.Product.Category,
+				Price:      entity.NewMoney(int64(item.Product.PriceCents), item.Product.Currency),
+			}
+		}
+	}
+
+	return entity.OrderEntity{
+		ID:              orderModel.ID,
+		BuyerID:         orderModel.BuyerID,
+		Currency:        currency,
+		Gross:           entity.NewMoney(int64(orderModel.GrossCents), currency),
+		Discount:        entity.NewMoney(int64(orderModel.DiscountCents), currency),
+		Tax:             entity.NewMoney(int64(orderModel.TaxCents), currency),
+		TaxInclusive:    orderModel.TaxInclusive,
+		Fee:             entity.NewMoney(int64(orderModel.FeeCents), currency),
+		Total:           entity.NewMoney(int64(orderModel.TotalCents), currency),
+		PromotionID:     orderModel.PromotionID,
+		PaymentIntentID: orderModel.PaymentIntentID,
+		Status:          orderModel.Status,
+		CancelReason:    orderModel.CancelReason,
+		CancelledAt:     orderModel.CancelledAt,
+		ExpiresAt:       orderModel.ExpiresAt,
+		CreatedAt:       orderModel.CreatedAt,
+		UpdatedAt:       orderModel.UpdatedAt,
+		Items:           items,
+	}
+}
+
 func NewOrderRepository(db *gorm.DB) OrderRepositoryInterface {
 	return &OrderRepository{
 		db: db,
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"backend-service/internal/core/domain/model"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepositoryInterface interface {
+	Add(ctx context.Context, event entity.OutboxEventEntity) error
+	GetDue(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEventEntity, error)
+	Lease(ctx context.Context, eventIDs []uuid.UUID, until time.Time) error
+	MarkPublished(ctx context.Context, eventID uuid.UUID, publishedAt time.Time) error
+	MarkFailed(ctx context.Context, eventID uuid.UUID, lastError string, leasedUntil time.Time, nextAttemptAt time.Time) error
+}
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// MarkFailed implements OutboxRepositoryInterface.
+// Nothing is recorded once the lease taken until leasedUntil has passed to another relay or the event is published.
+func (o *OutboxRepository) MarkFailed(ctx context.Context, eventID uuid.UUID, lastError string, leasedUntil time.Time, nextAttemptAt time.Time) error {
+
+	err := dbFromContext(ctx, o.db).
+		Model(&model.OutboxEventModel{}).
+		Where("id = ? AND next_attempt_at = ? AND published_at IS NULL", eventID, leasedUntil).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		}).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("event_id", eventID.String()).Msg("[OutboxRepository-4] MarkFailed: failed to record publish failure")
+		return err
+	}
+
+	return nil
+
+}
+
+// MarkPublished implements OutboxRepositoryInterface.
+// An event already marked published, by a relay that took it over after the lease ran out, is left as it is.
+func (o *OutboxRepository) MarkPublished(ctx context.Context, eventID uuid.UUID, publishedAt time.Time) error {
+
+	err := dbFromContext(ctx, o.db).
+		Model(&model.OutboxEventModel{}).
+		Where("id = ? AND published_at IS NULL", eventID).
+		Updates(map[string]interface{}{
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   nil,
+			"published_at": publishedAt,
+		}).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("event_id", eventID.String()).Msg("[OutboxRepository-3] MarkPublished: failed to mark event published")
+		return err
+	}
+
+	return nil
+
+}
+
+// Lease implements OutboxRepositoryInterface.
+// The events are not due again until until, which also identifies the lease to MarkFailed; it must therefore be
+// truncated to the microsecond the database stores.
+func (o *OutboxRepository) Lease(ctx context.Context, eventIDs []uuid.UUID, until time.Time) error {
+
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	err := dbFromContext(ctx, o.db).
+		Model(&model.OutboxEventModel{}).
+		Where("id IN ?", eventIDs).
+		Update("next_attempt_at", until).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("[OutboxRepository-5] Lease: failed to lease events")
+		return err
+	}
+
+	return nil
+
+}
+
+// GetDue implements OutboxRepositoryInterface.
+// Events are returned oldest first. Rows locked by another relay are skipped, so several instances can relay concurrently.
+func (o *OutboxRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]entity.OutboxEventEntity, error) {
+
+	var events []model.OutboxEventModel
+	err := dbFromContext(ctx, o.db).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("published_at IS NULL AND next_attempt_at <= ?", now).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&events).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("[OutboxRepository-2] GetDue: failed to get due events")
+		return nil, err
+	}
+
+	entities := make([]entity.OutboxEventEntity, len(events))
+	for i, event := range events {
+		entities[i] = entity.OutboxEventEntity{
+			ID:            event.ID,
+			AggregateType: event.AggregateType,
+			AggregateID:   event.AggregateID,
+			EventType:     event.EventType,
+			Payload:       event.Payload,
+			Attempts:      event.Attempts,
+			LastError:     event.LastError,
+			NextAttemptAt: event.NextAttemptAt,
+			PublishedAt:   event.PublishedAt,
+			CreatedAt:     event.CreatedAt,
+		}
+	}
+
+	return entities, nil
+
+}
+
+// Add implements OutboxRepositoryInterface.
+// Called with a transaction-bound ctx, the event commits or rolls back with the change it describes.
+func (o *OutboxRepository) Add(ctx context.Context, event entity.OutboxEventEntity) error {
+
+	eventModel := model.OutboxEventModel{
+		ID:            event.ID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		EventType:     event.EventType,
+		Payload:       event.Payload,
+		NextAttemptAt: event.NextAttemptAt,
+	}
+
+	if err := dbFromContext(ctx, o.db).Create(&eventModel).Error; err != nil {
+		log.Error().Err(err).Str("event_type", event.EventType).Msg("[OutboxRepository-1] Add: failed to add outbox event")
+		return err
+	}
+
+	return nil
+
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepositoryInterface {
+	return &OutboxRepository{db: db}
+}
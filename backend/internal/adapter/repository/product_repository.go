@@ -4,35 +4,42 @@ import (
 	"backend-service/internal/core/domain/entity"
 	"backend-service/internal/core/domain/model"
 	"context"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepositoryInterface interface {
 	GetByID(ctx context.Context, id uint) (*entity.ProductEntity, error)
-	UpdateStock(ctx context.Context, id uint, quantity int) error
+	UpdateStock(ctx context.Context, movement entity.InventoryMovementEntity) (*entity.InventoryMovementEntity, error)
+	UpdatePurchaseLimit(ctx context.Context, id uint, maxPerBuyer *int) error
+	UpdateSaleWindow(ctx context.Context, id uint, startsAt *time.Time, endsAt *time.Time) error
 }
 
 type ProductRepository struct {
 	db *gorm.DB
 }
 
-// UpdateStock implements ProductRepositoryInterface.
-func (p *ProductRepository) UpdateStock(ctx context.Context, id uint, quantity int) error {
+// UpdateSaleWindow implements ProductRepositoryInterface.
+func (p *ProductRepository) UpdateSaleWindow(ctx context.Context, id uint, startsAt *time.Time, endsAt *time.Time) error {
 
-	result := p.db.WithContext(ctx).
+	result := dbFromContext(ctx, p.db).
 		Model(&model.ProductModel{}).
-		Where("id = ? AND stock >= ?", id, quantity).
-		Update("stock", gorm.Expr("stock - ?", quantity))
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"sale_starts_at": startsAt,
+			"sale_ends_at":   endsAt,
+		})
 
 	if result.Error != nil {
-		log.Error().Err(result.Error).Msg("failed to update stock")
+		log.Error().Err(result.Error).Msg("failed to update sale window")
 		return result.Error
 	}
 
 	if result.RowsAffected == 0 {
-		log.Error().Msg("out of stock")
+		log.Error().Msg("product not found")
 		return gorm.ErrRecordNotFound
 	}
 
@@ -40,11 +47,87 @@ func (p *ProductRepository) UpdateStock(ctx context.Context, id uint, quantity i
 
 }
 
+// UpdatePurchaseLimit implements ProductRepositoryInterface.
+func (p *ProductRepository) UpdatePurchaseLimit(ctx context.Context, id uint, maxPerBuyer *int) error {
+
+	result := dbFromContext(ctx, p.db).
+		Model(&model.ProductModel{}).
+		Where("id = ?", id).
+		Update("max_per_buyer", maxPerBuyer)
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("failed to update purchase limit")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Error().Msg("product not found")
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+
+}
+
+// UpdateStock implements ProductRepositoryInterface.
+// movement.Quantity is a signed delta; the stock change and its ledger entry are written in one transaction.
+func (p *ProductRepository) UpdateStock(ctx context.Context, movement entity.InventoryMovementEntity) (*entity.InventoryMovementEntity, error) {
+
+	movementModel := model.InventoryMovementModel{
+		ProductID: movement.ProductID,
+		OrderID:   movement.OrderID,
+		Type:      movement.Type,
+		Quantity:  movement.Quantity,
+		Reason:    movement.Reason,
+	}
+
+	err := dbFromContext(ctx, p.db).Transaction(func(tx *gorm.DB) error {
+		productModel := model.ProductModel{ID: movement.ProductID}
+		result := tx.Model(&productModel).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "stock"}}}).
+			Where("stock + ? >= 0", movement.Quantity).
+			Update("stock", gorm.Expr("stock + ?", movement.Quantity))
+
+		if result.Error != nil {
+			log.Error().Err(result.Error).Msg("failed to update stock")
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			log.Error().Msg("out of stock")
+			return gorm.ErrRecordNotFound
+		}
+
+		movementModel.StockAfter = productModel.Stock
+		if err := tx.Create(&movementModel).Error; err != nil {
+			log.Error().Err(err).Msg("failed to record inventory movement")
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.InventoryMovementEntity{
+		ID:         movementModel.ID,
+		ProductID:  movementModel.ProductID,
+		OrderID:    movementModel.OrderID,
+		Type:       movementModel.Type,
+		Quantity:   movementModel.Quantity,
+		StockAfter: movementModel.StockAfter,
+		Reason:     movementModel.Reason,
+		CreatedAt:  movementModel.CreatedAt,
+	}, nil
+
+}
+
 // GetByID implements ProductRepositoryInterface.
 func (p *ProductRepository) GetByID(ctx context.Context, productID uint) (*entity.ProductEntity, error) {
 
 	var productModel model.ProductModel
-	if err := p.db.WithContext(ctx).First(&productModel, productID).Error; err != nil {
+	if err := dbFromContext(ctx, p.db).First(&productModel, productID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Error().Err(err).Msg("product not found")
 			return nil, gorm.ErrRecordNotFound
@@ -54,12 +137,17 @@ func (p *ProductRepository) GetByID(ctx context.Context, productID uint) (*entit
 	}
 
 	return &entity.ProductEntity{
-		ID:         productModel.ID,
-		Name:       productModel.Name,
-		PriceCents: productModel.PriceCents,
-		Stock:      productModel.Stock,
-		CreatedAt:  productModel.CreatedAt,
-		UpdatedAt:  productModel.UpdatedAt,
+		ID:           productModel.ID,
+		Name:         productModel.Name,
+		MerchantID:   productModel.MerchantID,
+		Category:     productModel.Category,
+		Price:        entity.NewMoney(int64(productModel.PriceCents), productModel.Currency),
+		Stock:        productModel.Stock,
+		MaxPerBuyer:  productModel.MaxPerBuyer,
+		SaleStartsAt: productModel.SaleStartsAt,
+		SaleEndsAt:   productModel.SaleEndsAt,
+		CreatedAt:    productModel.CreatedAt,
+		UpdatedAt:    productModel.UpdatedAt,
 	}, nil
 }
 
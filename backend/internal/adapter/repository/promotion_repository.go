@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/domain/model"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type PromotionRepositoryInterface interface {
+	Create(ctx context.Context, promotion entity.PromotionEntity) (*entity.PromotionEntity, error)
+	GetByCode(ctx context.Context, code string) (*entity.PromotionEntity, error)
+	Redeem(ctx context.Context, redemption entity.PromotionRedemptionEntity) error
+	CountBuyerRedemptions(ctx context.Context, promotionID uint, buyerID string) (int64, error)
+	Release(ctx context.Context, orderID uuid.UUID) error
+}
+
+type PromotionRepository struct {
+	db *gorm.DB
+}
+
+// Release implements PromotionRepositoryInterface.
+// It hands the use back to the promotion; orders without a redemption are ignored.
+func (p *PromotionRepository) Release(ctx context.Context, orderID uuid.UUID) error {
+
+	return dbFromContext(ctx, p.db).Transaction(func(tx *gorm.DB) error {
+		redemption := model.PromotionRedemptionModel{}
+		result := tx.Where("order_id = ?", orderID).Delete(&redemption)
+		if result.Error != nil {
+			log.Error().Err(result.Error).Str("order_id", orderID.String()).Msg("[PromotionRepository-1] Release: failed to delete redemption")
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		err := tx.Model(&model.PromotionModel{}).
+			Where("id = (?)", tx.Model(&model.OrderModel{}).Select("promotion_id").Where("id = ?", orderID)).
+			Update("used_count", gorm.Expr("used_count - 1")).Error
+		if err != nil {
+			log.Error().Err(err).Str("order_id", orderID.String()).Msg("[PromotionRepository-2] Release: failed to release promotion use")
+			return err
+		}
+
+		return nil
+	})
+}
+
+// CountBuyerRedemptions implements PromotionRepositoryInterface.
+func (p *PromotionRepository) CountBuyerRedemptions(ctx context.Context, promotionID uint, buyerID string) (int64, error) {
+
+	var count int64
+	err := dbFromContext(ctx, p.db).
+		Model(&model.PromotionRedemptionModel{}).
+		Where("promotion_id = ? AND buyer_id = ?", promotionID, buyerID).
+		Count(&count).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("buyer_id", buyerID).Msg("[PromotionRepository-3] CountBuyerRedemptions: failed to count redemptions")
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Redeem implements PromotionRepositoryInterface.
+// The usage counter is bumped with a conditional update, which also locks the promotion row until commit
+// so concurrent redemptions of the same code are serialized.
+func (p *PromotionRepository) Redeem(ctx context.Context, redemption entity.PromotionRedemptionEntity) error {
+
+	return dbFromContext(ctx, p.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.PromotionModel{}).
+			Where("id = ? AND (max_uses IS NULL OR used_count < max_uses)", redemption.PromotionID).
+			Update("used_count", gorm.Expr("used_count + 1"))
+		if result.Error != nil {
+			log.Error().Err(result.Error).Uint("promotion_id", redemption.PromotionID).Msg("[PromotionRepository-4] Redeem: failed to update usage")
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errs.ErrPromotionExhausted
+		}
+
+		err := tx.Create(&model.PromotionRedemptionModel{
+			PromotionID:   redemption.PromotionID,
+			OrderID:       redemption.OrderID,
+			BuyerID:       redemption.BuyerID,
+			DiscountCents: int(redemption.Discount.Amount),
+		}).Error
+		if err != nil {
+			log.Error().Err(err).Uint("promotion_id", redemption.PromotionID).Msg("[PromotionRepository-5] Redeem: failed to record redemption")
+			return err
+		}
+
+		return nil
+	})
+}
+
+// GetByCode implements PromotionRepositoryInterface.
+func (p *PromotionRepository) GetByCode(ctx context.Context, code string) (*entity.PromotionEntity, error) {
+
+	promotionModel := model.PromotionModel{}
+	if err := dbFromContext(ctx, p.db).First(&promotionModel, "code = ?", code).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrPromotionNotFound
+		}
+		log.Error().Err(err).Str("code", code).Msg("[PromotionRepository-6] GetByCode: failed to get promotion")
+		return nil, err
+	}
+
+	promotion := toPromotionEntity(promotionModel)
+	return &promotion, nil
+}
+
+// Create implements PromotionRepositoryInterface.
+func (p *PromotionRepository) Create(ctx context.Context, promotion entity.PromotionEntity) (*entity.PromotionEntity, error) {
+
+	promotionModel := model.PromotionModel{
+		Code:            promotion.Code,
+		Type:            promotion.Type,
+		Value:           promotion.Value,
+		Currency:        promotion.Currency,
+		ProductID:       promotion.ProductID,
+		MaxUses:         promotion.MaxUses,
+		MaxUsesPerBuyer: promotion.MaxUsesPerBuyer,
+		StartsAt:        promotion.StartsAt,
+		EndsAt:          promotion.EndsAt,
+	}
+
+	if err := dbFromContext(ctx, p.db).Create(&promotionModel).Error; err != nil {
+		log.Error().Err(err).Str("code", promotion.Code).Msg("[PromotionRepository-7] Create: failed to create promotion")
+		return nil, err
+	}
+
+	created := toPromotionEntity(promotionModel)
+	return &created, nil
+}
+
+func toPromotionEntity(promotionModel model.PromotionModel) entity.PromotionEntity {
+	return entity.PromotionEntity{
+		ID:              promotionModel.ID,
+		Code:            promotionModel.Code,
+		Type:            promotionModel.Type,
+		Value:           promotionModel.Value,
+		Currency:        promotionModel.Currency,
+		ProductID:       promotionModel.ProductID,
+		MaxUses:         promotionModel.MaxUses,
+		MaxUsesPerBuyer: promotionModel.MaxUsesPerBuyer,
+		UsedCount:       promotionModel.UsedCount,
+		StartsAt:        promotionModel.StartsAt,
+		EndsAt:          promotionModel.EndsAt,
+		CreatedAt:       promotionModel.CreatedAt,
+		UpdatedAt:       promotionModel.UpdatedAt,
+	}
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepositoryInterface {
+	return &PromotionRepository{db: db}
+}
@@ -31,18 +31,19 @@ func (s *SettlementRepository) UpsertBatch(ctx context.Context, settlements []en
 		models[i] = model.SettlementModel{
 			MerchantID:  settlement.MerchantID,
 			Date:        settlement.Date,
-			GrossCents:  settlement.GrossCents,
-			FeeCents:    settlement.FeeCents,
-			NetCents:    settlement.NetCents,
+			Currency:    settlement.Currency,
+			GrossCents:  settlement.Gross.Amount,
+			FeeCents:    settlement.Fee.Amount,
+			NetCents:    settlement.Net.Amount,
 			TxnCount:    settlement.TxnCount,
 			GeneratedAt: settlement.GeneratedAt,
 			UniqueRunID: settlement.UniqueRunID,
 		}
 	}
 
-	err := s.db.WithContext(ctx).
+	err := dbFromContext(ctx, s.db).
 		Clauses(clause.OnConflict{
-			Columns: []clause.Column{{Name: "merchant_id"}, {Name: "date"}},
+			Columns: []clause.Column{{Name: "merchant_id"}, {Name: "date"}, {Name: "currency"}},
 			DoUpdates: clause.AssignmentColumns([]string{
 				"gross_cents", "fee_cents", "net_cents", "txn_count",
 				"generated_at", "unique_run_id", "updated_at",
@@ -6,27 +6,144 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TransactionRepositoryInterface interface {
 	Count(ctx context.Context, from, to time.Time) (int64, error)
-	GetBatch(ctx context.Context, from time.Time, to time.Time, offset int64, limit int64) ([]entity.TransactionEntity, error)
+	GetBatch(ctx context.Context, from time.Time, to time.Time, after *entity.TransactionCursor, limit int64) ([]entity.TransactionEntity, error)
+	CreateBatch(ctx context.Context, transactions []entity.TransactionEntity) error
+	GetByPaymentIntent(ctx context.Context, paymentIntentID string) ([]entity.TransactionEntity, error)
+	UpdateStatusByPaymentIntent(ctx context.Context, paymentIntentID string, fromStatus string, toStatus string, paidAt *time.Time, reason *string) (int64, error)
+	UpdateStatusByOrder(ctx context.Context, orderID uuid.UUID, fromStatus string, toStatus string, reason *string) (int64, error)
 }
 
 type TransactionRepository struct {
 	db *gorm.DB
 }
 
+// UpdateStatusByOrder implements TransactionRepositoryInterface.
+// Only transactions still in fromStatus are updated; the number of updated rows is returned.
+func (t *TransactionRepository) UpdateStatusByOrder(ctx context.Context, orderID uuid.UUID, fromStatus string, toStatus string, reason *string) (int64, error) {
+
+	updates := map[string]interface{}{"status": toStatus}
+	if reason != nil {
+		updates["failure_reason"] = *reason
+	}
+
+	result := dbFromContext(ctx, t.db).
+		Model(&model.TransactionModel{}).
+		Where("order_id = ? AND status = ?", orderID, fromStatus).
+		Updates(updates)
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("order_id", orderID.String()).Msg("[TransactionRepository-5] UpdateStatusByOrder: failed to update transactions")
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+
+}
+
+// UpdateStatusByPaymentIntent implements TransactionRepositoryInterface.
+// Only transactions still in fromStatus are updated; the number of updated rows is returned.
+func (t *TransactionRepository) UpdateStatusByPaymentIntent(ctx context.Context, paymentIntentID string, fromStatus string, toStatus string, paidAt *time.Time, reason *string) (int64, error) {
+
+	updates := map[string]interface{}{"status": toStatus}
+	if paidAt != nil {
+		updates["paid_at"] = *paidAt
+	}
+	if reason != nil {
+		updates["failure_reason"] = *reason
+	}
+
+	result := dbFromContext(ctx, t.db).
+		Model(&model.TransactionModel{}).
+		Where("payment_intent_id = ? AND status = ?", paymentIntentID, fromStatus).
+		Updates(updates)
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("payment_intent_id", paymentIntentID).Msg("[TransactionRepository-4] UpdateStatusByPaymentIntent: failed to update transactions")
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+
+}
+
+// GetByPaymentIntent implements TransactionRepositoryInterface.
+// The rows are locked until the surrounding transaction ends, so one gateway result is applied at a time.
+func (t *TransactionRepository) GetByPaymentIntent(ctx context.Context, paymentIntentID string) ([]entity.TransactionEntity, error) {
+
+	var transactions []model.TransactionModel
+	err := dbFromContext(ctx, t.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("payment_intent_id = ?", paymentIntentID).
+		Order("merchant_id ASC").
+		Find(&transactions).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("payment_intent_id", paymentIntentID).Msg("[TransactionRepository-3] GetByPaymentIntent: failed to get transactions")
+		return nil, err
+	}
+
+	entities := make([]entity.TransactionEntity, len(transactions))
+	for i, txn := range transactions {
+		entities[i] = toTransactionEntity(txn)
+	}
+
+	return entities, nil
+
+}
+
+// CreateBatch implements TransactionRepositoryInterface.
+func (t *TransactionRepository) CreateBatch(ctx context.Context, transactions []entity.TransactionEntity) error {
+
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	models := make([]model.TransactionModel, len(transactions))
+	for i, txn := range transactions {
+		models[i] = model.TransactionModel{
+			ID:              txn.ID,
+			MerchantID:      txn.MerchantID,
+			OrderID:         txn.OrderID,
+			PaymentIntentID: txn.PaymentIntentID,
+			AmountCents:     int(txn.Amount.Amount),
+			FeeCents:        int(txn.Fee.Amount),
+			Currency:        txn.Amount.Currency,
+			Status:          txn.Status,
+			PaidAt:          txn.PaidAt,
+		}
+	}
+
+	if err := dbFromContext(ctx, t.db).Create(&models).Error; err != nil {
+		log.Error().Err(err).Msg("[TransactionRepository-2] CreateBatch: failed to create transactions")
+		return err
+	}
+
+	return nil
+
+}
+
 // GetBatch implements TransactionRepositoryInterface.
-func (t *TransactionRepository) GetBatch(ctx context.Context, from time.Time, to time.Time, offset int64, limit int64) ([]entity.TransactionEntity, error) {
+// Batches are keyed on (paid_at, id) rather than offsets: after is the last transaction of the previous batch, or
+// nil for the first. Transactions refunded or paid in the range between batches do not shift the next one.
+func (t *TransactionRepository) GetBatch(ctx context.Context, from time.Time, to time.Time, after *entity.TransactionCursor, limit int64) ([]entity.TransactionEntity, error) {
 
 	var transactions []model.TransactionModel
 
-	err := t.db.WithContext(ctx).
-		Where("paid_at >= ? AND paid_at <= ? AND status = ?", from, to, "PAID").
-		Offset(int(offset)).
+	query := dbFromContext(ctx, t.db).
+		Where("paid_at >= ? AND paid_at <= ? AND status = ?", from, to, "PAID")
+	if after != nil {
+		query = query.Where("(paid_at, id) > (?, ?)", after.PaidAt, after.ID)
+	}
+
+	err := query.
 		Limit(int(limit)).
 		Order("paid_at ASC, id ASC").
 		Find(&transactions).Error
@@ -38,14 +155,7 @@ func (t *TransactionRepository) GetBatch(ctx context.Context, from time.Time, to
 
 	entities := make([]entity.TransactionEntity, len(transactions))
 	for i, txn := range transactions {
-		entities[i] = entity.TransactionEntity{
-			ID:          txn.ID,
-			MerchantID:  txn.MerchantID,
-			AmountCents: txn.AmountCents,
-			FeeCents:    txn.FeeCents,
-			Status:      txn.Status,
-			PaidAt:      txn.PaidAt,
-		}
+		entities[i] = toTransactionEntity(txn)
 	}
 
 	return entities, nil
@@ -53,12 +163,13 @@ func (t *TransactionRepository) GetBatch(ctx context.Context, from time.Time, to
 }
 
 // Count implements TransactionRepositoryInterface.
+// Only PAID transactions are counted, matching the rows GetBatch returns.
 func (t *TransactionRepository) Count(ctx context.Context, from time.Time, to time.Time) (int64, error) {
 
 	var count int64
-	err := t.db.WithContext(ctx).
+	err := dbFromContext(ctx, t.db).
 		Model(&model.TransactionModel{}).
-		Where("paid_at >= ? AND paid_at <= ?", from, to).
+		Where("paid_at >= ? AND paid_at <= ? AND status = ?", from, to, "PAID").
 		Count(&count).Error
 
 	if err != nil {
@@ -69,6 +180,21 @@ func (t *TransactionRepository) Count(ctx context.Context, from time.Time, to ti
 	return count, err
 }
 
+func toTransactionEntity(txn model.TransactionModel) entity.TransactionEntity {
+	return entity.TransactionEntity{
+		ID:              txn.ID,
+		MerchantID:      txn.MerchantID,
+		OrderID:         txn.OrderID,
+		PaymentIntentID: txn.PaymentIntentID,
+		Amount:          entity.NewMoney(int64(txn.AmountCents), txn.Currency),
+		Fee:             entity.NewMoney(int64(txn.FeeCents), txn.Currency),
+		Status:          txn.Status,
+		FailureReason:   txn.FailureReason,
+		PaidAt:          txn.PaidAt,
+		CreatedAt:       txn.CreatedAt,
+	}
+}
+
 func NewTransactionRepository(db *gorm.DB) TransactionRepositoryInterface {
 	return &TransactionRepository{db: db}
 }
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"backend-service/internal/core/domain/model"
+	"backend-service/internal/testdb"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// A settlement job paused between batches resumes after the last transaction it read, so refunds of rows it has
+// already read and payments landing before it leave the rest of the scan as it was.
+func TestGetBatchResumesAfterCursor(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewTransactionRepository(db)
+	ctx := context.Background()
+
+	day := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	paid := make([]model.TransactionModel, 6)
+	for i := range paid {
+		paidAt := day.Add(time.Duration(i+1) * time.Hour)
+		paid[i] = model.TransactionModel{MerchantID: "merchant_001", AmountCents: 1000, FeeCents: 10, Status: "PAID", PaidAt: &paidAt}
+	}
+	if err := db.Create(&paid).Error; err != nil {
+		t.Fatalf("seed transactions: %v", err)
+	}
+
+	from, to := day, day.Add(24*time.Hour)
+	first, err := repo.GetBatch(ctx, from, to, nil, 3)
+	if err != nil {
+		t.Fatalf("first batch: %v", err)
+	}
+	assertBatch(t, "first batch", first, paid[0].ID, paid[1].ID, paid[2].ID)
+
+	// Between batches one read row is refunded and a payment lands before the cursor.
+	if err := db.Model(&model.TransactionModel{}).Where("id = ?", paid[0].ID).Update("status", "REFUNDED").Error; err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+	earlier := day.Add(30 * time.Minute)
+	if err := db.Create(&model.TransactionModel{MerchantID: "merchant_001", AmountCents: 500, FeeCents: 5, Status: "PAID", PaidAt: &earlier}).Error; err != nil {
+		t.Fatalf("late payment: %v", err)
+	}
+
+	last := first[len(first)-1]
+	second, err := repo.GetBatch(ctx, from, to, &entity.TransactionCursor{PaidAt: *last.PaidAt, ID: last.ID}, 3)
+	if err != nil {
+		t.Fatalf("second batch: %v", err)
+	}
+	assertBatch(t, "second batch", second, paid[3].ID, paid[4].ID, paid[5].ID)
+}
+
+func assertBatch(t *testing.T, name string, got []entity.TransactionEntity, want ...uuid.UUID) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: got %d transactions, want %d", name, len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID != want[i] {
+			t.Errorf("%s[%d] = %s, want %s", name, i, got[i].ID, want[i])
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+type TxManagerInterface interface {
+	// WithinTransaction runs fn in a database transaction. Repositories called with the ctx passed to fn
+	// join that transaction, so all their writes commit or roll back together.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type TxManager struct {
+	db *gorm.DB
+}
+
+// WithinTransaction implements TxManagerInterface.
+func (t *TxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	// Already inside a unit of work: join it instead of opening a second transaction.
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[TxManager] WithinTransaction: transaction rolled back")
+		return err
+	}
+
+	return nil
+}
+
+// dbFromContext returns the transaction bound to ctx, or db when ctx carries none.
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+func NewTxManager(db *gorm.DB) TxManagerInterface {
+	return &TxManager{db: db}
+}
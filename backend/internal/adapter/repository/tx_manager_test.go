@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"backend-service/internal/core/domain/model"
+	"backend-service/internal/testdb"
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+var errInjected = errors.New("injected fault")
+
+func seedStock(t *testing.T, db *gorm.DB, stock int) uint {
+	t.Helper()
+
+	product := model.ProductModel{Name: "Kopi", Stock: stock}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("seed product: %v", err)
+	}
+	return product.ID
+}
+
+func assertStock(t *testing.T, db *gorm.DB, productID uint, stock int, movements int64) {
+	t.Helper()
+
+	var product model.ProductModel
+	if err := db.First(&product, productID).Error; err != nil {
+		t.Fatalf("read product: %v", err)
+	}
+	if product.Stock != stock {
+		t.Errorf("stock = %d, want %d", product.Stock, stock)
+	}
+
+	var n int64
+	if err := db.Model(&model.InventoryMovementModel{}).Where("product_id = ?", productID).Count(&n).Error; err != nil {
+		t.Fatalf("count movements: %v", err)
+	}
+	if n != movements {
+		t.Errorf("inventory movements = %d, want %d", n, movements)
+	}
+}
+
+func TestWithinTransactionRollsBackStockUpdate(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault error
+		stock int
+		moved int64
+	}{
+		{"fault after the update", errInjected, 10, 0},
+		{"no fault", nil, 7, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := testdb.Open(t)
+			productRepo := NewProductRepository(db)
+			productID := seedStock(t, db, 10)
+
+			err := NewTxManager(db).WithinTransaction(context.Background(), func(ctx context.Context) error {
+				if _, err := productRepo.UpdateStock(ctx, entity.InventoryMovementEntity{ProductID: productID, Type: "ORDER", Quantity: -3}); err != nil {
+					return err
+				}
+				return tt.fault
+			})
+			if !errors.Is(err, tt.fault) {
+				t.Fatalf("WithinTransaction error = %v, want %v", err, tt.fault)
+			}
+
+			assertStock(t, db, productID, tt.stock, tt.moved)
+		})
+	}
+}
+
+// A nested unit of work joins the outer transaction, so its writes roll back when the outer one fails after it.
+func TestWithinTransactionJoinsOuterTransaction(t *testing.T) {
+	db := testdb.Open(t)
+	productRepo := NewProductRepository(db)
+	txManager := NewTxManager(db)
+	productID := seedStock(t, db, 10)
+
+	err := txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := productRepo.UpdateStock(ctx, entity.InventoryMovementEntity{ProductID: productID, Type: "ORDER", Quantity: -3})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		return errInjected
+	})
+	if !errors.Is(err, errInjected) {
+		t.Fatalf("WithinTransaction error = %v, want %v", err, errInjected)
+	}
+
+	assertStock(t, db, productID, 10, 0)
+}
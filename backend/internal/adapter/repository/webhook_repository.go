@@ -0,0 +1,398 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/domain/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepositoryInterface interface {
+	CreateSubscription(ctx context.Context, subscription entity.WebhookSubscriptionEntity) (*entity.WebhookSubscriptionEntity, error)
+	GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*entity.WebhookSubscriptionEntity, error)
+	ListSubscriptions(ctx context.Context, activeOnly bool) ([]entity.WebhookSubscriptionEntity, error)
+	DeactivateSubscription(ctx context.Context, subscriptionID uuid.UUID) error
+	CreateDeliveries(ctx context.Context, deliveries []entity.WebhookDeliveryEntity) error
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDeliveryEntity, error)
+	LeaseDeliveries(ctx context.Context, deliveryIDs []uuid.UUID, until time.Time) error
+	RecordAttempt(ctx context.Context, delivery entity.WebhookDeliveryEntity, attempt entity.WebhookDeliveryAttemptEntity, leasedUntil time.Time) error
+	GetDelivery(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDeliveryEntity, error)
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]entity.WebhookDeliveryEntity, error)
+	Redeliver(ctx context.Context, deliveryID uuid.UUID, now time.Time) error
+}
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// Redeliver implements WebhookRepositoryInterface.
+// The delivery is queued again with a fresh attempt budget; its attempt log is kept.
+func (w *WebhookRepository) Redeliver(ctx context.Context, deliveryID uuid.UUID, now time.Time) error {
+
+	result := dbFromContext(ctx, w.db).
+		Model(&model.WebhookDeliveryModel{}).
+		Where("id = ?", deliveryID).
+		Updates(map[string]interface{}{
+			"status":          "PENDING",
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("delivery_id", deliveryID.String()).Msg("[WebhookRepository-10] Redeliver: failed to queue redelivery")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+
+}
+
+// ListDeliveries implements WebhookRepositoryInterface.
+// Deliveries are returned newest first, without their attempt log.
+func (w *WebhookRepository) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]entity.WebhookDeliveryEntity, error) {
+
+	var deliveries []model.WebhookDeliveryModel
+	err := dbFromContext(ctx, w.db).
+		Where("subscription_id = ?", subscriptionID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+
+	if err != nil {
+		log.Error().Err(err).Str("subscription_id", subscriptionID.String()).Msg("[WebhookRepository-9] ListDeliveries: failed to list deliveries")
+		return nil, err
+	}
+
+	entities := make([]entity.WebhookDeliveryEntity, len(deliveries))
+	for i, delivery := range deliveries {
+		entities[i] = toWebhookDeliveryEntity(delivery)
+	}
+
+	return entities, nil
+
+}
+
+// GetDelivery implements WebhookRepositoryInterface.
+// The delivery comes with its attempt log, oldest attempt first.
+func (w *WebhookRepository) GetDelivery(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDeliveryEntity, error) {
+
+	var delivery model.WebhookDeliveryModel
+	err := dbFromContext(ctx, w.db).
+		Preload("AttemptLog", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&delivery, "id = ?", deliveryID).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrWebhookDeliveryNotFound
+		}
+		log.Error().Err(err).Str("delivery_id", deliveryID.String()).Msg("[WebhookRepository-8] GetDelivery: failed to get delivery")
+		return nil, err
+	}
+
+	result := toWebhookDeliveryEntity(delivery)
+	return &result, nil
+
+}
+
+// RecordAttempt implements WebhookRepositoryInterface.
+// It appends attempt to the log and stores the delivery's new status, attempt count and schedule. The status is
+// left as it is when the delivery is no longer under the lease taken until leasedUntil: redelivered meanwhile, or
+// taken over by another instance once the lease ran out.
+func (w *WebhookRepository) RecordAttempt(ctx context.Context, delivery entity.WebhookDeliveryEntity, attempt entity.WebhookDeliveryAttemptEntity, leasedUntil time.Time) error {
+
+	return dbFromContext(ctx, w.db).Transaction(func(tx *gorm.DB) error {
+		attemptModel := model.WebhookDeliveryAttemptModel{
+			DeliveryID:   delivery.ID,
+			StatusCode:   attempt.StatusCode,
+			Error:        attempt.Error,
+			ResponseBody: attempt.ResponseBody,
+			DurationMs:   int(attempt.Duration.Milliseconds()),
+		}
+		if err := tx.Create(&attemptModel).Error; err != nil {
+			log.Error().Err(err).Str("delivery_id", delivery.ID.String()).Msg("[WebhookRepository-6] RecordAttempt: failed to log attempt")
+			return err
+		}
+
+		err := tx.Model(&model.WebhookDeliveryModel{}).
+			Where("id = ? AND next_attempt_at = ?", delivery.ID, leasedUntil).
+			Updates(map[string]interface{}{
+				"status":           delivery.Status,
+				"attempts":         delivery.Attempts,
+				"next_attempt_at":  delivery.NextAttemptAt,
+				"last_status_code": delivery.LastStatusCode,
+				"last_error":       delivery.LastError,
+				"delivered_at":     delivery.DeliveredAt,
+			}).Error
+		if err != nil {
+			log.Error().Err(err).Str("delivery_id", delivery.ID.String()).Msg("[WebhookRepository-7] RecordAttempt: failed to update delivery")
+			return err
+		}
+
+		return nil
+	})
+
+}
+
+// LeaseDeliveries implements WebhookRepositoryInterface.
+// The deliveries are not due again until until, which also identifies the lease to RecordAttempt; it must
+// therefore be truncated to the microsecond the database stores.
+func (w *WebhookRepository) LeaseDeliveries(ctx context.Context, deliveryIDs []uuid.UUID, until time.Time) error {
+
+	if len(deliveryIDs) == 0 {
+		return nil
+	}
+
+	err := dbFromContext(ctx, w.db).
+		Model(&model.WebhookDeliveryModel{}).
+		Where("id IN ?", deliveryIDs).
+		Update("next_attempt_at", until).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookRepository-13] LeaseDeliveries: failed to lease deliveries")
+		return err
+	}
+
+	return nil
+
+}
+
+// GetDueDeliveries implements WebhookRepositoryInterface.
+// Rows locked by another deliverer are skipped, so several instances can deliver concurrently. Each delivery
+// carries its subscription, which is read without a lock.
+func (w *WebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDeliveryEntity, error) {
+
+	db := dbFromContext(ctx, w.db)
+
+	var deliveries []model.WebhookDeliveryModel
+	err := db.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? AND next_attempt_at <= ?", "PENDING", now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookRepository-5] GetDueDeliveries: failed to get due deliveries")
+		return nil, err
+	}
+
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+
+	subscriptionIDs := make([]uuid.UUID, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		subscriptionIDs = append(subscriptionIDs, delivery.SubscriptionID)
+	}
+
+	var subscriptions []model.WebhookSubscriptionModel
+	if err := db.Where("id IN ?", subscriptionIDs).Find(&subscriptions).Error; err != nil {
+		log.Error().Err(err).Msg("[WebhookRepository-12] GetDueDeliveries: failed to get subscriptions")
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]entity.WebhookSubscriptionEntity, len(subscriptions))
+	for _, subscription := range subscriptions {
+		byID[subscription.ID] = toWebhookSubscriptionEntity(subscription)
+	}
+
+	entities := make([]entity.WebhookDeliveryEntity, len(deliveries))
+	for i, delivery := range deliveries {
+		entities[i] = toWebhookDeliveryEntity(delivery)
+		if subscription, ok := byID[delivery.SubscriptionID]; ok {
+			entities[i].Subscription = &subscription
+		}
+	}
+
+	return entities, nil
+
+}
+
+// CreateDeliveries implements WebhookRepositoryInterface.
+// A delivery that already exists for the same subscription and event is left as it is.
+func (w *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []entity.WebhookDeliveryEntity) error {
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	models := make([]model.WebhookDeliveryModel, len(deliveries))
+	for i, delivery := range deliveries {
+		models[i] = model.WebhookDeliveryModel{
+			SubscriptionID: delivery.SubscriptionID,
+			EventID:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Payload:        delivery.Payload,
+			Status:         "PENDING",
+			NextAttemptAt:  delivery.NextAttemptAt,
+		}
+	}
+
+	err := dbFromContext(ctx, w.db).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+		Omit("Subscription", "AttemptLog").
+		Create(&models).Error
+
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookRepository-4] CreateDeliveries: failed to create deliveries")
+		return err
+	}
+
+	return nil
+
+}
+
+// DeactivateSubscription implements WebhookRepositoryInterface.
+func (w *WebhookRepository) DeactivateSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+
+	result := dbFromContext(ctx, w.db).
+		Model(&model.WebhookSubscriptionModel{}).
+		Where("id = ?", subscriptionID).
+		Update("active", false)
+
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("subscription_id", subscriptionID.String()).Msg("[WebhookRepository-3] DeactivateSubscription: failed to deactivate subscription")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrWebhookNotFound
+	}
+
+	return nil
+
+}
+
+// ListSubscriptions implements WebhookRepositoryInterface.
+func (w *WebhookRepository) ListSubscriptions(ctx context.Context, activeOnly bool) ([]entity.WebhookSubscriptionEntity, error) {
+
+	query := dbFromContext(ctx, w.db).Order("created_at ASC")
+	if activeOnly {
+		query = query.Where("active = ?", true)
+	}
+
+	var subscriptions []model.WebhookSubscriptionModel
+	if err := query.Find(&subscriptions).Error; err != nil {
+		log.Error().Err(err).Msg("[WebhookRepository-2] ListSubscriptions: failed to list subscriptions")
+		return nil, err
+	}
+
+	entities := make([]entity.WebhookSubscriptionEntity, len(subscriptions))
+	for i, subscription := range subscriptions {
+		entities[i] = toWebhookSubscriptionEntity(subscription)
+	}
+
+	return entities, nil
+
+}
+
+// GetSubscription implements WebhookRepositoryInterface.
+func (w *WebhookRepository) GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*entity.WebhookSubscriptionEntity, error) {
+
+	var subscription model.WebhookSubscriptionModel
+	if err := dbFromContext(ctx, w.db).First(&subscription, "id = ?", subscriptionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errs.ErrWebhookNotFound
+		}
+		log.Error().Err(err).Str("subscription_id", subscriptionID.String()).Msg("[WebhookRepository-1] GetSubscription: failed to get subscription")
+		return nil, err
+	}
+
+	result := toWebhookSubscriptionEntity(subscription)
+	return &result, nil
+
+}
+
+// CreateSubscription implements WebhookRepositoryInterface.
+func (w *WebhookRepository) CreateSubscription(ctx context.Context, subscription entity.WebhookSubscriptionEntity) (*entity.WebhookSubscriptionEntity, error) {
+
+	eventTypes, err := json.Marshal(subscription.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptionModel := model.WebhookSubscriptionModel{
+		URL:        subscription.URL,
+		Secret:     subscription.Secret,
+		EventTypes: string(eventTypes),
+		Active:     true,
+	}
+
+	if err := dbFromContext(ctx, w.db).Create(&subscriptionModel).Error; err != nil {
+		log.Error().Err(err).Str("url", subscription.URL).Msg("[WebhookRepository-11] CreateSubscription: failed to create subscription")
+		return nil, err
+	}
+
+	result := toWebhookSubscriptionEntity(subscriptionModel)
+	return &result, nil
+
+}
+
+func toWebhookSubscriptionEntity(subscription model.WebhookSubscriptionModel) entity.WebhookSubscriptionEntity {
+	var eventTypes []string
+	if err := json.Unmarshal([]byte(subscription.EventTypes), &eventTypes); err != nil {
+		log.Error().Err(err).Str("subscription_id", subscription.ID.String()).Msg("malformed webhook event types")
+	}
+
+	return entity.WebhookSubscriptionEntity{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		Secret:     subscription.Secret,
+		EventTypes: eventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+		UpdatedAt:  subscription.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryEntity(delivery model.WebhookDeliveryModel) entity.WebhookDeliveryEntity {
+	attempts := make([]entity.WebhookDeliveryAttemptEntity, len(delivery.AttemptLog))
+	for i, attempt := range delivery.AttemptLog {
+		attempts[i] = entity.WebhookDeliveryAttemptEntity{
+			ID:           attempt.ID,
+			DeliveryID:   attempt.DeliveryID,
+			StatusCode:   attempt.StatusCode,
+			Error:        attempt.Error,
+			ResponseBody: attempt.ResponseBody,
+			Duration:     time.Duration(attempt.DurationMs) * time.Millisecond,
+			CreatedAt:    attempt.CreatedAt,
+		}
+	}
+
+	return entity.WebhookDeliveryEntity{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+		AttemptLog:     attempts,
+	}
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepositoryInterface {
+	return &WebhookRepository{db: db}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"backend-service/internal/testdb"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// An attempt finishing after its delivery was redelivered is logged, but the redelivery stays queued.
+func TestRecordAttemptKeepsDeliveryLeasedToAnother(t *testing.T) {
+	db := testdb.Open(t)
+	repo := NewWebhookRepository(db)
+	ctx := context.Background()
+
+	subscription, err := repo.CreateSubscription(ctx, entity.WebhookSubscriptionEntity{URL: "https://example.com/hook", Secret: "whsec_test", EventTypes: []string{"*"}, Active: true})
+	if err != nil {
+		t.Fatalf("create subscription: %v", err)
+	}
+	if err := repo.CreateDeliveries(ctx, []entity.WebhookDeliveryEntity{{SubscriptionID: subscription.ID, EventID: uuid.New(), EventType: entity.EventOrderCreated, Payload: "{}", NextAttemptAt: time.Now()}}); err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+
+	due, err := repo.GetDueDeliveries(ctx, time.Now(), 10)
+	if err != nil || len(due) != 1 {
+		t.Fatalf("GetDueDeliveries = %d deliveries, %v; want 1", len(due), err)
+	}
+	delivery := due[0]
+
+	leasedUntil := time.Now().Add(time.Minute).Truncate(time.Microsecond)
+	if err := repo.LeaseDeliveries(ctx, []uuid.UUID{delivery.ID}, leasedUntil); err != nil {
+		t.Fatalf("LeaseDeliveries: %v", err)
+	}
+	if due, err := repo.GetDueDeliveries(ctx, time.Now(), 10); err != nil || len(due) != 0 {
+		t.Fatalf("GetDueDeliveries while leased = %d deliveries, %v; want none", len(due), err)
+	}
+
+	if err := repo.Redeliver(ctx, delivery.ID, time.Now()); err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+
+	message := "receiver responded with status 500"
+	delivery.Status, delivery.Attempts, delivery.LastError = "FAILED", 8, &message
+	if err := repo.RecordAttempt(ctx, delivery, entity.WebhookDeliveryAttemptEntity{Error: &message}, leasedUntil); err != nil {
+		t.Fatalf("RecordAttempt: %v", err)
+	}
+
+	got, err := repo.GetDelivery(ctx, delivery.ID)
+	if err != nil {
+		t.Fatalf("GetDelivery: %v", err)
+	}
+	if got.Status != "PENDING" || got.Attempts != 0 {
+		t.Errorf("delivery is %s after %d attempts, want PENDING after 0", got.Status, got.Attempts)
+	}
+	if len(got.AttemptLog) != 1 {
+		t.Errorf("logged %d attempts, want 1", len(got.AttemptLog))
+	}
+}
@@ -6,19 +6,61 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(orderHandler handler.OrderHandlerInterface, jobHandler handler.JobHandlerInterface) *gin.Engine {
+func SetupRouter(orderHandler handler.OrderHandlerInterface, jobHandler handler.JobHandlerInterface, productHandler handler.ProductHandlerInterface, cartHandler handler.CartHandlerInterface, admissionHandler handler.AdmissionHandlerInterface, promotionHandler handler.PromotionHandlerInterface, paymentHandler handler.PaymentHandlerInterface, webhookHandler handler.WebhookHandlerInterface, jobScheduleHandler handler.JobScheduleHandlerInterface, idempotency gin.HandlerFunc) *gin.Engine {
 	r := gin.Default()
 
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
-	r.POST("/orders", orderHandler.CreateOrder)
+	r.GET("/orders", orderHandler.ListOrders)
+	r.POST("/orders", idempotency, orderHandler.CreateOrder)
 	r.GET("/orders/:orderID", orderHandler.GetOrderByID)
+	r.POST("/orders/:orderID/cancel", orderHandler.CancelOrder)
 
-	r.POST("/jobs/settlement", jobHandler.CreateSettlementJob)
+	r.POST("/queue", admissionHandler.Enqueue)
+	r.GET("/queue/:token", admissionHandler.GetStatus)
+
+	r.GET("/buyers/:buyerID/orders", orderHandler.ListBuyerOrders)
+	r.GET("/buyers/:buyerID/cart", cartHandler.GetCart)
+	r.PUT("/buyers/:buyerID/cart/items/:productID", cartHandler.SetItem)
+	r.DELETE("/buyers/:buyerID/cart/items/:productID", cartHandler.RemoveItem)
+	r.POST("/buyers/:buyerID/cart/checkout", idempotency, cartHandler.Checkout)
+
+	r.GET("/products/:productID", productHandler.GetProduct)
+	r.PUT("/products/:productID/sale-window", productHandler.SetSaleWindow)
+	r.GET("/products/:productID/stock-history", productHandler.GetStockHistory)
+	r.POST("/products/:productID/stock-adjustments", productHandler.AdjustStock)
+	r.PUT("/products/:productID/purchase-limit", productHandler.SetPurchaseLimit)
+
+	r.POST("/promotions", promotionHandler.CreatePromotion)
+	r.GET("/promotions/:code", promotionHandler.GetPromotion)
+
+	r.POST("/payments/:intentID/simulate", paymentHandler.SimulatePayment)
+
+	r.POST("/webhooks", webhookHandler.CreateWebhook)
+	r.GET("/webhooks", webhookHandler.ListWebhooks)
+	r.GET("/webhooks/:webhookID", webhookHandler.GetWebhook)
+	r.DELETE("/webhooks/:webhookID", webhookHandler.DeleteWebhook)
+	r.GET("/webhooks/:webhookID/deliveries", webhookHandler.ListDeliveries)
+	r.GET("/webhook-deliveries/:deliveryID", webhookHandler.GetDelivery)
+	r.POST("/webhook-deliveries/:deliveryID/redeliver", webhookHandler.Redeliver)
+
+	r.POST("/jobs/settlement", idempotency, jobHandler.CreateSettlementJob)
+	r.GET("/jobs/queue", jobHandler.GetQueue)
 	r.GET("/jobs/:jobID", jobHandler.GetJob)
+	r.GET("/jobs/:jobID/events", jobHandler.StreamJobEvents)
 	r.POST("/jobs/:jobID/cancel", jobHandler.CancelJob)
+	r.POST("/jobs/:jobID/pause", jobHandler.PauseJob)
+	r.POST("/jobs/:jobID/resume", jobHandler.ResumeJob)
+	r.GET("/ws/jobs", jobHandler.JobDashboard)
+
+	r.POST("/job-schedules", jobScheduleHandler.CreateSchedule)
+	r.GET("/job-schedules", jobScheduleHandler.ListSchedules)
+	r.GET("/job-schedules/:scheduleID", jobScheduleHandler.GetSchedule)
+	r.PUT("/job-schedules/:scheduleID", jobScheduleHandler.UpdateSchedule)
+	r.DELETE("/job-schedules/:scheduleID", jobScheduleHandler.DeleteSchedule)
+	r.GET("/job-schedules/:scheduleID/runs", jobScheduleHandler.ListRuns)
 
 	return r
 }
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// maxResponseBody caps how much of a receiver's response is kept in the delivery log.
+const maxResponseBody = 1024
+
+// Result describes one delivery attempt. Err is set when no response was received.
+type Result struct {
+	StatusCode   int
+	ResponseBody string
+	Duration     time.Duration
+	Err          error
+}
+
+// Succeeded reports whether the receiver acknowledged the delivery with a 2xx response.
+func (r Result) Succeeded() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode <= 299
+}
+
+type ClientInterface interface {
+	Send(ctx context.Context, url string, secret string, headers map[string]string, body []byte) Result
+}
+
+type Client struct {
+	httpClient *http.Client
+}
+
+// Send implements ClientInterface.
+// The body is POSTed as JSON and signed with secret in SignatureHeader.
+func (c *Client) Send(ctx context.Context, url string, secret string, headers map[string]string, body []byte) Result {
+
+	started := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return Result{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "backend-service-webhooks/1")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set(SignatureHeader, Sign(secret, started, body))
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Result{Duration: time.Since(started), Err: err}
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return Result{
+		StatusCode:   resp.StatusCode,
+		ResponseBody: string(responseBody),
+		Duration:     time.Since(started),
+	}
+}
+
+func NewClient(timeout time.Duration) ClientInterface {
+	return &Client{
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
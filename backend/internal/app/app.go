@@ -2,14 +2,19 @@ package app
 
 import (
 	"backend-service/config"
+	"backend-service/internal/adapter/eventsink"
+	"backend-service/internal/adapter/gateway"
 	"backend-service/internal/adapter/handler"
+	"backend-service/internal/adapter/middleware"
 	"backend-service/internal/adapter/repository"
 	"backend-service/internal/adapter/router"
+	"backend-service/internal/adapter/webhook"
 	"backend-service/internal/core/service"
 	"backend-service/internal/logger"
 	"backend-service/pkg/validator"
 	"context"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -24,8 +29,8 @@ func RunServer() {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://127.0.0.1:3000"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", middleware.IdempotencyKeyHeader, handler.QueueTokenHeader},
+		ExposeHeaders:    []string{"Content-Length", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -45,28 +50,182 @@ func RunServer() {
 
 	logger.InitLogger()
 
+	txManager := repository.NewTxManager(db.DB)
 	productRepo := repository.NewProductRepository(db.DB)
 	orderRepo := repository.NewOrderRepository(db.DB)
 	jobRepo := repository.NewJobRepository(db.DB)
 	transactionRepo := repository.NewTransactionRepository(db.DB)
 	settlementRepo := repository.NewSettlementRepository(db.DB)
+	inventoryMovementRepo := repository.NewInventoryMovementRepository(db.DB)
+	idempotencyKeyRepo := repository.NewIdempotencyKeyRepository(db.DB)
+	cartRepo := repository.NewCartRepository(db.DB)
+	promotionRepo := repository.NewPromotionRepository(db.DB)
+	outboxRepo := repository.NewOutboxRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	jobScheduleRepo := repository.NewJobScheduleRepository(db.DB)
 
-	orderService := service.NewOrderService(orderRepo, productRepo)
-	jobService := service.NewJobService(cfg, jobRepo, transactionRepo, settlementRepo)
+	// The waiting room is opt-in; when it is disabled orders need no queue token.
+	var admissionService service.AdmissionServiceInterface
+	if cfg.Admission.Enabled {
+		admissionRepo := repository.NewAdmissionMemoryRepository()
+		if cfg.Admission.Backend == "postgres" {
+			admissionRepo = repository.NewAdmissionRepository(db.DB)
+		}
+		admissionService = service.NewAdmissionService(cfg, admissionRepo)
+	}
+
+	paymentGateway := gateway.NewPaymentSimulator(cfg)
+
+	orderService := service.NewOrderService(cfg, orderRepo, productRepo, promotionRepo, transactionRepo, outboxRepo, txManager, paymentGateway, admissionService)
+	jobService := service.NewJobService(cfg, jobRepo, transactionRepo, settlementRepo, outboxRepo, txManager)
+	jobScheduleService := service.NewJobScheduleService(cfg, jobScheduleRepo, jobService, txManager)
+	productService := service.NewProductService(productRepo, inventoryMovementRepo, outboxRepo, txManager)
+	idempotencyService := service.NewIdempotencyService(cfg, idempotencyKeyRepo)
+	cartService := service.NewCartService(cartRepo, productRepo, orderService, txManager, paymentGateway)
+	promotionService := service.NewPromotionService(promotionRepo, productRepo)
+	webhookTimeout := 10 * time.Second
+	if cfg.Webhooks.Timeout > 0 {
+		webhookTimeout = cfg.Webhooks.Timeout
+	}
+	webhookService := service.NewWebhookService(cfg, webhookRepo, txManager, webhook.NewClient(webhookTimeout))
+
+	// Webhook subscriptions are fed by the outbox like any other sink.
+	outboxRelay := service.NewOutboxRelay(cfg, outboxRepo, txManager, append(newEventSinks(cfg), webhookService)...)
+	paymentService := service.NewPaymentService(transactionRepo, orderService, txManager, paymentGateway)
+
+	// Gateway results complete or cancel orders; register before the first intent can settle.
+	paymentGateway.OnResult(paymentService.HandlePaymentResult)
 
 	orderHandler := handler.NewOrderHandler(orderService, customValidator)
 	jobHandler := handler.NewJobHandler(jobService, customValidator)
+	productHandler := handler.NewProductHandler(productService, customValidator)
+	cartHandler := handler.NewCartHandler(cartService, customValidator)
+	admissionHandler := handler.NewAdmissionHandler(admissionService, customValidator)
+	promotionHandler := handler.NewPromotionHandler(promotionService, customValidator)
+	paymentHandler := handler.NewPaymentHandler(paymentService, customValidator)
+	webhookHandler := handler.NewWebhookHandler(webhookService, customValidator)
+	jobScheduleHandler := handler.NewJobScheduleHandler(jobScheduleService, customValidator)
 
-	r = router.SetupRouter(orderHandler, jobHandler)
+	r = router.SetupRouter(orderHandler, jobHandler, productHandler, cartHandler, admissionHandler, promotionHandler, paymentHandler, webhookHandler, jobScheduleHandler, middleware.Idempotency(idempotencyService))
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	sweepInterval := 30 * time.Second
+	if cfg.Orders.ReservationSweepInterval > 0 {
+		sweepInterval = cfg.Orders.ReservationSweepInterval
+	}
+	jobService.AddPeriodicTask(service.PeriodicTask{
+		Name:     "order-reservation-sweeper",
+		Interval: sweepInterval,
+		Run: func(ctx context.Context) error {
+			_, err := orderService.ReleaseExpiredReservations(ctx)
+			return err
+		},
+	})
+
+	relayInterval := time.Second
+	if cfg.Outbox.PollInterval > 0 {
+		relayInterval = cfg.Outbox.PollInterval
+	}
+	jobService.AddPeriodicTask(service.PeriodicTask{
+		Name:     "outbox-relay",
+		Interval: relayInterval,
+		Run: func(ctx context.Context) error {
+			_, err := outboxRelay.Relay(ctx)
+			return err
+		},
+	})
+
+	webhookInterval := time.Second
+	if cfg.Webhooks.PollInterval > 0 {
+		webhookInterval = cfg.Webhooks.PollInterval
+	}
+	jobService.AddPeriodicTask(service.PeriodicTask{
+		Name:     "webhook-deliverer",
+		Interval: webhookInterval,
+		Run: func(ctx context.Context) error {
+			_, err := webhookService.DeliverDue(ctx)
+			return err
+		},
+	})
+
+	// Schedules are claimed with row locks, so every replica can run the scheduler.
+	schedulerInterval := 30 * time.Second
+	if cfg.Scheduler.PollInterval > 0 {
+		schedulerInterval = cfg.Scheduler.PollInterval
+	}
+	jobService.AddPeriodicTask(service.PeriodicTask{
+		Name:     "job-scheduler",
+		Interval: schedulerInterval,
+		Run: func(ctx context.Context) error {
+			_, err := jobScheduleService.FireDue(ctx)
+			return err
+		},
+	})
+
+	// Each replica stops the overdue jobs it runs and takes over the ones whose replica is gone.
+	watchdogInterval := time.Minute
+	if cfg.WORKERS.WatchdogInterval > 0 {
+		watchdogInterval = cfg.WORKERS.WatchdogInterval
+	}
+	jobService.AddPeriodicTask(service.PeriodicTask{
+		Name:     "job-watchdog",
+		Interval: watchdogInterval,
+		Run: func(ctx context.Context) error {
+			_, err := jobService.RunWatchdog(ctx)
+			return err
+		},
+	})
+
+	if admissionService != nil {
+		jobService.AddPeriodicTask(service.PeriodicTask{
+			Name:     "admission-admitter",
+			Interval: time.Second,
+			Run:      admissionService.Admit,
+		})
+	}
+
 	go jobService.StartWorkerPool(ctx)
 	log.Println("Settlement worker pool started")
 
+	go idempotencyService.StartCleanup(ctx)
+
 	log.Printf("Starting server on port %s", cfg.App.Port)
 	if err := r.Run(":" + cfg.App.Port); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// newEventSinks builds the sinks named in OUTBOX_SINKS, defaulting to the log sink.
+func newEventSinks(cfg *config.Config) []eventsink.EventSinkInterface {
+	names := "log"
+	if cfg.Outbox.Sinks != "" {
+		names = cfg.Outbox.Sinks
+	}
+
+	webhookTimeout := 5 * time.Second
+	if cfg.Outbox.WebhookTimeout > 0 {
+		webhookTimeout = cfg.Outbox.WebhookTimeout
+	}
+
+	var sinks []eventsink.EventSinkInterface
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "log":
+			sinks = append(sinks, eventsink.NewLogSink())
+		case "webhook":
+			if cfg.Outbox.WebhookURL == "" {
+				log.Fatal("OUTBOX_WEBHOOK_URL is required for the webhook sink")
+			}
+			sinks = append(sinks, eventsink.NewWebhookSink(cfg.Outbox.WebhookURL, webhookTimeout))
+		case "memory":
+			sinks = append(sinks, eventsink.NewMemoryBus())
+		case "":
+		default:
+			log.Fatalf("unknown outbox sink %q", name)
+		}
+	}
+
+	return sinks
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AdmissionTokenEntity struct {
+	Token      uuid.UUID
+	BuyerID    string
+	Status     string
+	Position   int64
+	AdmittedAt *time.Time
+	ExpiresAt  *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+type CartItemEntity struct {
+	BuyerID   string
+	ProductID uint
+	Quantity  int
+	UpdatedAt time.Time
+	Product   *ProductEntity
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+type IdempotencyKeyEntity struct {
+	Key          string
+	Scope        string
+	RequestHash  string
+	Status       string
+	ResponseCode int
+	ResponseBody []byte
+	ExpiresAt    time.Time
+}
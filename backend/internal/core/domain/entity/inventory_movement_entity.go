@@ -0,0 +1,25 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InventoryMovementEntity struct {
+	ID         uuid.UUID
+	ProductID  uint
+	OrderID    *uuid.UUID
+	Type       string
+	Quantity   int
+	StockAfter int
+	Reason     *string
+	CreatedAt  time.Time
+}
+
+type StockHistoryEntity struct {
+	ProductID    uint
+	CurrentStock int
+	LedgerStock  int
+	Movements    []InventoryMovementEntity
+}
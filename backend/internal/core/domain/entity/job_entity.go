@@ -6,10 +6,18 @@ import (
 	"github.com/google/uuid"
 )
 
+// Job priorities run from JobPriorityMin to JobPriorityMax; higher priorities are started first.
+const (
+	JobPriorityMin     = 0
+	JobPriorityDefault = 5
+	JobPriorityMax     = 9
+)
+
 type JobEntity struct {
 	ID           uuid.UUID
 	Type         string
 	Status       string
+	Priority     int
 	Progress     int
 	Processed    int64
 	Total        int64
@@ -18,8 +26,58 @@ type JobEntity struct {
 	ErrorMessage *string
 	UniqueRunID  *string
 	Cancelled    bool
-	StartedAt    *time.Time
-	CompletedAt  *time.Time
+	// Checkpoint is the JSON of a SettlementCheckpoint once the job has been paused while running.
+	Checkpoint *string
+	// Timeout is the job's own max runtime, or zero when it takes its type's. Each run gets the whole of it, ending
+	// by DeadlineAt; HeartbeatAt is when the running job last made progress. Requeues counts the runs the watchdog
+	// queued again after they timed out.
+	Timeout     time.Duration
+	DeadlineAt  *time.Time
+	HeartbeatAt *time.Time
+	Requeues    int
+	// Throughput is in rows per second, averaged from the start of the job, or from when it last resumed, to its
+	// last progress update.
+	Throughput  float64
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	// EstimatedStartAt and EstimatedCompletionAt are not stored: they are derived from the job's own rate while
+	// it runs, or from queue depth and the rate past jobs of its type sustained while it waits.
+	EstimatedStartAt      *time.Time
+	EstimatedCompletionAt *time.Time
+}
+
+// Elapsed is how long the job has been running, or ran for once finished; zero before it starts.
+func (j JobEntity) Elapsed(now time.Time) time.Duration {
+	if j.StartedAt == nil {
+		return 0
+	}
+	if j.CompletedAt != nil {
+		return j.CompletedAt.Sub(*j.StartedAt)
+	}
+	return now.Sub(*j.StartedAt)
+}
+
+// IsTerminal reports whether the job has reached a state it never leaves.
+func (j JobEntity) IsTerminal() bool {
+	return j.Status == "COMPLETED" || j.Status == "FAILED" || j.Status == "CANCELLED"
+}
+
+// Job lifecycle steps published to live subscribers.
+const (
+	JobUpdateQueued    = "queued"
+	JobUpdateStarted   = "started"
+	JobUpdateProgress  = "progress"
+	JobUpdateCompleted = "completed"
+	JobUpdateFailed    = "failed"
+	JobUpdateCancelled = "cancelled"
+	JobUpdatePaused    = "paused"
+)
+
+// JobUpdateEntity is one lifecycle step of a job together with the job's state right after it.
+type JobUpdateEntity struct {
+	Event string
+	Job   JobEntity
 }
 
 type SettlementJobParams struct {
@@ -27,11 +85,66 @@ type SettlementJobParams struct {
 	To   string
 }
 
+// SettlementJob is a settlement job handed to a worker. Checkpoint and StartedAt are set when it resumes. Timeout
+// is the max runtime that applies to the job, zero for none.
 type SettlementJob struct {
-	ID        uuid.UUID
-	From      time.Time
-	To        time.Time
-	RunID     string
-	BatchSize int
-	Cancelled chan bool
+	ID         uuid.UUID
+	Priority   int
+	From       time.Time
+	To         time.Time
+	RunID      string
+	BatchSize  int
+	Timeout    time.Duration
+	Requeues   int
+	Checkpoint *SettlementCheckpoint
+	StartedAt  *time.Time
+}
+
+// SettlementCheckpoint is how far a paused settlement job got: the last transaction it read, nil before the
+// first batch, and the settlements aggregated up to and including it. Keying on the transaction rather than a
+// row offset keeps the point valid when transactions in the range are refunded or paid while the job is paused.
+type SettlementCheckpoint struct {
+	After       *TransactionCursor
+	Processed   int64
+	Settlements []SettlementEntity
+}
+
+// JobQueueDepthEntity counts the QUEUED or RUNNING jobs of one type and priority, and the rows they have left.
+type JobQueueDepthEntity struct {
+	Type          string
+	Priority      int
+	Status        string
+	Jobs          int64
+	RemainingRows int64
+}
+
+// JobQueueStatsEntity is the queue as the workers see it: what waits at each priority, how busy each job type
+// is against its concurrency limit, and what the watchdog of this replica has done.
+type JobQueueStatsEntity struct {
+	Priorities []JobPriorityDepthEntity
+	Types      []JobTypeDepthEntity
+	Watchdog   JobWatchdogStatsEntity
+}
+
+type JobPriorityDepthEntity struct {
+	Priority   int
+	Queued     int64
+	QueuedRows int64
+}
+
+// JobWatchdogStatsEntity counts the jobs timed out since the replica started: TimedOut ran past their deadline
+// and Stalled went without progress, and each was either Requeued or Failed.
+type JobWatchdogStatsEntity struct {
+	TimedOut int64
+	Stalled  int64
+	Requeued int64
+	Failed   int64
+}
+
+// JobTypeDepthEntity has a Limit of zero when the type may use every worker.
+type JobTypeDepthEntity struct {
+	Type    string
+	Queued  int64
+	Running int64
+	Limit   int
 }
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Relative date ranges a schedule can create settlement jobs for, resolved on the day each run is scheduled.
+const (
+	ScheduleRangeYesterday     = "yesterday"
+	ScheduleRangeLast7Days     = "last_7_days"
+	ScheduleRangeLastFullWeek  = "last_full_week"
+	ScheduleRangeLastFullMonth = "last_full_month"
+)
+
+var ScheduleDateRanges = []string{
+	ScheduleRangeYesterday,
+	ScheduleRangeLast7Days,
+	ScheduleRangeLastFullWeek,
+	ScheduleRangeLastFullMonth,
+}
+
+// Statuses of a schedule run. A run is PENDING between being claimed and its job being created; one still PENDING
+// once the misfire grace has passed, as when its instance stopped in between, becomes MISSED and stays so.
+const (
+	ScheduleRunPending = "PENDING"
+	ScheduleRunFired   = "FIRED"
+	ScheduleRunFailed  = "FAILED"
+	ScheduleRunMissed  = "MISSED"
+)
+
+// JobScheduleEntity creates a JobType job each time CronExpression matches in Timezone. NextRunAt is the next
+// occurrence not yet handled.
+type JobScheduleEntity struct {
+	ID             uuid.UUID
+	Name           string
+	CronExpression string
+	Timezone       string
+	JobType        string
+	DateRange      string
+	Active         bool
+	NextRunAt      time.Time
+	LastRunAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// JobScheduleRunEntity records what happened to one occurrence of a schedule.
+type JobScheduleRunEntity struct {
+	ID           uuid.UUID
+	ScheduleID   uuid.UUID
+	ScheduledFor time.Time
+	Status       string
+	JobID        *uuid.UUID
+	Error        *string
+	CreatedAt    time.Time
+}
+
+// SettlementRange resolves dateRange against the calendar day of scheduledFor, in scheduledFor's location. It
+// returns the from and to dates of a settlement job, where to is the day after the last day covered: settlement
+// jobs take transactions paid from midnight on from up to midnight on to.
+func SettlementRange(dateRange string, scheduledFor time.Time) (string, string, error) {
+
+	today := time.Date(scheduledFor.Year(), scheduledFor.Month(), scheduledFor.Day(), 0, 0, 0, 0, time.UTC)
+
+	var from, to time.Time
+	switch dateRange {
+	case ScheduleRangeYesterday:
+		from, to = today.AddDate(0, 0, -1), today
+	case ScheduleRangeLast7Days:
+		from, to = today.AddDate(0, 0, -7), today
+	case ScheduleRangeLastFullWeek:
+		// Weeks run Monday to Sunday.
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		from, to = monday.AddDate(0, 0, -7), monday
+	case ScheduleRangeLastFullMonth:
+		first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+		from, to = first.AddDate(0, -1, 0), first
+	default:
+		return "", "", fmt.Errorf("unknown date range %q", dateRange)
+	}
+
+	return from.Format("2006-01-02"), to.Format("2006-01-02"), nil
+}
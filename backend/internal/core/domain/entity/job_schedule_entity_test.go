@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSettlementRange(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+
+	day := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 1, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name         string
+		dateRange    string
+		scheduledFor time.Time
+		from, to     string
+	}{
+		{"yesterday", ScheduleRangeYesterday, day(2025, 3, 15), "2025-03-14", "2025-03-15"},
+		{"yesterday across a month", ScheduleRangeYesterday, day(2025, 3, 1), "2025-02-28", "2025-03-01"},
+		{"yesterday across a year", ScheduleRangeYesterday, day(2025, 1, 1), "2024-12-31", "2025-01-01"},
+		{"last 7 days", ScheduleRangeLast7Days, day(2025, 3, 3), "2025-02-24", "2025-03-03"},
+
+		// 2025-03-03 is a Monday.
+		{"last full week on a Monday", ScheduleRangeLastFullWeek, day(2025, 3, 3), "2025-02-24", "2025-03-03"},
+		{"last full week on a Wednesday", ScheduleRangeLastFullWeek, day(2025, 3, 5), "2025-02-24", "2025-03-03"},
+		{"last full week on a Sunday", ScheduleRangeLastFullWeek, day(2025, 3, 9), "2025-02-24", "2025-03-03"},
+		{"last full week across a year", ScheduleRangeLastFullWeek, day(2025, 1, 1), "2024-12-23", "2024-12-30"},
+
+		{"last full month on the 1st", ScheduleRangeLastFullMonth, day(2025, 3, 1), "2025-02-01", "2025-03-01"},
+		{"last full month on the 31st", ScheduleRangeLastFullMonth, day(2025, 3, 31), "2025-02-01", "2025-03-01"},
+		{"last full month across a year", ScheduleRangeLastFullMonth, day(2025, 1, 15), "2024-12-01", "2025-01-01"},
+		{"last full month in a leap year", ScheduleRangeLastFullMonth, day(2024, 3, 1), "2024-02-01", "2024-03-01"},
+
+		// 00:30 on 1 March in Jakarta is still 28 February in UTC; the schedule's own calendar day counts.
+		{"day in the schedule's time zone", ScheduleRangeYesterday, time.Date(2025, 3, 1, 0, 30, 0, 0, jakarta), "2025-02-28", "2025-03-01"},
+		{"month in the schedule's time zone", ScheduleRangeLastFullMonth, time.Date(2025, 3, 1, 0, 30, 0, 0, jakarta), "2025-02-01", "2025-03-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := SettlementRange(tt.dateRange, tt.scheduledFor)
+			if err != nil {
+				t.Fatalf("SettlementRange: %v", err)
+			}
+			if from != tt.from || to != tt.to {
+				t.Errorf("SettlementRange(%q, %s) = %s to %s, want %s to %s", tt.dateRange, tt.scheduledFor, from, to, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestSettlementRangeRejectsUnknownRange(t *testing.T) {
+	if _, _, err := SettlementRange("last_quarter", time.Now()); err == nil {
+		t.Error("SettlementRange succeeded, want an error")
+	}
+}
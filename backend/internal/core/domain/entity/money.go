@@ -0,0 +1,92 @@
+package entity
+
+import (
+	errs "backend-service/internal/core/domain/error"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// currencyExponents lists the supported ISO 4217 currencies and how many minor-unit digits each has.
+var currencyExponents = map[string]int{
+	"IDR": 2,
+	"SGD": 2,
+	"MYR": 2,
+	"USD": 2,
+	"EUR": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+}
+
+// Money is an amount in the minor units of Currency, e.g. {Amount: 1050, Currency: "USD"} is 10.50 USD.
+// Amounts of different currencies never mix: Add and Sub return ErrCurrencyMismatch instead of guessing a rate.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: strings.ToUpper(currency)}
+}
+
+// IsSupportedCurrency reports whether code is a currency Money can represent.
+func IsSupportedCurrency(code string) bool {
+	_, ok := currencyExponents[strings.ToUpper(code)]
+	return ok
+}
+
+// Exponent returns the number of minor-unit digits of the currency.
+func (m Money) Exponent() int {
+	return currencyExponents[m.Currency]
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Multiply scales the amount, e.g. a unit price by a quantity.
+func (m Money) Multiply(n int64) Money {
+	return Money{Amount: m.Amount * n, Currency: m.Currency}
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Decimal formats the amount in major units with the currency's precision, e.g. "10.50".
+func (m Money) Decimal() string {
+	exponent := m.Exponent()
+	if exponent == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	digits := fmt.Sprintf("%0*d", exponent+1, amount)
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
+func (m Money) String() string {
+	return m.Currency + " " + m.Decimal()
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("%w: %s and %s", errs.ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
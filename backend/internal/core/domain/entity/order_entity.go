@@ -6,14 +6,82 @@ import (
 	"github.com/google/uuid"
 )
 
+// OrderEntity is a buyer's order, priced in a single currency. Gross is the sum of item totals. Total is what
+// the buyer pays: Gross less Discount, plus Fee, plus Tax unless TaxInclusive.
 type OrderEntity struct {
+	ID              uuid.UUID
+	BuyerID         string
+	Currency        string
+	Gross           Money
+	Discount        Money
+	Tax             Money
+	TaxInclusive    bool
+	Fee             Money
+	Total           Money
+	PromotionID     *uint
+	PaymentIntentID *string
+	Status          string
+	CancelReason    *string
+	CancelledAt     *time.Time
+	ExpiresAt       *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Items           []OrderItemEntity
+	QueueToken      *uuid.UUID
+	PromoCode       string
+}
+
+type OrderItemEntity struct {
 	ID         uuid.UUID
+	OrderID    uuid.UUID
 	ProductID  uint
-	BuyerID    string
 	Quantity   int
-	TotalCents int
-	Status     string
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	UnitPrice  Money
+	Total      Money
+	Discount   Money
+	TaxRateBps int
+	Tax        Money
 	Product    *ProductEntity
 }
+
+// OrderCursor marks the last order of a page; orders are listed newest first by (CreatedAt, ID).
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+type OrderFilterEntity struct {
+	BuyerID     string
+	ProductID   uint
+	Status      string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	After       *OrderCursor
+	Limit       int
+}
+
+type OrderPageEntity struct {
+	Orders     []OrderEntity
+	NextCursor *OrderCursor
+}
+
+// orderTransitions lists the statuses an order may move to from each status.
+var orderTransitions = map[string][]string{
+	"PENDING":   {"COMPLETED", "CANCELLED", "EXPIRED"},
+	"COMPLETED": {"CANCELLED"},
+	"CANCELLED": {},
+	"EXPIRED":   {},
+}
+
+func (o OrderEntity) IsExpired(now time.Time) bool {
+	return o.ExpiresAt != nil && !now.Before(*o.ExpiresAt)
+}
+
+func (o OrderEntity) CanTransitionTo(status string) bool {
+	for _, next := range orderTransitions[o.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types written to the outbox. Consumers may see an event more than once and should deduplicate on its ID.
+const (
+	EventOrderCreated   = "order.created"
+	EventOrderCompleted = "order.completed"
+	EventOrderCancelled = "order.cancelled"
+	EventOrderExpired   = "order.expired"
+	EventStockChanged   = "stock.changed"
+	EventJobCompleted   = "job.completed"
+	EventJobFailed      = "job.failed"
+	EventJobCancelled   = "job.cancelled"
+)
+
+// OutboxEventEntity is a domain event recorded in the same transaction as the change it describes.
+// Payload is the event body as JSON.
+type OutboxEventEntity struct {
+	ID            uuid.UUID
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Payload       string
+	Attempts      int
+	LastError     *string
+	NextAttemptAt time.Time
+	PublishedAt   *time.Time
+	CreatedAt     time.Time
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentIntentEntity is a payment the buyer completes at the gateway for the whole order total.
+type PaymentIntentEntity struct {
+	ID        string
+	OrderID   uuid.UUID
+	Amount    Money
+	Status    string
+	CreatedAt time.Time
+}
+
+// PaymentResultEntity is the gateway's final word on a payment intent: PAID or FAILED.
+type PaymentResultEntity struct {
+	PaymentIntentID string
+	Status          string
+	FailureReason   *string
+	OccurredAt      time.Time
+}
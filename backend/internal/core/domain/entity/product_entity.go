@@ -3,10 +3,26 @@ package entity
 import "time"
 
 type ProductEntity struct {
-	ID         uint
-	Name       string
-	PriceCents int
-	Stock      int
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID           uint
+	Name         string
+	MerchantID   string
+	Category     string
+	Price        Money
+	Stock        int
+	MaxPerBuyer  *int
+	SaleStartsAt *time.Time
+	SaleEndsAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// SaleState reports whether the product can be ordered at now. A product without a window is always LIVE.
+func (p ProductEntity) SaleState(now time.Time) string {
+	if p.SaleStartsAt != nil && now.Before(*p.SaleStartsAt) {
+		return "UPCOMING"
+	}
+	if p.SaleEndsAt != nil && !now.Before(*p.SaleEndsAt) {
+		return "ENDED"
+	}
+	return "LIVE"
 }
@@ -0,0 +1,94 @@
+package entity
+
+import (
+	errs "backend-service/internal/core/domain/error"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PromotionEntity struct {
+	ID   uint
+	Code string
+	Type string
+	// Value is a percentage for PERCENTAGE promotions and an amount in minor units of Currency for FIXED ones.
+	Value           int
+	Currency        *string
+	ProductID       *uint
+	MaxUses         *int
+	MaxUsesPerBuyer *int
+	UsedCount       int
+	StartsAt        *time.Time
+	EndsAt          *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type PromotionRedemptionEntity struct {
+	ID          uuid.UUID
+	PromotionID uint
+	OrderID     uuid.UUID
+	BuyerID     string
+	Discount    Money
+	CreatedAt   time.Time
+}
+
+// IsActive reports whether now falls inside the promotion's validity window.
+func (p PromotionEntity) IsActive(now time.Time) bool {
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Allocate returns the discount for each of items, all priced in currency. A product-scoped promotion only
+// discounts that product's lines, and the discount never exceeds the amount it applies to. The discount is
+// spread over the eligible lines in proportion to their totals, with the rounding remainder on the last one,
+// so that tax can be computed per line on the discounted amount. A FIXED promotion in another currency
+// returns ErrCurrencyMismatch.
+func (p PromotionEntity) Allocate(items []OrderItemEntity, currency string) ([]Money, error) {
+	eligible, last := int64(0), -1
+	for i, item := range items {
+		if p.applies(item) {
+			eligible += item.Total.Amount
+			last = i
+		}
+	}
+
+	discount := int64(p.Value)
+	if p.Type == "PERCENTAGE" {
+		discount = eligible * int64(p.Value) / 100
+	} else if p.Currency == nil || *p.Currency != currency {
+		promotionCurrency := ""
+		if p.Currency != nil {
+			promotionCurrency = *p.Currency
+		}
+		return nil, fmt.Errorf("%w: promotion is in %s, order is in %s", errs.ErrCurrencyMismatch, promotionCurrency, currency)
+	}
+	discount = min(discount, eligible)
+
+	allocation := make([]Money, len(items))
+	allocated := int64(0)
+	for i, item := range items {
+		allocation[i] = NewMoney(0, currency)
+		if !p.applies(item) || discount == 0 {
+			continue
+		}
+		if i == last {
+			allocation[i].Amount = discount - allocated
+			break
+		}
+		allocation[i].Amount = discount * item.Total.Amount / eligible
+		allocated += allocation[i].Amount
+	}
+
+	return allocation, nil
+}
+
+func (p PromotionEntity) applies(item OrderItemEntity) bool {
+	return p.ProductID == nil || *p.ProductID == item.ProductID
+}
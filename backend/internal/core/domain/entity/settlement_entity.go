@@ -10,10 +10,35 @@ type SettlementEntity struct {
 	ID          uuid.UUID
 	MerchantID  string
 	Date        time.Time
-	GrossCents  int64
-	FeeCents    int64
-	NetCents    int64
+	Currency    string
+	Gross       Money
+	Fee         Money
+	Net         Money
 	TxnCount    int
 	GeneratedAt time.Time
 	UniqueRunID string
 }
+
+// AddTransaction adds txn to the settlement totals. A transaction in another currency returns ErrCurrencyMismatch.
+func (s *SettlementEntity) AddTransaction(txn TransactionEntity) error {
+	net, err := txn.Amount.Sub(txn.Fee)
+	if err != nil {
+		return err
+	}
+
+	gross, err := s.Gross.Add(txn.Amount)
+	if err != nil {
+		return err
+	}
+	fee, err := s.Fee.Add(txn.Fee)
+	if err != nil {
+		return err
+	}
+	if s.Net, err = s.Net.Add(net); err != nil {
+		return err
+	}
+
+	s.Gross, s.Fee = gross, fee
+	s.TxnCount++
+	return nil
+}
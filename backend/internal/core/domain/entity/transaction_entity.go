@@ -6,11 +6,24 @@ import (
 	"github.com/google/uuid"
 )
 
+// TransactionEntity is one merchant's share of a payment. Amount is what the buyer paid for the merchant's goods
+// and Fee is the commission kept from it. PaidAt is set once the gateway reports the payment as PAID.
 type TransactionEntity struct {
-	ID          uuid.UUID
-	MerchantID  string
-	AmountCents int
-	FeeCents    int
-	Status      string
-	PaidAt      time.Time
+	ID              uuid.UUID
+	MerchantID      string
+	OrderID         *uuid.UUID
+	PaymentIntentID *string
+	Amount          Money
+	Fee             Money
+	Status          string
+	FailureReason   *string
+	PaidAt          *time.Time
+	CreatedAt       time.Time
+}
+
+// TransactionCursor marks the last transaction a settlement job has read; jobs read PAID transactions in
+// (PaidAt, ID) order.
+type TransactionCursor struct {
+	PaidAt time.Time
+	ID     uuid.UUID
 }
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookSubscriptionEntity registers URL to receive the listed event types. "*" subscribes to every type.
+type WebhookSubscriptionEntity struct {
+	ID         uuid.UUID
+	URL        string
+	Secret     string
+	EventTypes []string
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// WebhookDeliveryEntity is one event on its way to one subscription. Payload is the JSON body that is POSTed.
+type WebhookDeliveryEntity struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        uuid.UUID
+	EventType      string
+	Payload        string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      *string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	Subscription   *WebhookSubscriptionEntity
+	AttemptLog     []WebhookDeliveryAttemptEntity
+}
+
+type WebhookDeliveryAttemptEntity struct {
+	ID           uuid.UUID
+	DeliveryID   uuid.UUID
+	StatusCode   *int
+	Error        *string
+	ResponseBody *string
+	Duration     time.Duration
+	CreatedAt    time.Time
+}
+
+// WebhookEventTypes lists the event types a subscription may ask for.
+var WebhookEventTypes = []string{
+	EventOrderCreated,
+	EventOrderCompleted,
+	EventOrderCancelled,
+	EventOrderExpired,
+	EventStockChanged,
+	EventJobCompleted,
+	EventJobFailed,
+	EventJobCancelled,
+}
+
+func (w WebhookSubscriptionEntity) Matches(eventType string) bool {
+	for _, subscribed := range w.EventTypes {
+		if subscribed == "*" || subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
@@ -6,11 +6,63 @@ var (
 	ErrProductNotFound = errors.New("product not found")
 	ErrOutOfStock      = errors.New("out of stock")
 
-	ErrOrderNotFound = errors.New("order not found")
+	ErrPurchaseLimitExceeded = errors.New("purchase limit per buyer exceeded")
+	ErrSaleNotStarted        = errors.New("sale has not started yet")
+	ErrSaleEnded             = errors.New("sale has ended")
+	ErrInvalidSaleWindow     = errors.New("sale window must start before it ends")
+
+	ErrInvalidStockAdjustment = errors.New("invalid stock adjustment")
+
+	ErrCurrencyMismatch    = errors.New("amounts are in different currencies")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrOrderAlreadyCancelled  = errors.New("order already cancelled")
+	ErrOrderCannotBeCancelled = errors.New("order cannot be cancelled")
+	ErrOrderStatusConflict    = errors.New("order status changed concurrently")
+	ErrOrderCannotBeConfirmed = errors.New("order cannot be confirmed")
+	ErrOrderExpired           = errors.New("order reservation has expired")
+
+	ErrPaymentIntentNotFound       = errors.New("payment intent not found")
+	ErrPaymentIntentAlreadySettled = errors.New("payment intent already settled")
+	ErrPaymentSimulatorDisabled    = errors.New("payment gateway does not support simulated results")
+
+	ErrPromotionNotFound          = errors.New("promotion code not found")
+	ErrPromotionCodeTaken         = errors.New("promotion code already exists")
+	ErrInvalidPromotion           = errors.New("invalid promotion")
+	ErrPromotionNotActive         = errors.New("promotion code is not active")
+	ErrPromotionNotApplicable     = errors.New("promotion code does not apply to this order")
+	ErrPromotionExhausted         = errors.New("promotion code has no uses left")
+	ErrPromotionBuyerLimitReached = errors.New("promotion code already used the maximum number of times by this buyer")
+
+	ErrCartEmpty        = errors.New("cart is empty")
+	ErrCartItemNotFound = errors.New("cart item not found")
 
 	ErrInvalidDateRange = errors.New("invalid date range")
 
-	ErrJobNotFound = errors.New("job not found")
+	ErrJobNotFound   = errors.New("job not found")
+	ErrJobNotRunning = errors.New("job is no longer running")
+
+	ErrJobCannotBeCancelled  = errors.New("job cannot be cancelled")
+	ErrJobCannotBePaused     = errors.New("only queued or running jobs of this instance can be paused")
+	ErrJobCannotBeResumed    = errors.New("only paused jobs can be resumed")
+	ErrInvalidJobPriority    = errors.New("job priority must be between 0 and 9")
+	ErrInvalidJobTimeout     = errors.New("job timeout must be a whole number of seconds, at least one")
+	ErrSettlementJobOverlaps = errors.New("settlement job overlaps an active job")
+
+	ErrJobScheduleNotFound = errors.New("job schedule not found")
+	ErrInvalidJobSchedule  = errors.New("invalid job schedule")
+
+	ErrQueueDisabled         = errors.New("waiting room is not enabled")
+	ErrQueueTokenRequired    = errors.New("queue token is required")
+	ErrQueueTokenNotFound    = errors.New("queue token not found")
+	ErrQueueTokenNotAdmitted = errors.New("queue token has not been admitted yet")
+	ErrQueueTokenExpired     = errors.New("queue token has expired or was already used")
+
+	ErrWebhookNotFound         = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhook          = errors.New("invalid webhook subscription")
 
-	ErrJobCannotBeCancelled = errors.New("job cannot be cancelled")
+	ErrIdempotencyKeyReused     = errors.New("idempotency key already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
 )
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AdmissionTokenModel struct {
+	Token      uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Seq        int64     `gorm:"autoIncrement;->"`
+	BuyerID    string    `gorm:"not null"`
+	Status     string    `gorm:"not null;default:WAITING"`
+	AdmittedAt *time.Time
+	ExpiresAt  *time.Time
+	UsedAt     *time.Time
+	CreatedAt  time.Time
+}
+
+func (AdmissionTokenModel) TableName() string {
+	return "admission_tokens"
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type CartItemModel struct {
+	BuyerID   string       `gorm:"primaryKey"`
+	ProductID uint         `gorm:"primaryKey"`
+	Quantity  int          `gorm:"not null"`
+	CreatedAt time.Time    `gorm:"autoCreateTime"`
+	UpdatedAt time.Time    `gorm:"autoUpdateTime"`
+	Product   ProductModel `gorm:"foreignKey:ProductID"`
+}
+
+func (CartItemModel) TableName() string {
+	return "cart_items"
+}
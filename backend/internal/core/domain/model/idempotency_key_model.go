@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type IdempotencyKeyModel struct {
+	Key          string `gorm:"primaryKey"`
+	Scope        string `gorm:"primaryKey"`
+	RequestHash  string `gorm:"not null"`
+	Status       string `gorm:"not null;default:IN_PROGRESS"`
+	ResponseCode *int
+	ResponseBody []byte
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (IdempotencyKeyModel) TableName() string {
+	return "idempotency_keys"
+}
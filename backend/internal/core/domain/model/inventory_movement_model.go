@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InventoryMovementModel struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ProductID  uint       `gorm:"not null;index"`
+	OrderID    *uuid.UUID `gorm:"type:uuid;index"`
+	Type       string     `gorm:"not null"`
+	Quantity   int        `gorm:"not null"`
+	StockAfter int        `gorm:"not null"`
+	Reason     *string
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (InventoryMovementModel) TableName() string {
+	return "inventory_movements"
+}
@@ -6,10 +6,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// Priority carries no gorm default so that priority 0 is inserted as such.
 type JobModel struct {
 	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	Type         string    `gorm:"not null;index"`
 	Status       string    `gorm:"not null;default:QUEUED;index"`
+	Priority     int       `gorm:"not null"`
 	Progress     int       `gorm:"default:0"`
 	Processed    int64     `gorm:"default:0"`
 	Total        int64     `gorm:"default:0"`
@@ -17,13 +19,31 @@ type JobModel struct {
 	ResultPath   *string
 	ErrorMessage *string
 	UniqueRunID  *string
-	Cancelled    bool `gorm:"default:false"`
-	StartedAt    *time.Time
-	CompletedAt  *time.Time
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	Cancelled    bool    `gorm:"default:false"`
+	Throughput   float64 `gorm:"default:0"`
+	Checkpoint   *string `gorm:"type:jsonb"`
+	// TimeoutSeconds is nil for jobs that take the max runtime of their type.
+	TimeoutSeconds *int
+	Requeues       int `gorm:"not null;default:0"`
+	DeadlineAt     *time.Time
+	HeartbeatAt    *time.Time
+	StartedAt      *time.Time
+	CompletedAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 func (JobModel) TableName() string {
 	return "jobs"
 }
+
+type JobThroughputStatModel struct {
+	JobType       string `gorm:"primaryKey"`
+	RowsPerSecond float64
+	Samples       int
+	UpdatedAt     time.Time
+}
+
+func (JobThroughputStatModel) TableName() string {
+	return "job_throughput_stats"
+}
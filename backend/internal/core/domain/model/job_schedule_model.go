@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Active carries no gorm default so that a schedule created paused is inserted as such.
+type JobScheduleModel struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name           string    `gorm:"not null"`
+	CronExpression string    `gorm:"not null"`
+	Timezone       string    `gorm:"not null;default:UTC"`
+	JobType        string    `gorm:"not null"`
+	DateRange      string    `gorm:"not null"`
+	Active         bool      `gorm:"not null"`
+	NextRunAt      time.Time `gorm:"not null"`
+	LastRunAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (JobScheduleModel) TableName() string {
+	return "job_schedules"
+}
+
+type JobScheduleRunModel struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	ScheduleID   uuid.UUID `gorm:"type:uuid;not null"`
+	ScheduledFor time.Time `gorm:"not null"`
+	Status       string    `gorm:"not null"`
+	JobID        *uuid.UUID
+	Error        *string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (JobScheduleRunModel) TableName() string {
+	return "job_schedule_runs"
+}
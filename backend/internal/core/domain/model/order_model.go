@@ -7,17 +7,44 @@ import (
 )
 
 type OrderModel struct {
-	ID         uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	ProductID  uint         `gorm:"not null"`
-	BuyerID    string       `gorm:"not null;index"`
-	Quantity   int          `gorm:"not null"`
-	TotalCents int          `gorm:"not null"`
-	Status     string       `gorm:"default:PENDING"`
-	CreatedAt  time.Time    `gorm:"autoCreateTime"`
-	UpdatedAt  time.Time    `gorm:"autoUpdateTime"`
-	Product    ProductModel `gorm:"foreignKey:ProductID"`
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	BuyerID         string    `gorm:"not null;index"`
+	Currency        string    `gorm:"not null;default:IDR"`
+	GrossCents      int       `gorm:"not null"`
+	DiscountCents   int       `gorm:"not null;default:0"`
+	TaxCents        int       `gorm:"not null;default:0"`
+	TaxInclusive    bool      `gorm:"not null;default:false"`
+	FeeCents        int       `gorm:"not null;default:0"`
+	TotalCents      int       `gorm:"not null"`
+	PromotionID     *uint
+	PaymentIntentID *string
+	Status          string `gorm:"default:PENDING"`
+	CancelReason    *string
+	CancelledAt     *time.Time
+	ExpiresAt       *time.Time
+	CreatedAt       time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time        `gorm:"autoUpdateTime"`
+	Items           []OrderItemModel `gorm:"foreignKey:OrderID"`
 }
 
 func (OrderModel) TableName() string {
 	return "orders"
 }
+
+type OrderItemModel struct {
+	ID             uuid.UUID    `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OrderID        uuid.UUID    `gorm:"type:uuid;not null"`
+	ProductID      uint         `gorm:"not null;index"`
+	Quantity       int          `gorm:"not null"`
+	UnitPriceCents int          `gorm:"not null"`
+	TotalCents     int          `gorm:"not null"`
+	DiscountCents  int          `gorm:"not null;default:0"`
+	TaxRateBps     int          `gorm:"not null;default:0"`
+	TaxCents       int          `gorm:"not null;default:0"`
+	CreatedAt      time.Time    `gorm:"autoCreateTime"`
+	Product        ProductModel `gorm:"foreignKey:ProductID"`
+}
+
+func (OrderItemModel) TableName() string {
+	return "order_items"
+}
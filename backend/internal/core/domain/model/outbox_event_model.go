@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxEventModel struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	AggregateType string    `gorm:"not null"`
+	AggregateID   string    `gorm:"not null"`
+	EventType     string    `gorm:"not null"`
+	Payload       string    `gorm:"type:jsonb;not null"`
+	Attempts      int       `gorm:"not null;default:0"`
+	LastError     *string
+	NextAttemptAt time.Time `gorm:"not null"`
+	PublishedAt   *time.Time
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (OutboxEventModel) TableName() string {
+	return "outbox_events"
+}
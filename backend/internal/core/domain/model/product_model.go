@@ -3,12 +3,18 @@ package model
 import "time"
 
 type ProductModel struct {
-	ID         uint   `gorm:"primaryKey"`
-	Name       string `gorm:"not null"`
-	PriceCents int    `gorm:"not null;default:0"`
-	Stock      int    `gorm:"not null;default:0"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID           uint   `gorm:"primaryKey"`
+	Name         string `gorm:"not null"`
+	MerchantID   string `gorm:"not null;default:merchant_001"`
+	Category     string `gorm:"not null;default:GENERAL"`
+	PriceCents   int    `gorm:"not null;default:0"`
+	Currency     string `gorm:"not null;default:IDR"`
+	Stock        int    `gorm:"not null;default:0"`
+	MaxPerBuyer  *int
+	SaleStartsAt *time.Time
+	SaleEndsAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 func (ProductModel) TableName() string {
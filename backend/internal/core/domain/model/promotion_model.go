@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PromotionModel struct {
+	ID              uint   `gorm:"primaryKey"`
+	Code            string `gorm:"not null;uniqueIndex"`
+	Type            string `gorm:"not null"`
+	Value           int    `gorm:"not null"`
+	Currency        *string
+	ProductID       *uint
+	MaxUses         *int
+	MaxUsesPerBuyer *int
+	UsedCount       int `gorm:"not null;default:0"`
+	StartsAt        *time.Time
+	EndsAt          *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (PromotionModel) TableName() string {
+	return "promotions"
+}
+
+type PromotionRedemptionModel struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PromotionID   uint      `gorm:"not null"`
+	OrderID       uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	BuyerID       string    `gorm:"not null"`
+	DiscountCents int       `gorm:"not null"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+}
+
+func (PromotionRedemptionModel) TableName() string {
+	return "promotion_redemptions"
+}
@@ -8,8 +8,9 @@ import (
 
 type SettlementModel struct {
 	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	MerchantID  string    `gorm:"not null;index:idx_merchant_date"`
-	Date        time.Time `gorm:"type:date;not null;index:idx_merchant_date"`
+	MerchantID  string    `gorm:"not null;index:idx_merchant_date_currency"`
+	Date        time.Time `gorm:"type:date;not null;index:idx_merchant_date_currency"`
+	Currency    string    `gorm:"not null;default:IDR;index:idx_merchant_date_currency"`
 	GrossCents  int64     `gorm:"not null;default:0"`
 	FeeCents    int64     `gorm:"not null;default:0"`
 	NetCents    int64     `gorm:"not null;default:0"`
@@ -7,13 +7,17 @@ import (
 )
 
 type TransactionModel struct {
-	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	MerchantID  string    `gorm:"not null;index"`
-	AmountCents int       `gorm:"not null"`
-	FeeCents    int       `gorm:"not null"`
-	Status      string    `gorm:"default:PAID"`
-	PaidAt      time.Time `gorm:"index"`
-	CreatedAt   time.Time
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	MerchantID      string     `gorm:"not null;index"`
+	OrderID         *uuid.UUID `gorm:"type:uuid;index"`
+	PaymentIntentID *string    `gorm:"index"`
+	AmountCents     int        `gorm:"not null"`
+	FeeCents        int        `gorm:"not null"`
+	Currency        string     `gorm:"not null;default:IDR"`
+	Status          string     `gorm:"default:PAID"`
+	FailureReason   *string
+	PaidAt          *time.Time `gorm:"index"`
+	CreatedAt       time.Time
 }
 
 func (TransactionModel) TableName() string {
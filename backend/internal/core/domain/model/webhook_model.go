@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookSubscriptionModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	URL        string    `gorm:"not null"`
+	Secret     string    `gorm:"not null"`
+	EventTypes string    `gorm:"type:jsonb;not null"`
+	Active     bool      `gorm:"not null;default:true"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (WebhookSubscriptionModel) TableName() string {
+	return "webhook_subscriptions"
+}
+
+type WebhookDeliveryModel struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SubscriptionID uuid.UUID `gorm:"type:uuid;not null"`
+	EventID        uuid.UUID `gorm:"type:uuid;not null"`
+	EventType      string    `gorm:"not null"`
+	Payload        string    `gorm:"type:jsonb;not null"`
+	Status         string    `gorm:"not null;default:PENDING"`
+	Attempts       int       `gorm:"not null;default:0"`
+	NextAttemptAt  time.Time `gorm:"not null"`
+	LastStatusCode *int
+	LastError      *string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Subscription   WebhookSubscriptionModel      `gorm:"foreignKey:SubscriptionID"`
+	AttemptLog     []WebhookDeliveryAttemptModel `gorm:"foreignKey:DeliveryID"`
+}
+
+func (WebhookDeliveryModel) TableName() string {
+	return "webhook_deliveries"
+}
+
+type WebhookDeliveryAttemptModel struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	DeliveryID   uuid.UUID `gorm:"type:uuid;not null"`
+	StatusCode   *int
+	Error        *string
+	ResponseBody *string
+	DurationMs   int       `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+func (WebhookDeliveryAttemptModel) TableName() string {
+	return "webhook_delivery_attempts"
+}
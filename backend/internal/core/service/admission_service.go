@@ -0,0 +1,82 @@
+package service
+
+import (
+	"backend-service/config"
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type AdmissionServiceInterface interface {
+	Enqueue(ctx context.Context, buyerID string) (*entity.AdmissionTokenEntity, error)
+	GetStatus(ctx context.Context, token uuid.UUID) (*entity.AdmissionTokenEntity, error)
+	// Admit lets the next batch of waiting buyers through; it is meant to run once per second.
+	Admit(ctx context.Context) error
+	Consume(ctx context.Context, token uuid.UUID, buyerID string) error
+	Restore(ctx context.Context, token uuid.UUID) error
+}
+
+type AdmissionService struct {
+	admissionRepo repository.AdmissionRepositoryInterface
+	ratePerSecond int
+	tokenTTL      time.Duration
+}
+
+// Admit implements AdmissionServiceInterface.
+func (a *AdmissionService) Admit(ctx context.Context) error {
+
+	admitted, err := a.admissionRepo.AdmitNext(ctx, a.ratePerSecond, a.tokenTTL)
+	if err != nil {
+		log.Error().Err(err).Msg("[AdmissionService-1] Admit: failed to admit waiting buyers")
+		return err
+	}
+
+	if admitted > 0 {
+		log.Info().Int("admitted", admitted).Msg("Buyers admitted from waiting room")
+	}
+
+	return nil
+}
+
+// Consume implements AdmissionServiceInterface.
+func (a *AdmissionService) Consume(ctx context.Context, token uuid.UUID, buyerID string) error {
+	return a.admissionRepo.Consume(ctx, token, buyerID)
+}
+
+// Restore implements AdmissionServiceInterface.
+func (a *AdmissionService) Restore(ctx context.Context, token uuid.UUID) error {
+	return a.admissionRepo.Restore(ctx, token)
+}
+
+// Enqueue implements AdmissionServiceInterface.
+func (a *AdmissionService) Enqueue(ctx context.Context, buyerID string) (*entity.AdmissionTokenEntity, error) {
+	return a.admissionRepo.Enqueue(ctx, buyerID)
+}
+
+// GetStatus implements AdmissionServiceInterface.
+func (a *AdmissionService) GetStatus(ctx context.Context, token uuid.UUID) (*entity.AdmissionTokenEntity, error) {
+	return a.admissionRepo.Get(ctx, token)
+}
+
+func NewAdmissionService(cfg *config.Config, admissionRepo repository.AdmissionRepositoryInterface) AdmissionServiceInterface {
+
+	ratePerSecond := 50
+	if cfg.Admission.RatePerSecond > 0 {
+		ratePerSecond = cfg.Admission.RatePerSecond
+	}
+
+	tokenTTL := 5 * time.Minute
+	if cfg.Admission.TokenTTL > 0 {
+		tokenTTL = cfg.Admission.TokenTTL
+	}
+
+	return &AdmissionService{
+		admissionRepo: admissionRepo,
+		ratePerSecond: ratePerSecond,
+		tokenTTL:      tokenTTL,
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"backend-service/internal/adapter/gateway"
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type CartServiceInterface interface {
+	GetCart(ctx context.Context, buyerID string) ([]entity.CartItemEntity, error)
+	SetItem(ctx context.Context, item entity.CartItemEntity) error
+	RemoveItem(ctx context.Context, buyerID string, productID uint) error
+	// Checkout orders the contents of the buyer's cart; request carries the buyer and checkout options, its items are ignored.
+	Checkout(ctx context.Context, request entity.OrderEntity) (*entity.OrderEntity, error)
+}
+
+type CartService struct {
+	cartRepo       repository.CartRepositoryInterface
+	productRepo    repository.ProductRepositoryInterface
+	orderService   OrderServiceInterface
+	txManager      repository.TxManagerInterface
+	paymentGateway gateway.PaymentGatewayInterface
+}
+
+// Checkout implements CartServiceInterface.
+// The order is created and the cart emptied in one transaction, so a failed checkout leaves the cart intact. The
+// cart is emptied first: CreateOrder cancels the payment intent of an order it fails, but nothing after it would.
+func (c *CartService) Checkout(ctx context.Context, request entity.OrderEntity) (*entity.OrderEntity, error) {
+
+	var (
+		order   *entity.OrderEntity
+		buyerID = request.BuyerID
+	)
+
+	err := c.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		cartItems, err := c.cartRepo.GetItemsForUpdate(ctx, buyerID)
+		if err != nil {
+			return err
+		}
+
+		if len(cartItems) == 0 {
+			return errs.ErrCartEmpty
+		}
+
+		request.Items = make([]entity.OrderItemEntity, len(cartItems))
+		for i, item := range cartItems {
+			request.Items[i] = entity.OrderItemEntity{
+				ProductID: item.ProductID,
+				Quantity:  item.Quantity,
+			}
+		}
+
+		if err := c.cartRepo.Clear(ctx, buyerID); err != nil {
+			return err
+		}
+
+		order, err = c.orderService.CreateOrder(ctx, request)
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Str("buyer_id", buyerID).Msg("[CartService-1] Checkout: failed to check out cart")
+		// Only the commit can fail once the order is created; its intent goes with the rolled-back order.
+		if order != nil {
+			c.cancelPaymentIntent(ctx, *order)
+		}
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// cancelPaymentIntent stops the gateway from collecting payment for an order that was rolled back.
+func (c *CartService) cancelPaymentIntent(ctx context.Context, order entity.OrderEntity) {
+	if order.PaymentIntentID == nil {
+		return
+	}
+
+	err := c.paymentGateway.CancelIntent(context.WithoutCancel(ctx), *order.PaymentIntentID)
+	if err != nil && !errors.Is(err, errs.ErrPaymentIntentNotFound) && !errors.Is(err, errs.ErrPaymentIntentAlreadySettled) {
+		log.Error().Err(err).Str("order_id", order.ID.String()).Msg("[CartService-3] Checkout: failed to cancel payment intent")
+	}
+}
+
+// RemoveItem implements CartServiceInterface.
+func (c *CartService) RemoveItem(ctx context.Context, buyerID string, productID uint) error {
+	return c.cartRepo.DeleteItem(ctx, buyerID, productID)
+}
+
+// SetItem implements CartServiceInterface.
+func (c *CartService) SetItem(ctx context.Context, item entity.CartItemEntity) error {
+
+	if _, err := c.productRepo.GetByID(ctx, item.ProductID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errs.ErrProductNotFound
+		}
+		return err
+	}
+
+	if err := c.cartRepo.UpsertItem(ctx, item); err != nil {
+		log.Error().Err(err).Str("buyer_id", item.BuyerID).Msg("[CartService-2] SetItem: failed to set cart item")
+		return err
+	}
+
+	return nil
+}
+
+// GetCart implements CartServiceInterface.
+func (c *CartService) GetCart(ctx context.Context, buyerID string) ([]entity.CartItemEntity, error) {
+	return c.cartRepo.GetItems(ctx, buyerID)
+}
+
+func NewCartService(cartRepo repository.CartRepositoryInterface, productRepo repository.ProductRepositoryInterface, orderService OrderServiceInterface, txManager repository.TxManagerInterface, paymentGateway gateway.PaymentGatewayInterface) CartServiceInterface {
+	return &CartService{
+		cartRepo:       cartRepo,
+		productRepo:    productRepo,
+		orderService:   orderService,
+		txManager:      txManager,
+		paymentGateway: paymentGateway,
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"backend-service/internal/adapter/gateway"
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// failingCommitTx runs the unit of work and then fails as a commit would.
+type failingCommitTx struct{ err error }
+
+func (f failingCommitTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return f.err
+}
+
+type checkoutCartRepo struct {
+	repository.CartRepositoryInterface
+	clearErr error
+}
+
+func (r checkoutCartRepo) GetItemsForUpdate(context.Context, string) ([]entity.CartItemEntity, error) {
+	return []entity.CartItemEntity{{BuyerID: "buyer_001", ProductID: 1, Quantity: 2}}, nil
+}
+
+func (r checkoutCartRepo) Clear(context.Context, string) error {
+	return r.clearErr
+}
+
+// intentOrderService creates orders paid through intent_001.
+type intentOrderService struct {
+	OrderServiceInterface
+	created int
+}
+
+func (o *intentOrderService) CreateOrder(_ context.Context, request entity.OrderEntity) (*entity.OrderEntity, error) {
+	o.created++
+	intentID := "intent_001"
+	return &entity.OrderEntity{ID: uuid.New(), BuyerID: request.BuyerID, PaymentIntentID: &intentID}, nil
+}
+
+type cancelRecordingGateway struct {
+	gateway.PaymentGatewayInterface
+	cancelled []string
+}
+
+func (g *cancelRecordingGateway) CancelIntent(_ context.Context, paymentIntentID string) error {
+	g.cancelled = append(g.cancelled, paymentIntentID)
+	return nil
+}
+
+func TestCheckoutCancelsIntentWhenCommitFails(t *testing.T) {
+	commitErr := errors.New("commit failed")
+	orders := &intentOrderService{}
+	payments := &cancelRecordingGateway{}
+	c := &CartService{cartRepo: checkoutCartRepo{}, orderService: orders, txManager: failingCommitTx{err: commitErr}, paymentGateway: payments}
+
+	order, err := c.Checkout(context.Background(), entity.OrderEntity{BuyerID: "buyer_001"})
+	if !errors.Is(err, commitErr) {
+		t.Fatalf("Checkout error = %v, want %v", err, commitErr)
+	}
+	if order != nil {
+		t.Errorf("Checkout returned order %s for a rolled-back checkout", order.ID)
+	}
+	if !slices.Equal(payments.cancelled, []string{"intent_001"}) {
+		t.Errorf("cancelled intents = %v, want [intent_001]", payments.cancelled)
+	}
+}
+
+func TestCheckoutClearsCartBeforeCreatingOrder(t *testing.T) {
+	clearErr := errors.New("clear failed")
+	orders := &intentOrderService{}
+	payments := &cancelRecordingGateway{}
+	c := &CartService{cartRepo: checkoutCartRepo{clearErr: clearErr}, orderService: orders, txManager: inlineTx{}, paymentGateway: payments}
+
+	if _, err := c.Checkout(context.Background(), entity.OrderEntity{BuyerID: "buyer_001"}); !errors.Is(err, clearErr) {
+		t.Fatalf("Checkout error = %v, want %v", err, clearErr)
+	}
+	if orders.created != 0 {
+		t.Errorf("created %d orders after the cart failed to clear, want 0", orders.created)
+	}
+	if len(payments.cancelled) != 0 {
+		t.Errorf("cancelled intents = %v, want none", payments.cancelled)
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type orderEventItem struct {
+	ProductID uint `json:"product_id"`
+	Quantity  int  `json:"quantity"`
+}
+
+type orderEventPayload struct {
+	OrderID         uuid.UUID        `json:"order_id"`
+	BuyerID         string           `json:"buyer_id"`
+	Status          string           `json:"status"`
+	Currency        string           `json:"currency"`
+	Total           int64            `json:"total"`
+	PaymentIntentID *string          `json:"payment_intent_id,omitempty"`
+	CancelReason    *string          `json:"cancel_reason,omitempty"`
+	Items           []orderEventItem `json:"items"`
+}
+
+type stockEventPayload struct {
+	ProductID    uint       `json:"product_id"`
+	MovementType string     `json:"movement_type"`
+	Quantity     int        `json:"quantity"`
+	StockAfter   int        `json:"stock_after"`
+	OrderID      *uuid.UUID `json:"order_id,omitempty"`
+	Reason       *string    `json:"reason,omitempty"`
+}
+
+type jobEventPayload struct {
+	JobID        uuid.UUID  `json:"job_id"`
+	Type         string     `json:"type"`
+	Status       string     `json:"status"`
+	ResultPath   *string    `json:"result_path,omitempty"`
+	ErrorMessage *string    `json:"error_message,omitempty"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+// recordEvent adds an event to the outbox. Called with a transaction-bound ctx, the event is only published if
+// the transaction commits.
+func recordEvent(ctx context.Context, outboxRepo repository.OutboxRepositoryInterface, aggregateType string, aggregateID string, eventType string, payload interface{}) error {
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return outboxRepo.Add(ctx, entity.OutboxEventEntity{
+		ID:            uuid.New(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(body),
+		NextAttemptAt: time.Now(),
+	})
+}
+
+func recordOrderEvent(ctx context.Context, outboxRepo repository.OutboxRepositoryInterface, eventType string, order entity.OrderEntity) error {
+
+	items := make([]orderEventItem, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = orderEventItem{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
+
+	return recordEvent(ctx, outboxRepo, "order", order.ID.String(), eventType, orderEventPayload{
+		OrderID:         order.ID,
+		BuyerID:         order.BuyerID,
+		Status:          order.Status,
+		Currency:        order.Currency,
+		Total:           order.Total.Amount,
+		PaymentIntentID: order.PaymentIntentID,
+		CancelReason:    order.CancelReason,
+		Items:           items,
+	})
+}
+
+func recordStockEvent(ctx context.Context, outboxRepo repository.OutboxRepositoryInterface, movement entity.InventoryMovementEntity) error {
+	return recordEvent(ctx, outboxRepo, "product", strconv.FormatUint(uint64(movement.ProductID), 10), entity.EventStockChanged, stockEventPayload{
+		ProductID:    movement.ProductID,
+		MovementType: movement.Type,
+		Quantity:     movement.Quantity,
+		StockAfter:   movement.StockAfter,
+		OrderID:      movement.OrderID,
+		Reason:       movement.Reason,
+	})
+}
+
+func recordJobEvent(ctx context.Context, outboxRepo repository.OutboxRepositoryInterface, eventType string, job entity.JobEntity) error {
+	return recordEvent(ctx, outboxRepo, "job", job.ID.String(), eventType, jobEventPayload{
+		JobID:        job.ID,
+		Type:         job.Type,
+		Status:       job.Status,
+		ResultPath:   job.ResultPath,
+		ErrorMessage: job.ErrorMessage,
+		CompletedAt:  job.CompletedAt,
+	})
+}
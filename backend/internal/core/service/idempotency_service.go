@@ -0,0 +1,104 @@
+package service
+
+import (
+	"backend-service/config"
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type IdempotencyServiceInterface interface {
+	// Begin reserves key for a request. It returns nil when the caller should process the request,
+	// or the stored record when a completed response must be replayed.
+	Begin(ctx context.Context, key string, scope string, requestHash string) (*entity.IdempotencyKeyEntity, error)
+	Complete(ctx context.Context, key string, scope string, responseCode int, responseBody []byte) error
+	Release(ctx context.Context, key string, scope string) error
+	StartCleanup(ctx context.Context)
+}
+
+type IdempotencyService struct {
+	idempotencyKeyRepo repository.IdempotencyKeyRepositoryInterface
+	ttl                time.Duration
+}
+
+// Begin implements IdempotencyServiceInterface.
+func (i *IdempotencyService) Begin(ctx context.Context, key string, scope string, requestHash string) (*entity.IdempotencyKeyEntity, error) {
+
+	reserved, err := i.idempotencyKeyRepo.Reserve(ctx, entity.IdempotencyKeyEntity{
+		Key:         key,
+		Scope:       scope,
+		RequestHash: requestHash,
+		ExpiresAt:   time.Now().Add(i.ttl),
+	})
+	if err != nil {
+		log.Error().Err(err).Str("idempotency_key", key).Msg("[IdempotencyService-1] Begin: failed to reserve key")
+		return nil, err
+	}
+
+	if reserved {
+		return nil, nil
+	}
+
+	existing, err := i.idempotencyKeyRepo.Get(ctx, key, scope)
+	if err != nil {
+		log.Error().Err(err).Str("idempotency_key", key).Msg("[IdempotencyService-2] Begin: failed to load existing key")
+		return nil, err
+	}
+
+	if existing.RequestHash != requestHash {
+		return nil, errs.ErrIdempotencyKeyReused
+	}
+
+	if existing.Status != "COMPLETED" {
+		return nil, errs.ErrIdempotencyKeyInProgress
+	}
+
+	return existing, nil
+}
+
+// Complete implements IdempotencyServiceInterface.
+func (i *IdempotencyService) Complete(ctx context.Context, key string, scope string, responseCode int, responseBody []byte) error {
+	return i.idempotencyKeyRepo.Complete(ctx, key, scope, responseCode, responseBody)
+}
+
+// Release implements IdempotencyServiceInterface.
+func (i *IdempotencyService) Release(ctx context.Context, key string, scope string) error {
+	return i.idempotencyKeyRepo.Delete(ctx, key, scope)
+}
+
+// StartCleanup implements IdempotencyServiceInterface.
+func (i *IdempotencyService) StartCleanup(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := i.idempotencyKeyRepo.DeleteExpired(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("[IdempotencyService-3] StartCleanup: failed to delete expired keys")
+				continue
+			}
+			log.Info().Int64("deleted", deleted).Msg("Expired idempotency keys cleaned up")
+		}
+	}
+}
+
+func NewIdempotencyService(cfg *config.Config, idempotencyKeyRepo repository.IdempotencyKeyRepositoryInterface) IdempotencyServiceInterface {
+
+	ttl := 24 * time.Hour
+	if cfg.Idempotency.TTL > 0 {
+		ttl = cfg.Idempotency.TTL
+	}
+
+	return &IdempotencyService{
+		idempotencyKeyRepo: idempotencyKeyRepo,
+		ttl:                ttl,
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobProgressBroker fans job updates out to in-process subscribers, either of one job or of every job. It only
+// reaches listeners in this replica; the jobs table stays the source of truth for anyone who missed an update.
+type JobProgressBroker struct {
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[int]chan entity.JobUpdateEntity
+	all         map[int]chan entity.JobUpdateEntity
+	nextID      int
+}
+
+// Publish hands update to the subscribers of its job and to every all-jobs subscriber without blocking the
+// worker. A subscriber that has fallen behind loses its oldest pending update, so the latest one always gets through.
+func (b *JobProgressBroker) Publish(update entity.JobUpdateEntity) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subscribers[update.Job.ID] {
+		offer(ch, update)
+	}
+	for _, ch := range b.all {
+		offer(ch, update)
+	}
+}
+
+// Subscribe returns a channel receiving every update of jobID published from now on, and a function that ends
+// the subscription.
+func (b *JobProgressBroker) Subscribe(jobID uuid.UUID, buffer int) (<-chan entity.JobUpdateEntity, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan entity.JobUpdateEntity, buffer)
+	if b.subscribers[jobID] == nil {
+		b.subscribers[jobID] = make(map[int]chan entity.JobUpdateEntity)
+	}
+	b.subscribers[jobID][id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers[jobID], id)
+			if len(b.subscribers[jobID]) == 0 {
+				delete(b.subscribers, jobID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// SubscribeAll returns a channel receiving the updates of every job published from now on, and a function that
+// ends the subscription.
+func (b *JobProgressBroker) SubscribeAll(buffer int) (<-chan entity.JobUpdateEntity, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan entity.JobUpdateEntity, buffer)
+	b.all[id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.all, id)
+			close(ch)
+		})
+	}
+}
+
+func NewJobProgressBroker() *JobProgressBroker {
+	return &JobProgressBroker{
+		subscribers: make(map[uuid.UUID]map[int]chan entity.JobUpdateEntity),
+		all:         make(map[int]chan entity.JobUpdateEntity),
+	}
+}
+
+// offer sends update on ch, making room by discarding the oldest pending update when ch is full.
+func offer(ch chan entity.JobUpdateEntity, update entity.JobUpdateEntity) {
+	select {
+	case ch <- update:
+		return
+	default:
+	}
+
+	select {
+	case <-ch:
+	default:
+	}
+
+	select {
+	case ch <- update:
+	default:
+	}
+}
+
+// throughputSmoothing is how far each completed job moves its type's stored rate towards its own.
+const throughputSmoothing = 0.3
+
+// rowsPerSecond is the average rate at which processed rows went through in elapsed.
+func rowsPerSecond(processed int64, elapsed time.Duration) float64 {
+	if processed <= 0 || elapsed <= 0 {
+		return 0
+	}
+	return float64(processed) / elapsed.Seconds()
+}
+
+// estimateCompletion is when remaining rows are done if work goes on at rate from from. It returns nil when
+// there is no rate to go on.
+func estimateCompletion(rate float64, remaining int64, from time.Time) *time.Time {
+	if rate <= 0 {
+		return nil
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	eta := from.Add(time.Duration(float64(remaining) / rate * float64(time.Second)))
+	return &eta
+}
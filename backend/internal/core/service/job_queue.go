@@ -0,0 +1,170 @@
+package service
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueuedJob is a job waiting in, or handed out by, the JobQueue.
+type QueuedJob struct {
+	Type     string
+	Priority int
+	Job      entity.SettlementJob
+	queuedAt time.Time
+	seq      uint64
+}
+
+// JobQueue hands queued jobs to workers. The next job is the one of the highest priority whose type is below
+// its concurrency limit; a job gains a priority level for every aging interval it waits, so a stream of urgent
+// jobs cannot hold back the rest forever. Between jobs of equal priority the type with the fewest running jobs
+// goes first, then the job queued first. A job whose range overlaps a running job of its type waits for it.
+type JobQueue struct {
+	mu      sync.Mutex
+	pending []*QueuedJob
+	active  map[*QueuedJob]struct{}
+	running map[string]int
+	limits  map[string]int
+	aging   time.Duration
+	seq     uint64
+	// changed is closed and replaced whenever a job is queued or finishes, waking the workers waiting in Next.
+	changed chan struct{}
+}
+
+func NewJobQueue(limits map[string]int, aging time.Duration) *JobQueue {
+	return &JobQueue{
+		active:  make(map[*QueuedJob]struct{}),
+		running: make(map[string]int),
+		limits:  limits,
+		aging:   aging,
+		changed: make(chan struct{}),
+	}
+}
+
+// Push queues job.
+func (q *JobQueue) Push(jobType string, priority int, job entity.SettlementJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.seq++
+	q.pending = append(q.pending, &QueuedJob{
+		Type:     jobType,
+		Priority: priority,
+		Job:      job,
+		queuedAt: time.Now(),
+		seq:      q.seq,
+	})
+	q.notify()
+}
+
+// Remove takes the job out of the queue, reporting whether it was still waiting.
+func (q *JobQueue) Remove(jobID uuid.UUID) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, item := range q.pending {
+		if item.Job.ID == jobID {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// Next blocks until a job may run and counts it as running for its type; callers must call Done with the job
+// once it finishes. It returns false when ctx is done.
+func (q *JobQueue) Next(ctx context.Context) (*QueuedJob, bool) {
+	for {
+		q.mu.Lock()
+		if item := q.pop(time.Now()); item != nil {
+			q.active[item] = struct{}{}
+			q.running[item.Type]++
+			q.mu.Unlock()
+			return item, true
+		}
+		changed := q.changed
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-changed:
+		}
+	}
+}
+
+// Done releases the slot item held for its type.
+func (q *JobQueue) Done(item *QueuedJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.active, item)
+	q.running[item.Type]--
+	q.notify()
+}
+
+// Limit is the most jobs of jobType that may run at once, or zero for no limit.
+func (q *JobQueue) Limit(jobType string) int {
+	return q.limits[jobType]
+}
+
+// pop removes and returns the job to run next, or nil when every waiting job is held back by its type's limit
+// or by a running job it overlaps.
+func (q *JobQueue) pop(now time.Time) *QueuedJob {
+	best := -1
+	for i, item := range q.pending {
+		if limit := q.limits[item.Type]; limit > 0 && q.running[item.Type] >= limit {
+			continue
+		}
+		if q.overlapsActive(item) {
+			continue
+		}
+		if best < 0 || q.before(item, q.pending[best], now) {
+			best = i
+		}
+	}
+
+	if best < 0 {
+		return nil
+	}
+
+	item := q.pending[best]
+	q.pending = append(q.pending[:best], q.pending[best+1:]...)
+	return item
+}
+
+func (q *JobQueue) overlapsActive(item *QueuedJob) bool {
+	for active := range q.active {
+		if active.Type == item.Type && !active.Job.From.After(item.Job.To) && !item.Job.From.After(active.Job.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// before reports whether a should run before b.
+func (q *JobQueue) before(a, b *QueuedJob, now time.Time) bool {
+	if pa, pb := q.effectivePriority(a, now), q.effectivePriority(b, now); pa != pb {
+		return pa > pb
+	}
+	if ra, rb := q.running[a.Type], q.running[b.Type]; ra != rb {
+		return ra < rb
+	}
+	return a.seq < b.seq
+}
+
+func (q *JobQueue) effectivePriority(item *QueuedJob, now time.Time) int {
+	if q.aging <= 0 {
+		return item.Priority
+	}
+	return item.Priority + int(now.Sub(item.queuedAt)/q.aging)
+}
+
+func (q *JobQueue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
@@ -0,0 +1,362 @@
+package service
+
+import (
+	"backend-service/config"
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/pkg/cron"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	// Schedules name IANA time zones; embedding the database keeps them working on hosts without one.
+	_ "time/tzdata"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// jobScheduleMaxMissedRuns bounds the MISSED runs recorded for one schedule at a time, so a minutely schedule
+// that was down for a month does not write tens of thousands of rows.
+const jobScheduleMaxMissedRuns = 100
+
+type JobScheduleServiceInterface interface {
+	CreateSchedule(ctx context.Context, schedule entity.JobScheduleEntity) (*entity.JobScheduleEntity, error)
+	GetSchedule(ctx context.Context, scheduleID uuid.UUID) (*entity.JobScheduleEntity, error)
+	ListSchedules(ctx context.Context) ([]entity.JobScheduleEntity, error)
+	UpdateSchedule(ctx context.Context, schedule entity.JobScheduleEntity) (*entity.JobScheduleEntity, error)
+	DeleteSchedule(ctx context.Context, scheduleID uuid.UUID) error
+	ListRuns(ctx context.Context, scheduleID uuid.UUID, status string, limit int) ([]entity.JobScheduleRunEntity, error)
+	FireDue(ctx context.Context) (int, error)
+}
+
+type JobScheduleService struct {
+	scheduleRepo repository.JobScheduleRepositoryInterface
+	jobService   JobServiceInterface
+	txManager    repository.TxManagerInterface
+	misfireGrace time.Duration
+	batchSize    int
+}
+
+// claimedRun is an occurrence this instance has claimed and still has to create the job for.
+type claimedRun struct {
+	schedule entity.JobScheduleEntity
+	run      entity.JobScheduleRunEntity
+}
+
+// FireDue implements JobScheduleServiceInterface.
+// Due schedules are claimed in a transaction that locks them from other instances and moves them to their next
+// occurrence; the jobs are created once that has committed, so each occurrence fires on one instance only. An
+// instance stopping in between leaves its run PENDING; runs still PENDING a misfire grace after they were claimed
+// are recorded as MISSED, as by then they would not have fired anyway.
+func (j *JobScheduleService) FireDue(ctx context.Context) (int, error) {
+
+	fired := 0
+	for {
+		var (
+			batch   int
+			claimed []claimedRun
+			now     = time.Now()
+		)
+
+		err := j.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			message := fmt.Sprintf("job not created within %s of the run being claimed", j.misfireGrace)
+			stale, err := j.scheduleRepo.MissStaleRuns(ctx, now.Add(-j.misfireGrace), message)
+			if err != nil {
+				return err
+			}
+			if stale > 0 {
+				log.Warn().Int64("runs", stale).Msg("[JobScheduleService-8] FireDue: runs left pending by a stopped instance recorded as missed")
+			}
+
+			schedules, err := j.scheduleRepo.GetDue(ctx, now, j.batchSize)
+			if err != nil {
+				return err
+			}
+
+			batch = len(schedules)
+			for _, schedule := range schedules {
+				run, err := j.claim(ctx, schedule, now)
+				if err != nil {
+					return err
+				}
+				if run != nil {
+					claimed = append(claimed, claimedRun{schedule: schedule, run: *run})
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("[JobScheduleService-1] FireDue: failed to claim due schedules")
+			return fired, err
+		}
+
+		for _, c := range claimed {
+			if j.fire(ctx, c.schedule, c.run) {
+				fired++
+			}
+		}
+
+		if batch < j.batchSize {
+			break
+		}
+	}
+
+	return fired, nil
+
+}
+
+// claim records every occurrence of schedule up to now and moves it to its next one. The latest occurrence is
+// returned as a PENDING run to fire if it is within the misfire grace; the rest are recorded as MISSED.
+func (j *JobScheduleService) claim(ctx context.Context, schedule entity.JobScheduleEntity, now time.Time) (*entity.JobScheduleRunEntity, error) {
+
+	cronSchedule, loc, err := parseSchedule(schedule)
+	if err != nil {
+		// Only possible if the time zone database changed under a stored schedule; stop it rather than retry forever.
+		log.Error().Err(err).Str("schedule_id", schedule.ID.String()).Msg("[JobScheduleService-2] claim: schedule can no longer be evaluated, deactivating it")
+		message := err.Error()
+		if _, err := j.scheduleRepo.CreateRun(ctx, entity.JobScheduleRunEntity{
+			ScheduleID:   schedule.ID,
+			ScheduledFor: schedule.NextRunAt,
+			Status:       entity.ScheduleRunFailed,
+			Error:        &message,
+		}); err != nil {
+			return nil, err
+		}
+		schedule.Active = false
+		return nil, j.scheduleRepo.Update(ctx, schedule)
+	}
+
+	var (
+		due     []time.Time
+		skipped int
+		next    = schedule.NextRunAt
+	)
+	for !next.IsZero() && !next.After(now) {
+		due = append(due, next)
+		if len(due) > jobScheduleMaxMissedRuns+1 {
+			due = due[1:]
+			skipped++
+		}
+		next = cronSchedule.Next(next.In(loc))
+	}
+
+	if skipped > 0 {
+		log.Warn().Str("schedule_id", schedule.ID.String()).Int("skipped", skipped).Msg("[JobScheduleService-3] claim: too many missed runs, only the latest are recorded")
+	}
+
+	latest := due[len(due)-1]
+	missed := due
+	fire := now.Sub(latest) <= j.misfireGrace
+	if fire {
+		missed = due[:len(due)-1]
+	}
+
+	for _, scheduledFor := range missed {
+		message := fmt.Sprintf("not fired within %s of its scheduled time", j.misfireGrace)
+		if _, err := j.scheduleRepo.CreateRun(ctx, entity.JobScheduleRunEntity{
+			ScheduleID:   schedule.ID,
+			ScheduledFor: scheduledFor,
+			Status:       entity.ScheduleRunMissed,
+			Error:        &message,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	var run *entity.JobScheduleRunEntity
+	if fire {
+		if run, err = j.scheduleRepo.CreateRun(ctx, entity.JobScheduleRunEntity{
+			ScheduleID:   schedule.ID,
+			ScheduledFor: latest,
+			Status:       entity.ScheduleRunPending,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if next.IsZero() {
+		// The expression has no occurrence left within cron's look-ahead; keep the schedule but stop evaluating it.
+		schedule.Active = false
+		schedule.NextRunAt = latest
+		return run, j.scheduleRepo.Update(ctx, schedule)
+	}
+
+	var lastRunAt *time.Time
+	if fire {
+		lastRunAt = &latest
+	}
+
+	return run, j.scheduleRepo.Advance(ctx, schedule.ID, next, lastRunAt)
+
+}
+
+// fire creates the job for a claimed run and records the outcome on the run. It reports whether a job was created.
+func (j *JobScheduleService) fire(ctx context.Context, schedule entity.JobScheduleEntity, run entity.JobScheduleRunEntity) bool {
+
+	_, loc, err := parseSchedule(schedule)
+	if err == nil {
+		var from, to string
+		if from, to, err = entity.SettlementRange(schedule.DateRange, run.ScheduledFor.In(loc)); err == nil {
+			var job *entity.JobEntity
+			// An identical job already active is taken as this run's; an overlapping one fails the run.
+			if job, _, err = j.jobService.CreateSettlementJob(ctx, from, to, entity.JobPriorityDefault, 0, false); err == nil {
+				run.JobID = &job.ID
+			}
+		}
+	}
+
+	run.Status = entity.ScheduleRunFired
+	if err != nil {
+		log.Error().Err(err).Str("schedule_id", schedule.ID.String()).Msg("[JobScheduleService-4] fire: failed to create scheduled job")
+		message := err.Error()
+		run.Status = entity.ScheduleRunFailed
+		run.Error = &message
+	}
+
+	updated, err := j.scheduleRepo.UpdateRun(ctx, run)
+	if err != nil {
+		log.Error().Err(err).Str("run_id", run.ID.String()).Msg("[JobScheduleService-5] fire: failed to record run outcome")
+	} else if !updated {
+		// Another instance recorded the run as missed while the job was being created; the run keeps that outcome.
+		event := log.Warn().Str("run_id", run.ID.String()).Str("outcome", run.Status)
+		if run.JobID != nil {
+			event = event.Str("job_id", run.JobID.String())
+		}
+		event.Msg("[JobScheduleService-9] fire: run was no longer pending, outcome not recorded")
+		return false
+	}
+
+	return run.Status == entity.ScheduleRunFired
+
+}
+
+// ListRuns implements JobScheduleServiceInterface.
+func (j *JobScheduleService) ListRuns(ctx context.Context, scheduleID uuid.UUID, status string, limit int) ([]entity.JobScheduleRunEntity, error) {
+
+	if _, err := j.scheduleRepo.GetByID(ctx, scheduleID); err != nil {
+		return nil, err
+	}
+
+	return j.scheduleRepo.ListRuns(ctx, scheduleID, status, limit)
+
+}
+
+// DeleteSchedule implements JobScheduleServiceInterface.
+func (j *JobScheduleService) DeleteSchedule(ctx context.Context, scheduleID uuid.UUID) error {
+	return j.scheduleRepo.Delete(ctx, scheduleID)
+}
+
+// UpdateSchedule implements JobScheduleServiceInterface.
+// The next run is worked out again from now, so occurrences skipped by the change are not reported as missed.
+func (j *JobScheduleService) UpdateSchedule(ctx context.Context, schedule entity.JobScheduleEntity) (*entity.JobScheduleEntity, error) {
+
+	if _, err := j.scheduleRepo.GetByID(ctx, schedule.ID); err != nil {
+		return nil, err
+	}
+
+	if err := prepareSchedule(&schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := j.scheduleRepo.Update(ctx, schedule); err != nil {
+		log.Error().Err(err).Str("schedule_id", schedule.ID.String()).Msg("[JobScheduleService-6] UpdateSchedule: failed to update schedule")
+		return nil, err
+	}
+
+	return j.scheduleRepo.GetByID(ctx, schedule.ID)
+
+}
+
+// ListSchedules implements JobScheduleServiceInterface.
+func (j *JobScheduleService) ListSchedules(ctx context.Context) ([]entity.JobScheduleEntity, error) {
+	return j.scheduleRepo.List(ctx)
+}
+
+// GetSchedule implements JobScheduleServiceInterface.
+func (j *JobScheduleService) GetSchedule(ctx context.Context, scheduleID uuid.UUID) (*entity.JobScheduleEntity, error) {
+	return j.scheduleRepo.GetByID(ctx, scheduleID)
+}
+
+// CreateSchedule implements JobScheduleServiceInterface.
+func (j *JobScheduleService) CreateSchedule(ctx context.Context, schedule entity.JobScheduleEntity) (*entity.JobScheduleEntity, error) {
+
+	if err := prepareSchedule(&schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	created, err := j.scheduleRepo.Create(ctx, schedule)
+	if err != nil {
+		log.Error().Err(err).Msg("[JobScheduleService-7] CreateSchedule: failed to create schedule")
+		return nil, err
+	}
+
+	return created, nil
+
+}
+
+// prepareSchedule fills in defaults, validates schedule and sets its next run to the first occurrence after now.
+func prepareSchedule(schedule *entity.JobScheduleEntity, now time.Time) error {
+
+	if schedule.Timezone == "" {
+		schedule.Timezone = "UTC"
+	}
+	if schedule.JobType == "" {
+		schedule.JobType = "SETTLEMENT"
+	}
+
+	if schedule.JobType != "SETTLEMENT" {
+		return fmt.Errorf("%w: only SETTLEMENT jobs can be scheduled", errs.ErrInvalidJobSchedule)
+	}
+	if !slices.Contains(entity.ScheduleDateRanges, schedule.DateRange) {
+		return fmt.Errorf("%w: date_range must be one of %v", errs.ErrInvalidJobSchedule, entity.ScheduleDateRanges)
+	}
+
+	cronSchedule, loc, err := parseSchedule(*schedule)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errs.ErrInvalidJobSchedule, err)
+	}
+
+	next := cronSchedule.Next(now.In(loc))
+	if next.IsZero() {
+		return fmt.Errorf("%w: cron expression never matches", errs.ErrInvalidJobSchedule)
+	}
+	schedule.NextRunAt = next
+
+	return nil
+
+}
+
+func parseSchedule(schedule entity.JobScheduleEntity) (*cron.Schedule, *time.Location, error) {
+
+	cronSchedule, err := cron.Parse(schedule.CronExpression)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unknown time zone %q", schedule.Timezone)
+	}
+
+	return cronSchedule, loc, nil
+
+}
+
+func NewJobScheduleService(cfg *config.Config, scheduleRepo repository.JobScheduleRepositoryInterface, jobService JobServiceInterface, txManager repository.TxManagerInterface) JobScheduleServiceInterface {
+
+	misfireGrace := time.Hour
+	if cfg.Scheduler.MisfireGrace > 0 {
+		misfireGrace = cfg.Scheduler.MisfireGrace
+	}
+
+	return &JobScheduleService{
+		scheduleRepo: scheduleRepo,
+		jobService:   jobService,
+		txManager:    txManager,
+		misfireGrace: misfireGrace,
+		batchSize:    50,
+	}
+}
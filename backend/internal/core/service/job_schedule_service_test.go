@@ -0,0 +1,52 @@
+package service
+
+import (
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"testing"
+	"time"
+)
+
+// inlineTx runs the unit of work without a database.
+type inlineTx struct{}
+
+func (inlineTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// staleRunsRepo has no due schedules and records the cut-off FireDue sweeps PENDING runs with.
+type staleRunsRepo struct {
+	repository.JobScheduleRepositoryInterface
+	claimedBefore []time.Time
+}
+
+func (r *staleRunsRepo) MissStaleRuns(_ context.Context, claimedBefore time.Time, _ string) (int64, error) {
+	r.claimedBefore = append(r.claimedBefore, claimedBefore)
+	return 1, nil
+}
+
+func (r *staleRunsRepo) GetDue(context.Context, time.Time, int) ([]entity.JobScheduleEntity, error) {
+	return nil, nil
+}
+
+func TestFireDueMissesRunsLeftPending(t *testing.T) {
+	repo := &staleRunsRepo{}
+	j := &JobScheduleService{scheduleRepo: repo, txManager: inlineTx{}, misfireGrace: time.Hour, batchSize: 10}
+
+	before := time.Now()
+	fired, err := j.FireDue(context.Background())
+	if err != nil {
+		t.Fatalf("FireDue: %v", err)
+	}
+	if fired != 0 {
+		t.Errorf("fired %d runs, want 0", fired)
+	}
+
+	if len(repo.claimedBefore) != 1 {
+		t.Fatalf("stale runs swept %d times, want 1", len(repo.claimedBefore))
+	}
+	if cutoff := repo.claimedBefore[0]; cutoff.Before(before.Add(-time.Hour)) || cutoff.After(time.Now().Add(-time.Hour)) {
+		t.Errorf("runs claimed before %s swept, want a misfire grace before now", cutoff)
+	}
+}
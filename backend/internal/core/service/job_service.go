@@ -7,7 +7,9 @@ import (
 	errs "backend-service/internal/core/domain/error"
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,17 +17,166 @@ import (
 )
 
 type JobServiceInterface interface {
-	CreateSettlementJob(ctx context.Context, from string, to string) (*entity.JobEntity, error)
+	CreateSettlementJob(ctx context.Context, from string, to string, priority int, timeout time.Duration, force bool) (*entity.JobEntity, bool, error)
 	GetJob(ctx context.Context, jobID uuid.UUID) (*entity.JobEntity, error)
+	GetQueueStats(ctx context.Context) (*entity.JobQueueStatsEntity, error)
 	StartWorkerPool(ctx context.Context)
 	CancelJob(ctx context.Context, jobID uuid.UUID) error
+	PauseJob(ctx context.Context, jobID uuid.UUID) error
+	ResumeJob(ctx context.Context, jobID uuid.UUID) (*entity.JobEntity, error)
+	AddPeriodicTask(task PeriodicTask)
+	SubscribeJob(ctx context.Context, jobID uuid.UUID) (*entity.JobEntity, <-chan entity.JobUpdateEntity, func(), error)
+	WatchJobs(buffer int) (<-chan entity.JobUpdateEntity, func())
+	RunWatchdog(ctx context.Context) (int, error)
 }
 
 type JobService struct {
 	jobRepo         repository.JobRepositoryInterface
 	transactionRepo repository.TransactionRepositoryInterface
+	outboxRepo      repository.OutboxRepositoryInterface
+	txManager       repository.TxManagerInterface
 	workerPool      *WorkerPool
-	activeJobs      map[uuid.UUID]chan bool
+	progress        *JobProgressBroker
+	typeTimeouts    map[string]time.Duration
+	stallTimeout    time.Duration
+}
+
+// GetQueueStats implements JobServiceInterface.
+// Counts cover the jobs of every replica; the limits are this replica's.
+func (j *JobService) GetQueueStats(ctx context.Context) (*entity.JobQueueStatsEntity, error) {
+
+	depths, err := j.jobRepo.GetQueueDepth(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[JobService-9] GetQueueStats: failed to get queue depth")
+		return nil, err
+	}
+
+	stats := &entity.JobQueueStatsEntity{Watchdog: j.workerPool.watchdog.stats()}
+	// The maps index into the slices rather than point into them, as appending may move their elements.
+	priorities := map[int]int{}
+	types := map[string]int{}
+	for _, depth := range depths {
+		t, ok := types[depth.Type]
+		if !ok {
+			stats.Types = append(stats.Types, entity.JobTypeDepthEntity{Type: depth.Type, Limit: j.workerPool.queue.Limit(depth.Type)})
+			t = len(stats.Types) - 1
+			types[depth.Type] = t
+		}
+
+		if depth.Status == "RUNNING" {
+			stats.Types[t].Running += depth.Jobs
+			continue
+		}
+		stats.Types[t].Queued += depth.Jobs
+
+		p, ok := priorities[depth.Priority]
+		if !ok {
+			stats.Priorities = append(stats.Priorities, entity.JobPriorityDepthEntity{Priority: depth.Priority})
+			p = len(stats.Priorities) - 1
+			priorities[depth.Priority] = p
+		}
+		stats.Priorities[p].Queued += depth.Jobs
+		stats.Priorities[p].QueuedRows += depth.RemainingRows
+	}
+
+	return stats, nil
+}
+
+// WatchJobs implements JobServiceInterface.
+// The returned channel carries the updates of every job in this replica; callers must call the returned function.
+func (j *JobService) WatchJobs(buffer int) (<-chan entity.JobUpdateEntity, func()) {
+	return j.progress.SubscribeAll(buffer)
+}
+
+// SubscribeJob implements JobServiceInterface.
+// It returns the job as stored now and a channel of the updates the worker publishes after that; the
+// subscription is taken before the read so no update falls in between. Callers must call the returned function.
+func (j *JobService) SubscribeJob(ctx context.Context, jobID uuid.UUID) (*entity.JobEntity, <-chan entity.JobUpdateEntity, func(), error) {
+
+	updates, unsubscribe := j.progress.Subscribe(jobID, 16)
+
+	job, err := j.GetJob(ctx, jobID)
+	if err != nil {
+		unsubscribe()
+		log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobService-7] SubscribeJob: failed to get job")
+		return nil, nil, nil, err
+	}
+
+	return job, updates, unsubscribe, nil
+}
+
+// ResumeJob implements JobServiceInterface.
+// The job is queued again at its priority and continues from its checkpoint, if it got as far as taking one.
+func (j *JobService) ResumeJob(ctx context.Context, jobID uuid.UUID) (*entity.JobEntity, error) {
+
+	job, err := j.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobService-10] ResumeJob: failed to get job")
+		return nil, err
+	}
+
+	if job.Status != "PAUSED" || job.UniqueRunID == nil {
+		return nil, errs.ErrJobCannotBeResumed
+	}
+
+	settlementJob, err := j.settlementJobFor(*job)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobService-11] ResumeJob: failed to read job")
+		return nil, err
+	}
+
+	// Only one of two concurrent resumes gets to queue the job.
+	resumed, err := j.jobRepo.TransitionStatus(ctx, jobID, "PAUSED", "QUEUED")
+	if err != nil {
+		return nil, err
+	}
+	if !resumed {
+		return nil, errs.ErrJobCannotBeResumed
+	}
+
+	job.Status = "QUEUED"
+	j.enqueue(*job, settlementJob)
+
+	log.Info().Str("job_id", jobID.String()).Bool("from_checkpoint", settlementJob.Checkpoint != nil).Msg("Settlement job resumed")
+
+	return job, nil
+}
+
+// PauseJob implements JobServiceInterface.
+// A queued job is paused straight away. A running one has its context cancelled, which interrupts the query or
+// write in flight; it saves a checkpoint of the batches it finished and only shows as PAUSED once it has.
+func (j *JobService) PauseJob(ctx context.Context, jobID uuid.UUID) error {
+
+	job, err := j.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobService-13] PauseJob: failed to get job")
+		return err
+	}
+
+	if job.Status != "QUEUED" && job.Status != "RUNNING" {
+		return errs.ErrJobCannotBePaused
+	}
+
+	if job.Status == "QUEUED" && (j.workerPool.RemoveJob(jobID) || !j.workerPool.HasJob(jobID)) {
+		paused, err := j.jobRepo.TransitionStatus(ctx, jobID, "QUEUED", "PAUSED")
+		if err != nil {
+			return err
+		}
+		if paused {
+			job.Status = "PAUSED"
+			j.progress.Publish(entity.JobUpdateEntity{Event: entity.JobUpdatePaused, Job: *job})
+			return nil
+		}
+	}
+
+	if !j.workerPool.StopJob(jobID, errJobPaused) {
+		// Running on another replica, or left behind by a restart.
+		return errs.ErrJobCannotBePaused
+	}
+
+	log.Info().Str("job_id", jobID.String()).Msg("[JobService-14] PauseJob: job context cancelled for pause")
+
+	return nil
 }
 
 // CancelJob implements JobServiceInterface.
@@ -37,42 +188,70 @@ func (j *JobService) CancelJob(ctx context.Context, jobID uuid.UUID) error {
 		return err
 	}
 
-	if job.Status != "QUEUED" && job.Status != "RUNNING" {
+	if job.Status != "QUEUED" && job.Status != "RUNNING" && job.Status != "PAUSED" {
 		return errs.ErrJobCannotBeCancelled
 	}
 
-	if cancelChan, exists := j.activeJobs[jobID]; exists {
-		select {
-		case cancelChan <- true:
-			log.Info().Str("job_id", jobID.String()).Msg("[JobService-2] CancelJob: Cancellation signal sent to worker")
-		default:
-			log.Warn().Str("job_id", jobID.String()).Msg("[JobService-3] CancelJob: Cancellation signal already sent or worker not listening")
-		}
-		delete(j.activeJobs, jobID)
+	// A worker of this replica that has taken the job records CANCELLED itself once its queries have aborted.
+	if j.workerPool.StopJob(jobID, errJobCancelled) {
+		log.Info().Str("job_id", jobID.String()).Msg("[JobService-2] CancelJob: job context cancelled")
+		return nil
 	}
 
-	if job.Status == "QUEUED" {
-		completedAt := time.Now()
-		err = j.jobRepo.UpdateStatus(ctx, jobID, "CANCELLED", nil)
-		if err != nil {
-			log.Error().Err(err).Str("job_id", jobID.String()).Msg(" [JobService-4] CancelJob: Failed to update job status to CANCELLED")
+	if job.Status == "RUNNING" {
+		// Running on another replica, or left behind by a restart.
+		return errs.ErrJobCannotBeCancelled
+	}
+
+	j.workerPool.RemoveJob(jobID)
+
+	// Only the status change that wins records the event, so a job is cancelled once.
+	cancelled := false
+	completedAt := time.Now()
+	err = j.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		changed, err := j.jobRepo.TransitionStatus(ctx, jobID, job.Status, "CANCELLED")
+		if err != nil || !changed {
 			return err
 		}
 
-		err = j.jobRepo.UpdateCancelledFlag(ctx, jobID, true)
-		if err != nil {
-			log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobService-5] CancelJob: Failed to update cancelled flag for cancelled job")
+		if err := j.jobRepo.UpdateCancelledFlag(ctx, jobID, true); err != nil {
+			return err
 		}
 
-		err = j.jobRepo.UpdateCompletedAt(ctx, jobID, &completedAt)
-		if err != nil {
-			log.Error().Err(err).Str("job_id", jobID.String()).Msg("[JobService-5] CancelJob: Failed to update completed_at for cancelled job")
+		if err := j.jobRepo.UpdateCompletedAt(ctx, jobID, &completedAt); err != nil {
+			return err
+		}
+
+		cancelled = true
+		job.Status = "CANCELLED"
+		job.Cancelled = true
+		job.CompletedAt = &completedAt
+		return recordJobEvent(ctx, j.outboxRepo, entity.EventJobCancelled, *job)
+	})
+	if err != nil {
+		log.Error().Err(err).Str("job_id", jobID.String()).Msg(" [JobService-4] CancelJob: Failed to update job status to CANCELLED")
+		return err
+	}
+
+	if !cancelled {
+		// A worker took the job in the meantime.
+		if j.workerPool.StopJob(jobID, errJobCancelled) {
+			log.Info().Str("job_id", jobID.String()).Msg("[JobService-3] CancelJob: job context cancelled")
+			return nil
 		}
+		return errs.ErrJobCannotBeCancelled
 	}
 
+	j.progress.Publish(entity.JobUpdateEntity{Event: entity.JobUpdateCancelled, Job: *job})
+
 	return nil
 }
 
+// AddPeriodicTask implements JobServiceInterface.
+func (j *JobService) AddPeriodicTask(task PeriodicTask) {
+	j.workerPool.AddPeriodicTask(task)
+}
+
 // StartWorkerPool implements JobServiceInterface.
 func (j *JobService) StartWorkerPool(ctx context.Context) {
 	j.workerPool.Start(ctx)
@@ -80,33 +259,103 @@ func (j *JobService) StartWorkerPool(ctx context.Context) {
 
 // GetJob implements JobServiceInterface.
 func (j *JobService) GetJob(ctx context.Context, jobID uuid.UUID) (*entity.JobEntity, error) {
-	return j.jobRepo.GetByID(ctx, jobID)
+
+	job, err := j.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status == "QUEUED" || job.Status == "RUNNING" {
+		if err := j.estimate(ctx, job, time.Now()); err != nil {
+			// Estimates are a convenience; the job itself is still worth returning.
+			log.Warn().Err(err).Str("job_id", jobID.String()).Msg("[JobService-8] GetJob: failed to estimate completion")
+		}
+	}
+
+	return job, nil
+}
+
+// estimate fills in when job should start and finish. A running job is extrapolated from its own rate, falling
+// back to the rate past jobs of its type sustained until its first batch is in. A queued job waits for the rows
+// queued ahead of it, shared out over the workers, each type at its own historical rate.
+func (j *JobService) estimate(ctx context.Context, job *entity.JobEntity, now time.Time) error {
+
+	if job.Status == "RUNNING" && job.Throughput > 0 {
+		job.EstimatedCompletionAt = estimateCompletion(job.Throughput, job.Total-job.Processed, now)
+		return nil
+	}
+
+	stats, err := j.jobRepo.GetThroughputStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	rate := stats[job.Type]
+	if rate <= 0 {
+		return nil
+	}
+
+	if job.Status == "RUNNING" {
+		job.EstimatedCompletionAt = estimateCompletion(rate, job.Total-job.Processed, now)
+		return nil
+	}
+
+	backlog, err := j.jobRepo.GetBacklogAhead(ctx, *job)
+	if err != nil {
+		return err
+	}
+
+	var waitSeconds float64
+	for jobType, rows := range backlog {
+		typeRate := stats[jobType]
+		if typeRate <= 0 {
+			typeRate = rate
+		}
+		waitSeconds += float64(rows) / typeRate
+	}
+
+	startAt := now.Add(time.Duration(waitSeconds / float64(j.workerPool.workerCount) * float64(time.Second)))
+	job.EstimatedStartAt = &startAt
+	job.EstimatedCompletionAt = estimateCompletion(rate, job.Total, startAt)
+	return nil
 }
 
 // CreateSettlementJob implements JobServiceInterface.
-func (j *JobService) CreateSettlementJob(ctx context.Context, from string, to string) (*entity.JobEntity, error) {
+// Two settlement jobs over the same days would race to write the same settlement rows. Unless force is set, an
+// active job with the same range is returned instead of a new one, reporting created as false, and one with an
+// overlapping range is an error. A forced job is queued and waits for the overlapping jobs this replica runs to
+// finish.
+func (j *JobService) CreateSettlementJob(ctx context.Context, from string, to string, priority int, timeout time.Duration, force bool) (*entity.JobEntity, bool, error) {
+
+	if priority < entity.JobPriorityMin || priority > entity.JobPriorityMax {
+		return nil, false, errs.ErrInvalidJobPriority
+	}
+
+	if timeout < 0 || timeout%time.Second != 0 {
+		return nil, false, errs.ErrInvalidJobTimeout
+	}
 
 	fromTime, err := time.Parse("2006-01-02", from)
 	if err != nil {
 		log.Error().Err(err).Msg("[JobService-1] CreateSettlementJob: failed to parse from date")
-		return nil, errs.ErrInvalidDateRange
+		return nil, false, errs.ErrInvalidDateRange
 	}
 
 	toTime, err := time.Parse("2006-01-02", to)
 	if err != nil {
 		log.Error().Err(err).Msg("[JobService-3] CreateSettlementJob: failed to parse to date")
-		return nil, errs.ErrInvalidDateRange
+		return nil, false, errs.ErrInvalidDateRange
 	}
 
 	if fromTime.After(toTime) {
 		log.Error().Msg("[JobService-2] CreateSettlementJob: from date is after to date")
-		return nil, errs.ErrInvalidDateRange
+		return nil, false, errs.ErrInvalidDateRange
 	}
 
 	total, err := j.transactionRepo.Count(ctx, fromTime, toTime)
 	if err != nil {
 		log.Error().Err(err).Msg("[JobService-4] CreateSettlementJob: failed to count transactions")
-		return nil, err
+		return nil, false, err
 	}
 
 	params := entity.SettlementJobParams{
@@ -117,71 +366,199 @@ func (j *JobService) CreateSettlementJob(ctx context.Context, from string, to st
 	paramsJSON, err := json.Marshal(params)
 	if err != nil {
 		log.Error().Err(err).Msg("[JobService-5] CreateSettlementJob: failed to marshal params")
-		return nil, err
+		return nil, false, err
 	}
 
 	uniqueRunID := uuid.New().String()
 	job := &entity.JobEntity{
 		Type:        "SETTLEMENT",
 		Status:      "QUEUED",
+		Priority:    priority,
+		Timeout:     timeout,
 		Total:       total,
 		Params:      string(paramsJSON),
 		UniqueRunID: &uniqueRunID,
 	}
 
-	jobID, err := j.jobRepo.Create(ctx, job)
+	var existing *entity.JobEntity
+	err = j.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := j.jobRepo.LockSettlementJobs(ctx); err != nil {
+			return err
+		}
+
+		overlaps, err := j.jobRepo.GetActiveSettlementOverlaps(ctx, fromTime, toTime)
+		if err != nil {
+			return err
+		}
+
+		if !force {
+			for _, overlap := range overlaps {
+				var overlapParams entity.SettlementJobParams
+				if err := json.Unmarshal([]byte(overlap.Params), &overlapParams); err != nil {
+					return err
+				}
+
+				if overlapParams == params {
+					existing = &overlap
+					return nil
+				}
+			}
+
+			if len(overlaps) > 0 {
+				var overlapParams entity.SettlementJobParams
+				if err := json.Unmarshal([]byte(overlaps[0].Params), &overlapParams); err != nil {
+					return err
+				}
+				return fmt.Errorf("%w: %s job %s covers %s to %s; retry with force=true to run after it",
+					errs.ErrSettlementJobOverlaps, strings.ToLower(overlaps[0].Status), overlaps[0].ID, overlapParams.From, overlapParams.To)
+			}
+		}
+
+		jobID, err := j.jobRepo.Create(ctx, job)
+		if err != nil {
+			return err
+		}
+
+		job.ID = jobID
+		return nil
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("[JobService-6] CreateSettlementJob: failed to create job")
-		return nil, err
+		return nil, false, err
 	}
 
-	job.ID = jobID
+	if existing != nil {
+		log.Info().Str("job_id", existing.ID.String()).Str("from", from).Str("to", to).Msg("Settlement job already active, returning it")
+		return existing, false, nil
+	}
 
-	cancelChan := make(chan bool, 1)
-	j.activeJobs[jobID] = cancelChan
+	jobID := job.ID
 
-	settlementJob := entity.SettlementJob{
+	j.enqueue(*job, entity.SettlementJob{
 		ID:        jobID,
+		Priority:  priority,
 		From:      fromTime,
 		To:        toTime,
 		RunID:     uniqueRunID,
 		BatchSize: 100,
-		Cancelled: cancelChan,
-	}
-
-	go func() {
-		j.workerPool.AddJob(settlementJob)
-
-		go func() {
-			ticker := time.NewTicker(5 * time.Second)
-			defer ticker.Stop()
-
-			for range ticker.C {
-				currentJob, err := j.jobRepo.GetByID(context.Background(), jobID)
-				if err != nil {
-					break
-				}
-
-				if currentJob.Status == "COMPLETED" || currentJob.Status == "FAILED" || currentJob.Status == "CANCELLED" {
-					delete(j.activeJobs, jobID)
-					close(cancelChan)
-					break
-				}
-			}
-		}()
-	}()
+		Timeout:   j.timeoutFor(*job),
+	})
 
 	log.Info().
 		Str("job_id", jobID.String()).
 		Str("from", from).
 		Str("to", to).
+		Int("priority", priority).
 		Int64("total", total).
 		Msg("Settlement job created and queued")
 
-	return job, nil
+	return job, true, nil
+}
+
+// settlementJobFor rebuilds the worker's view of a stored settlement job, including the checkpoint it resumes
+// from, if it has one.
+func (j *JobService) settlementJobFor(job entity.JobEntity) (entity.SettlementJob, error) {
+
+	var params entity.SettlementJobParams
+	if err := json.Unmarshal([]byte(job.Params), &params); err != nil {
+		return entity.SettlementJob{}, err
+	}
+
+	fromTime, err := time.Parse("2006-01-02", params.From)
+	if err != nil {
+		return entity.SettlementJob{}, err
+	}
+
+	toTime, err := time.Parse("2006-01-02", params.To)
+	if err != nil {
+		return entity.SettlementJob{}, err
+	}
+
+	var checkpoint *entity.SettlementCheckpoint
+	if job.Checkpoint != nil {
+		checkpoint = &entity.SettlementCheckpoint{}
+		if err := json.Unmarshal([]byte(*job.Checkpoint), checkpoint); err != nil {
+			return entity.SettlementJob{}, err
+		}
+	}
+
+	var runID string
+	if job.UniqueRunID != nil {
+		runID = *job.UniqueRunID
+	}
+
+	return entity.SettlementJob{
+		ID:         job.ID,
+		Priority:   job.Priority,
+		From:       fromTime,
+		To:         toTime,
+		RunID:      runID,
+		BatchSize:  100,
+		Timeout:    j.timeoutFor(job),
+		Requeues:   job.Requeues,
+		Checkpoint: checkpoint,
+		StartedAt:  job.StartedAt,
+	}, nil
+}
+
+// timeoutFor is the job's own max runtime, or else its type's; zero means none.
+func (j *JobService) timeoutFor(job entity.JobEntity) time.Duration {
+	if job.Timeout > 0 {
+		return job.Timeout
+	}
+	return j.typeTimeouts[job.Type]
+}
+
+// enqueue announces job as queued and hands settlementJob to the worker pool.
+func (j *JobService) enqueue(job entity.JobEntity, settlementJob entity.SettlementJob) {
+	j.progress.Publish(entity.JobUpdateEntity{Event: entity.JobUpdateQueued, Job: job})
+	j.workerPool.AddJob(settlementJob)
+}
+
+// parseTypeConcurrency reads a "TYPE=limit,..." list. Malformed entries are logged and skipped.
+func parseTypeConcurrency(raw string) map[string]int {
+	limits := make(map[string]int)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		jobType, value, found := strings.Cut(entry, "=")
+		limit, err := strconv.Atoi(strings.TrimSpace(value))
+		if !found || err != nil || limit < 1 {
+			log.Warn().Str("entry", entry).Msg("ignoring malformed WORKERS_TYPE_CONCURRENCY entry")
+			continue
+		}
+
+		limits[strings.ToUpper(strings.TrimSpace(jobType))] = limit
+	}
+	return limits
+}
+
+// parseTypeTimeouts reads a "TYPE=duration,..." list such as SETTLEMENT=2h. Malformed entries are logged and
+// skipped.
+func parseTypeTimeouts(raw string) map[string]time.Duration {
+	timeouts := make(map[string]time.Duration)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		jobType, value, found := strings.Cut(entry, "=")
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if !found || err != nil || timeout < time.Second {
+			log.Warn().Str("entry", entry).Msg("ignoring malformed WORKERS_TYPE_TIMEOUT entry")
+			continue
+		}
+
+		timeouts[strings.ToUpper(strings.TrimSpace(jobType))] = timeout.Truncate(time.Second)
+	}
+	return timeouts
 }
 
-func NewJobService(cfg *config.Config, jobRepo repository.JobRepositoryInterface, transactionRepo repository.TransactionRepositoryInterface, settlementRepo repository.SettlementRepositoryInterface) JobServiceInterface {
+func NewJobService(cfg *config.Config, jobRepo repository.JobRepositoryInterface, transactionRepo repository.TransactionRepositoryInterface, settlementRepo repository.SettlementRepositoryInterface, outboxRepo repository.OutboxRepositoryInterface, txManager repository.TxManagerInterface) JobServiceInterface {
 
 	workerCount := 4
 
@@ -191,12 +568,33 @@ func NewJobService(cfg *config.Config, jobRepo repository.JobRepositoryInterface
 		}
 	}
 
-	workerPool := NewWorkerPool(workerCount, transactionRepo, settlementRepo, jobRepo)
+	aging := 10 * time.Minute
+	if cfg.WORKERS.PriorityAging > 0 {
+		aging = cfg.WORKERS.PriorityAging
+	}
+
+	stallTimeout := 15 * time.Minute
+	if cfg.WORKERS.StallTimeout > 0 {
+		stallTimeout = cfg.WORKERS.StallTimeout
+	}
+
+	timeoutRequeues := 0
+	if cfg.WORKERS.TimeoutRequeues > 0 {
+		timeoutRequeues = cfg.WORKERS.TimeoutRequeues
+	}
+
+	progress := NewJobProgressBroker()
+	queue := NewJobQueue(parseTypeConcurrency(cfg.WORKERS.TypeConcurrency), aging)
+	workerPool := NewWorkerPool(workerCount, queue, timeoutRequeues, transactionRepo, settlementRepo, jobRepo, outboxRepo, txManager, progress)
 
 	return &JobService{
 		jobRepo:         jobRepo,
 		transactionRepo: transactionRepo,
+		outboxRepo:      outboxRepo,
+		txManager:       txManager,
 		workerPool:      workerPool,
-		activeJobs:      make(map[uuid.UUID]chan bool),
+		progress:        progress,
+		typeTimeouts:    parseTypeTimeouts(cfg.WORKERS.TypeTimeout),
+		stallTimeout:    stallTimeout,
 	}
 }
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// queueDepthRepo serves GetQueueDepth from depths; any other method panics on the nil embedded interface.
+type queueDepthRepo struct {
+	repository.JobRepositoryInterface
+	depths []entity.JobQueueDepthEntity
+}
+
+func (r queueDepthRepo) GetQueueDepth(context.Context) ([]entity.JobQueueDepthEntity, error) {
+	return r.depths, nil
+}
+
+func TestGetQueueStatsAggregatesEveryType(t *testing.T) {
+	repo := queueDepthRepo{depths: []entity.JobQueueDepthEntity{
+		{Type: "SETTLEMENT", Priority: 9, Status: "QUEUED", Jobs: 1, RemainingRows: 100},
+		{Type: "EXPORT", Priority: 9, Status: "QUEUED", Jobs: 2, RemainingRows: 50},
+		{Type: "REPORT", Priority: 7, Status: "RUNNING", Jobs: 1, RemainingRows: 10},
+		{Type: "SETTLEMENT", Priority: 5, Status: "RUNNING", Jobs: 3, RemainingRows: 900},
+		{Type: "EXPORT", Priority: 5, Status: "QUEUED", Jobs: 4, RemainingRows: 40},
+		{Type: "SETTLEMENT", Priority: 5, Status: "QUEUED", Jobs: 5, RemainingRows: 500},
+		{Type: "REPORT", Priority: 1, Status: "QUEUED", Jobs: 6, RemainingRows: 60},
+	}}
+	j := &JobService{
+		jobRepo:    repo,
+		workerPool: &WorkerPool{queue: NewJobQueue(map[string]int{"SETTLEMENT": 2}, time.Minute)},
+	}
+
+	stats, err := j.GetQueueStats(context.Background())
+	if err != nil {
+		t.Fatalf("GetQueueStats: %v", err)
+	}
+
+	wantTypes := []entity.JobTypeDepthEntity{
+		{Type: "SETTLEMENT", Queued: 6, Running: 3, Limit: 2},
+		{Type: "EXPORT", Queued: 6},
+		{Type: "REPORT", Queued: 6, Running: 1},
+	}
+	if !reflect.DeepEqual(stats.Types, wantTypes) {
+		t.Errorf("types = %+v, want %+v", stats.Types, wantTypes)
+	}
+
+	wantPriorities := []entity.JobPriorityDepthEntity{
+		{Priority: 9, Queued: 3, QueuedRows: 150},
+		{Priority: 5, Queued: 9, QueuedRows: 540},
+		{Priority: 1, Queued: 6, QueuedRows: 60},
+	}
+	if !reflect.DeepEqual(stats.Priorities, wantPriorities) {
+		t.Errorf("priorities = %+v, want %+v", stats.Priorities, wantPriorities)
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// errJobTimedOut and errJobStalled are the causes a job's context is cancelled with when it runs past its deadline
+// or goes without progress for the stall timeout; wrapped with the details, they become the job's error message.
+var (
+	errJobTimedOut = errors.New("job timed out")
+	errJobStalled  = errors.New("job stalled")
+)
+
+// watchdogCounters count the timed-out jobs this replica has failed or queued again, by why they timed out.
+type watchdogCounters struct {
+	timedOut atomic.Int64
+	stalled  atomic.Int64
+	requeued atomic.Int64
+	failed   atomic.Int64
+}
+
+func (c *watchdogCounters) record(cause error, requeued bool) {
+	if errors.Is(cause, errJobStalled) {
+		c.stalled.Add(1)
+	} else {
+		c.timedOut.Add(1)
+	}
+
+	if requeued {
+		c.requeued.Add(1)
+	} else {
+		c.failed.Add(1)
+	}
+}
+
+func (c *watchdogCounters) stats() entity.JobWatchdogStatsEntity {
+	return entity.JobWatchdogStatsEntity{
+		TimedOut: c.timedOut.Load(),
+		Stalled:  c.stalled.Load(),
+		Requeued: c.requeued.Load(),
+		Failed:   c.failed.Load(),
+	}
+}
+
+// timeOut handles a RUNNING job stopped by cause. While the job has requeues left it is queued again here, to
+// resume from checkpoint or, when that is nil, from the checkpoint it already has; otherwise it fails with cause
+// as its reason. Nothing is recorded if the job is no longer RUNNING.
+func (w *WorkerPool) timeOut(ctx context.Context, job entity.SettlementJob, snapshot entity.JobEntity, cause error, checkpoint *string) {
+	if snapshot.Requeues < w.timeoutRequeues {
+		requeued, err := w.jobRepo.Requeue(ctx, job.ID, checkpoint, snapshot.Progress, snapshot.Processed)
+		if err != nil {
+			log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to requeue timed-out job")
+			return
+		}
+		if !requeued {
+			return
+		}
+
+		w.watchdog.record(cause, true)
+		log.Warn().Err(cause).Str("job_id", job.ID.String()).Int("requeues", snapshot.Requeues+1).Msg("Timed-out job requeued")
+
+		snapshot.Status = "QUEUED"
+		snapshot.Requeues++
+		snapshot.Throughput = 0
+		snapshot.DeadlineAt = nil
+		snapshot.EstimatedCompletionAt = nil
+		w.progress.Publish(entity.JobUpdateEntity{Event: entity.JobUpdateQueued, Job: snapshot})
+
+		job.Requeues++
+		job.StartedAt = snapshot.StartedAt
+		w.AddJob(job)
+		return
+	}
+
+	if w.markJobAsFailed(ctx, snapshot, cause.Error()) {
+		w.watchdog.record(cause, false)
+		log.Warn().Err(cause).Str("job_id", job.ID.String()).Msg("Timed-out job failed")
+	}
+}
+
+// RunWatchdog implements JobServiceInterface.
+// Every replica runs the watchdog. A job one of its own workers holds is stopped through its context and
+// handled by that worker. A job no worker here holds is left to the replica running it until it is overdue by a
+// further stall timeout, by when that replica would have stopped it were it still up.
+func (j *JobService) RunWatchdog(ctx context.Context) (int, error) {
+
+	now := time.Now()
+	jobs, err := j.jobRepo.GetOverdue(ctx, now, now.Add(-j.stallTimeout))
+	if err != nil {
+		log.Error().Err(err).Msg("[JobService-15] RunWatchdog: failed to get overdue jobs")
+		return 0, err
+	}
+
+	handled := 0
+	for _, job := range jobs {
+		var cause error
+		if job.DeadlineAt != nil && job.DeadlineAt.Before(now) {
+			cause = fmt.Errorf("%w: ran past its deadline of %s", errJobTimedOut, job.DeadlineAt.Format(time.RFC3339))
+		} else {
+			cause = fmt.Errorf("%w: no progress for %s", errJobStalled, j.stallTimeout)
+		}
+
+		if j.workerPool.StopJob(job.ID, cause) {
+			log.Warn().Err(cause).Str("job_id", job.ID.String()).Msg("[JobService-16] RunWatchdog: stopping job")
+			handled++
+			continue
+		}
+
+		if !j.abandoned(job, now) {
+			continue
+		}
+
+		settlementJob, err := j.settlementJobFor(job)
+		if err != nil {
+			log.Error().Err(err).Str("job_id", job.ID.String()).Msg("[JobService-17] RunWatchdog: failed to read job")
+			if j.workerPool.markJobAsFailed(ctx, job, fmt.Sprintf("%s: %s", cause, err)) {
+				j.workerPool.watchdog.record(cause, false)
+			}
+			handled++
+			continue
+		}
+
+		log.Warn().Err(cause).Str("job_id", job.ID.String()).Msg("[JobService-18] RunWatchdog: job not held by this replica")
+		j.workerPool.timeOut(ctx, settlementJob, job, cause, nil)
+		handled++
+	}
+
+	return handled, nil
+}
+
+// abandoned reports whether job, overdue and held by no worker of this replica, is overdue by a further stall
+// timeout. Jobs whose replica restarted before they recorded a heartbeat count from when they started.
+func (j *JobService) abandoned(job entity.JobEntity, now time.Time) bool {
+	if job.DeadlineAt != nil && job.DeadlineAt.Add(j.stallTimeout).Before(now) {
+		return true
+	}
+
+	lastSeen := job.HeartbeatAt
+	if lastSeen == nil {
+		lastSeen = job.StartedAt
+	}
+	return lastSeen == nil || lastSeen.Add(2*j.stallTimeout).Before(now)
+}
@@ -1,10 +1,16 @@
 package service
 
 import (
+	"backend-service/config"
+	"backend-service/internal/adapter/gateway"
 	"backend-service/internal/adapter/repository"
 	"backend-service/internal/core/domain/entity"
 	errs "backend-service/internal/core/domain/error"
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
@@ -14,11 +20,208 @@ import (
 type OrderServiceInterface interface {
 	CreateOrder(ctx context.Context, order entity.OrderEntity) (*entity.OrderEntity, error)
 	GetOrderByID(ctx context.Context, orderID uuid.UUID) (*entity.OrderEntity, error)
+	CancelOrder(ctx context.Context, orderID uuid.UUID, reason string) (*entity.OrderEntity, error)
+	ConfirmOrder(ctx context.Context, orderID uuid.UUID) (*entity.OrderEntity, error)
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+	ListOrders(ctx context.Context, filter entity.OrderFilterEntity) (*entity.OrderPageEntity, error)
 }
 
 type OrderService struct {
-	orderRepo   repository.OrderRepositoryInterface
-	productRepo repository.ProductRepositoryInterface
+	orderRepo       repository.OrderRepositoryInterface
+	productRepo     repository.ProductRepositoryInterface
+	promotionRepo   repository.PromotionRepositoryInterface
+	transactionRepo repository.TransactionRepositoryInterface
+	outboxRepo      repository.OutboxRepositoryInterface
+	txManager       repository.TxManagerInterface
+	paymentGateway  gateway.PaymentGatewayInterface
+	reservationTTL  time.Duration
+	pricing         *PricingPipeline
+	admission       AdmissionServiceInterface
+}
+
+// ListOrders implements OrderServiceInterface.
+func (o *OrderService) ListOrders(ctx context.Context, filter entity.OrderFilterEntity) (*entity.OrderPageEntity, error) {
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, errs.ErrInvalidDateRange
+	}
+
+	page, err := o.orderRepo.ListOrders(ctx, filter)
+	if err != nil {
+		log.Error().Err(err).Str("buyer_id", filter.BuyerID).Msg("failed to list orders")
+		return nil, err
+	}
+
+	return page, nil
+
+}
+
+// ReleaseExpiredReservations implements OrderServiceInterface.
+// Each batch is released in its own transaction so a long sweep does not hold row locks for its whole duration.
+func (o *OrderService) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+
+	const sweepBatchSize = 100
+
+	released := 0
+	for {
+		var orders []entity.OrderEntity
+		err := o.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			orders, err = o.orderRepo.GetExpiredPending(ctx, time.Now(), sweepBatchSize)
+			if err != nil {
+				return err
+			}
+
+			for _, order := range orders {
+				if err := o.orderRepo.UpdateStatus(ctx, order.ID, "PENDING", "EXPIRED"); err != nil {
+					return err
+				}
+
+				if err := o.restoreStock(ctx, order, "EXPIRY", "reservation expired"); err != nil {
+					return err
+				}
+
+				if err := o.releasePromotion(ctx, order); err != nil {
+					return err
+				}
+
+				if err := o.closeTransactions(ctx, order, "reservation expired"); err != nil {
+					return err
+				}
+
+				order.Status = "EXPIRED"
+				if err := recordOrderEvent(ctx, o.outboxRepo, entity.EventOrderExpired, order); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("failed to release expired reservations")
+			return released, err
+		}
+
+		for _, order := range orders {
+			o.cancelPaymentIntent(ctx, order)
+		}
+
+		batch := len(orders)
+		released += batch
+		if batch < sweepBatchSize {
+			break
+		}
+	}
+
+	if released > 0 {
+		log.Info().Int("released", released).Msg("Expired order reservations released")
+	}
+
+	return released, nil
+
+}
+
+// ConfirmOrder implements OrderServiceInterface.
+// It completes a pending order and is called once the order's payment is reported PAID.
+func (o *OrderService) ConfirmOrder(ctx context.Context, orderID uuid.UUID) (*entity.OrderEntity, error) {
+
+	var confirmed *entity.OrderEntity
+
+	err := o.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := o.orderRepo.GetOrderByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if order.Status == "EXPIRED" || (order.Status == "PENDING" && order.IsExpired(time.Now())) {
+			return errs.ErrOrderExpired
+		}
+
+		if order.Status != "PENDING" || !order.CanTransitionTo("COMPLETED") {
+			return errs.ErrOrderCannotBeConfirmed
+		}
+
+		if err := o.orderRepo.UpdateStatus(ctx, orderID, "PENDING", "COMPLETED"); err != nil {
+			if errors.Is(err, errs.ErrOrderStatusConflict) {
+				return errs.ErrOrderCannotBeConfirmed
+			}
+			return err
+		}
+
+		order.Status = "COMPLETED"
+		confirmed = order
+
+		return recordOrderEvent(ctx, o.outboxRepo, entity.EventOrderCompleted, *order)
+	})
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to confirm order")
+		return nil, err
+	}
+
+	return confirmed, nil
+
+}
+
+// CancelOrder implements OrderServiceInterface.
+func (o *OrderService) CancelOrder(ctx context.Context, orderID uuid.UUID, reason string) (*entity.OrderEntity, error) {
+
+	var cancelled *entity.OrderEntity
+	var previousStatus string
+
+	// Status change, stock restoration and transaction updates commit or roll back together.
+	err := o.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		order, err := o.orderRepo.GetOrderByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+
+		if order.Status == "CANCELLED" {
+			return errs.ErrOrderAlreadyCancelled
+		}
+
+		if !order.CanTransitionTo("CANCELLED") {
+			return errs.ErrOrderCannotBeCancelled
+		}
+
+		cancelledAt := time.Now()
+		if err := o.orderRepo.Cancel(ctx, orderID, order.Status, reason, cancelledAt); err != nil {
+			if errors.Is(err, errs.ErrOrderStatusConflict) {
+				return errs.ErrOrderAlreadyCancelled
+			}
+			return err
+		}
+
+		if err := o.restoreStock(ctx, *order, "CANCELLATION", reason); err != nil {
+			return err
+		}
+
+		if err := o.releasePromotion(ctx, *order); err != nil {
+			return err
+		}
+
+		if err := o.closeTransactions(ctx, *order, reason); err != nil {
+			return err
+		}
+
+		previousStatus = order.Status
+		order.Status = "CANCELLED"
+		order.CancelReason = &reason
+		order.CancelledAt = &cancelledAt
+		cancelled = order
+
+		return recordOrderEvent(ctx, o.outboxRepo, entity.EventOrderCancelled, *order)
+	})
+	if err != nil {
+		log.Error().Err(err).Str("order_id", orderID.String()).Msg("failed to cancel order")
+		return nil, err
+	}
+
+	if previousStatus == "PENDING" {
+		o.cancelPaymentIntent(ctx, *cancelled)
+	}
+
+	return cancelled, nil
+
 }
 
 // GetOrderByID implements OrderServiceInterface.
@@ -36,45 +239,338 @@ func (o *OrderService) GetOrderByID(ctx context.Context, orderID uuid.UUID) (*en
 // CreateOrder implements OrderServiceInterface.
 func (o *OrderService) CreateOrder(ctx context.Context, order entity.OrderEntity) (*entity.OrderEntity, error) {
 
-	product, err := o.productRepo.GetByID(ctx, order.ProductID)
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, errs.ErrProductNotFound
+	// With the waiting room enabled every order spends one admitted queue token, handed back if the order fails.
+	if o.admission != nil {
+		if order.QueueToken == nil {
+			return nil, errs.ErrQueueTokenRequired
 		}
-		return nil, err
+
+		if err := o.admission.Consume(ctx, *order.QueueToken, order.BuyerID); err != nil {
+			log.Error().Err(err).Str("buyer_id", order.BuyerID).Msg("failed to consume queue token")
+			return nil, err
+		}
+
+		created := false
+		defer func() {
+			if created {
+				return
+			}
+			if err := o.admission.Restore(context.WithoutCancel(ctx), *order.QueueToken); err != nil {
+				log.Error().Err(err).Str("buyer_id", order.BuyerID).Msg("failed to restore queue token")
+			}
+		}()
+
+		newOrder, err := o.createOrder(ctx, order)
+		created = err == nil
+		return newOrder, err
 	}
 
-	// Check stock and update atomically
-	err = o.productRepo.UpdateStock(ctx, order.ProductID, order.Quantity)
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, errs.ErrOutOfStock
+	return o.createOrder(ctx, order)
+
+}
+
+func (o *OrderService) createOrder(ctx context.Context, order entity.OrderEntity) (*entity.OrderEntity, error) {
+
+	items := mergeOrderItems(order.Items)
+
+	// Prices are snapshotted on the items so later price changes do not affect the order.
+	for i := range items {
+		product, err := o.productRepo.GetByID(ctx, items[i].ProductID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, fmt.Errorf("%w: product %d", errs.ErrProductNotFound, items[i].ProductID)
+			}
+			return nil, err
 		}
-		return nil, err
+
+		switch product.SaleState(time.Now()) {
+		case "UPCOMING":
+			return nil, fmt.Errorf("%w: product %d", errs.ErrSaleNotStarted, items[i].ProductID)
+		case "ENDED":
+			return nil, fmt.Errorf("%w: product %d", errs.ErrSaleEnded, items[i].ProductID)
+		}
+
+		// An order is priced in one currency, taken from its first product.
+		if i == 0 {
+			order.Currency = product.Price.Currency
+		} else if product.Price.Currency != order.Currency {
+			return nil, fmt.Errorf("%w: product %d is priced in %s, order is in %s", errs.ErrCurrencyMismatch, items[i].ProductID, product.Price.Currency, order.Currency)
+		}
+
+		items[i].UnitPrice = product.Price
+		items[i].Total = product.Price.Multiply(int64(items[i].Quantity))
+		items[i].Product = product
 	}
 
+	// Stock is reserved now and released by the sweeper unless the order is paid before expiresAt.
+	expiresAt := time.Now().Add(o.reservationTTL)
 	newOrder := &entity.OrderEntity{
-		ProductID:  order.ProductID,
-		BuyerID:    order.BuyerID,
-		Quantity:   order.Quantity,
-		TotalCents: product.PriceCents * order.Quantity,
-		Status:     "COMPLETED",
+		ID:        uuid.New(),
+		BuyerID:   order.BuyerID,
+		Currency:  order.Currency,
+		Status:    "PENDING",
+		ExpiresAt: &expiresAt,
+		Items:     items,
+	}
+
+	var promotion *entity.PromotionEntity
+	if order.PromoCode != "" {
+		var err error
+		promotion, err = o.promotionRepo.GetByCode(ctx, normalizePromoCode(order.PromoCode))
+		if err != nil {
+			return nil, err
+		}
+
+		if !promotion.IsActive(time.Now()) {
+			return nil, errs.ErrPromotionNotActive
+		}
+
+		newOrder.PromotionID = &promotion.ID
+	}
+
+	if err := o.pricing.Price(newOrder, promotion); err != nil {
+		return nil, err
+	}
+	if promotion != nil && newOrder.Discount.IsZero() {
+		return nil, errs.ErrPromotionNotApplicable
+	}
+
+	// The buyer pays the whole total through one intent; the order is completed when the gateway reports it PAID.
+	intent, err := o.paymentGateway.CreateIntent(ctx, newOrder.ID, newOrder.Total)
+	if err != nil {
+		log.Error().Err(err).Str("buyer_id", order.BuyerID).Msg("failed to create payment intent")
+		return nil, err
 	}
+	newOrder.PaymentIntentID = &intent.ID
 
-	orderID, err := o.orderRepo.Create(ctx, *newOrder)
+	transactions, err := o.merchantTransactions(*newOrder)
 	if err != nil {
+		o.cancelPaymentIntent(ctx, *newOrder)
 		return nil, err
 	}
 
-	newOrder.ID = orderID
+	// Stock decrements, ledger entries, order insert and pending transactions commit or roll back together.
+	err = o.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// items are sorted by product ID, so concurrent orders lock stock rows in the same order and cannot deadlock.
+		for _, item := range items {
+			err := o.updateStock(ctx, entity.InventoryMovementEntity{
+				ProductID: item.ProductID,
+				OrderID:   &newOrder.ID,
+				Type:      "ORDER",
+				Quantity:  -item.Quantity,
+			})
+			if err != nil {
+				if err == gorm.ErrRecordNotFound {
+					return fmt.Errorf("%w: product %d", errs.ErrOutOfStock, item.ProductID)
+				}
+				return err
+			}
+
+			// UpdateStock holds the product row lock until commit, so concurrent orders for this product
+			// are serialized here and the sum below cannot miss another in-flight order.
+			if limit := item.Product.MaxPerBuyer; limit != nil {
+				held, err := o.orderRepo.SumBuyerQuantity(ctx, order.BuyerID, item.ProductID)
+				if err != nil {
+					return err
+				}
+
+				if held+item.Quantity > *limit {
+					return fmt.Errorf("%w: product %d allows %d per buyer", errs.ErrPurchaseLimitExceeded, item.ProductID, *limit)
+				}
+			}
+		}
+
+		if _, err := o.orderRepo.Create(ctx, *newOrder); err != nil {
+			return err
+		}
+
+		if err := o.transactionRepo.CreateBatch(ctx, transactions); err != nil {
+			return err
+		}
+
+		if err := recordOrderEvent(ctx, o.outboxRepo, entity.EventOrderCreated, *newOrder); err != nil {
+			return err
+		}
+
+		if promotion == nil {
+			return nil
+		}
+
+		// The redemption is written in the same transaction, so a failed order never spends a use.
+		err := o.promotionRepo.Redeem(ctx, entity.PromotionRedemptionEntity{
+			PromotionID: promotion.ID,
+			OrderID:     newOrder.ID,
+			BuyerID:     order.BuyerID,
+			Discount:    newOrder.Discount,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Redeem holds the promotion row lock, so the count includes every other in-flight redemption of this code.
+		if limit := promotion.MaxUsesPerBuyer; limit != nil {
+			used, err := o.promotionRepo.CountBuyerRedemptions(ctx, promotion.ID, order.BuyerID)
+			if err != nil {
+				return err
+			}
+
+			if used > int64(*limit) {
+				return errs.ErrPromotionBuyerLimitReached
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Str("buyer_id", order.BuyerID).Msg("failed to create order")
+		o.cancelPaymentIntent(ctx, *newOrder)
+		return nil, err
+	}
 
 	return newOrder, nil
 
 }
 
-func NewOrderService(orderRepo repository.OrderRepositoryInterface, productRepo repository.ProductRepositoryInterface) OrderServiceInterface {
+// merchantTransactions splits a priced order into one PENDING transaction per merchant. Each carries what the
+// buyer pays for that merchant's lines, net of discount and including exclusive tax; the platform fee stays out.
+func (o *OrderService) merchantTransactions(order entity.OrderEntity) ([]entity.TransactionEntity, error) {
+
+	amounts := make(map[string]entity.Money)
+	merchants := make([]string, 0)
+	for _, item := range order.Items {
+		share, err := item.Total.Sub(item.Discount)
+		if err != nil {
+			return nil, err
+		}
+		if !order.TaxInclusive {
+			if share, err = share.Add(item.Tax); err != nil {
+				return nil, err
+			}
+		}
+
+		merchantID := item.Product.MerchantID
+		current, ok := amounts[merchantID]
+		if !ok {
+			merchants = append(merchants, merchantID)
+			current = entity.NewMoney(0, order.Currency)
+		}
+		if amounts[merchantID], err = current.Add(share); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(merchants)
+
+	transactions := make([]entity.TransactionEntity, len(merchants))
+	for i, merchantID := range merchants {
+		transactions[i] = entity.TransactionEntity{
+			ID:              uuid.New(),
+			MerchantID:      merchantID,
+			OrderID:         &order.ID,
+			PaymentIntentID: order.PaymentIntentID,
+			Amount:          amounts[merchantID],
+			Fee:             o.pricing.MerchantFee(amounts[merchantID]),
+			Status:          "PENDING",
+		}
+	}
+
+	return transactions, nil
+
+}
+
+// closeTransactions settles the transactions of an order that is being cancelled or expired: payments still
+// pending are cancelled and payments already captured are refunded.
+func (o *OrderService) closeTransactions(ctx context.Context, order entity.OrderEntity, reason string) error {
+	if _, err := o.transactionRepo.UpdateStatusByOrder(ctx, order.ID, "PENDING", "CANCELLED", &reason); err != nil {
+		return err
+	}
+	_, err := o.transactionRepo.UpdateStatusByOrder(ctx, order.ID, "PAID", "REFUNDED", &reason)
+	return err
+}
+
+// cancelPaymentIntent stops the gateway from collecting payment for an order that will not be completed.
+// It is best effort: a result that still arrives finds the transactions closed and is refunded.
+func (o *OrderService) cancelPaymentIntent(ctx context.Context, order entity.OrderEntity) {
+	if order.PaymentIntentID == nil {
+		return
+	}
+
+	err := o.paymentGateway.CancelIntent(context.WithoutCancel(ctx), *order.PaymentIntentID)
+	if err != nil && !errors.Is(err, errs.ErrPaymentIntentNotFound) && !errors.Is(err, errs.ErrPaymentIntentAlreadySettled) {
+		log.Error().Err(err).Str("order_id", order.ID.String()).Msg("failed to cancel payment intent")
+	}
+}
+
+// releasePromotion gives back the promotion use held by order, if any.
+func (o *OrderService) releasePromotion(ctx context.Context, order entity.OrderEntity) error {
+	if order.PromotionID == nil {
+		return nil
+	}
+	return o.promotionRepo.Release(ctx, order.ID)
+}
+
+// restoreStock returns every item of order to stock, in product ID order.
+func (o *OrderService) restoreStock(ctx context.Context, order entity.OrderEntity, movementType string, reason string) error {
+	for _, item := range mergeOrderItems(order.Items) {
+		err := o.updateStock(ctx, entity.InventoryMovementEntity{
+			ProductID: item.ProductID,
+			OrderID:   &order.ID,
+			Type:      movementType,
+			Quantity:  item.Quantity,
+			Reason:    &reason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateStock applies a stock movement and records its stock.changed event in the same transaction.
+func (o *OrderService) updateStock(ctx context.Context, movement entity.InventoryMovementEntity) error {
+	recorded, err := o.productRepo.UpdateStock(ctx, movement)
+	if err != nil {
+		return err
+	}
+	return recordStockEvent(ctx, o.outboxRepo, *recorded)
+}
+
+// mergeOrderItems combines lines for the same product and sorts them by product ID.
+func mergeOrderItems(items []entity.OrderItemEntity) []entity.OrderItemEntity {
+	quantities := make(map[uint]int, len(items))
+	for _, item := range items {
+		quantities[item.ProductID] += item.Quantity
+	}
+
+	merged := make([]entity.OrderItemEntity, 0, len(quantities))
+	for productID, quantity := range quantities {
+		merged = append(merged, entity.OrderItemEntity{ProductID: productID, Quantity: quantity})
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ProductID < merged[j].ProductID
+	})
+
+	return merged
+}
+
+// NewOrderService builds the order service. admission may be nil when the waiting room is disabled.
+func NewOrderService(cfg *config.Config, orderRepo repository.OrderRepositoryInterface, productRepo repository.ProductRepositoryInterface, promotionRepo repository.PromotionRepositoryInterface, transactionRepo repository.TransactionRepositoryInterface, outboxRepo repository.OutboxRepositoryInterface, txManager repository.TxManagerInterface, paymentGateway gateway.PaymentGatewayInterface, admission AdmissionServiceInterface) OrderServiceInterface {
+
+	reservationTTL := 15 * time.Minute
+	if cfg.Orders.ReservationTTL > 0 {
+		reservationTTL = cfg.Orders.ReservationTTL
+	}
+
 	return &OrderService{
-		orderRepo:   orderRepo,
-		productRepo: productRepo,
+		orderRepo:       orderRepo,
+		productRepo:     productRepo,
+		promotionRepo:   promotionRepo,
+		transactionRepo: transactionRepo,
+		outboxRepo:      outboxRepo,
+		txManager:       txManager,
+		paymentGateway:  paymentGateway,
+		reservationTTL:  reservationTTL,
+		pricing:         NewPricingPipeline(cfg),
+		admission:       admission,
 	}
 }
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"backend-service/config"
+	"backend-service/internal/adapter/gateway"
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"backend-service/internal/core/domain/model"
+	"backend-service/internal/testdb"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var errInjected = errors.New("injected fault")
+
+// stubPaymentGateway opens an intent per order and records the ones cancelled.
+type stubPaymentGateway struct {
+	gateway.PaymentGatewayInterface
+	mu        sync.Mutex
+	cancelled []string
+}
+
+func (g *stubPaymentGateway) CreateIntent(_ context.Context, orderID uuid.UUID, amount entity.Money) (*entity.PaymentIntentEntity, error) {
+	return &entity.PaymentIntentEntity{ID: "pi_" + orderID.String(), OrderID: orderID, Amount: amount, Status: "PENDING"}, nil
+}
+
+func (g *stubPaymentGateway) CancelIntent(_ context.Context, paymentIntentID string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cancelled = append(g.cancelled, paymentIntentID)
+	return nil
+}
+
+// stockCountingProductRepo counts the stock updates that went through.
+type stockCountingProductRepo struct {
+	repository.ProductRepositoryInterface
+	updates atomic.Int64
+}
+
+func (p *stockCountingProductRepo) UpdateStock(ctx context.Context, movement entity.InventoryMovementEntity) (*entity.InventoryMovementEntity, error) {
+	recorded, err := p.ProductRepositoryInterface.UpdateStock(ctx, movement)
+	if err == nil {
+		p.updates.Add(1)
+	}
+	return recorded, err
+}
+
+type failingOrderRepo struct {
+	repository.OrderRepositoryInterface
+}
+
+func (failingOrderRepo) Create(context.Context, entity.OrderEntity) (uuid.UUID, error) {
+	return uuid.Nil, errInjected
+}
+
+type failingTransactionRepo struct {
+	repository.TransactionRepositoryInterface
+}
+
+func (failingTransactionRepo) CreateBatch(context.Context, []entity.TransactionEntity) error {
+	return errInjected
+}
+
+// failingOutboxRepo fails to record order.created; the stock.changed events before it go through.
+type failingOutboxRepo struct {
+	repository.OutboxRepositoryInterface
+}
+
+func (f failingOutboxRepo) Add(ctx context.Context, event entity.OutboxEventEntity) error {
+	if event.EventType == entity.EventOrderCreated {
+		return errInjected
+	}
+	return f.OutboxRepositoryInterface.Add(ctx, event)
+}
+
+type orderTest struct {
+	db        *gorm.DB
+	products  *stockCountingProductRepo
+	orders    repository.OrderRepositoryInterface
+	txs       repository.TransactionRepositoryInterface
+	outbox    repository.OutboxRepositoryInterface
+	payments  *stubPaymentGateway
+	txManager repository.TxManagerInterface
+}
+
+func newOrderTest(t *testing.T) *orderTest {
+	t.Helper()
+
+	db := testdb.Open(t)
+	return &orderTest{
+		db:        db,
+		products:  &stockCountingProductRepo{ProductRepositoryInterface: repository.NewProductRepository(db)},
+		orders:    repository.NewOrderRepository(db),
+		txs:       repository.NewTransactionRepository(db),
+		outbox:    repository.NewOutboxRepository(db),
+		payments:  &stubPaymentGateway{},
+		txManager: repository.NewTxManager(db),
+	}
+}
+
+func (o *orderTest) service() OrderServiceInterface {
+	return NewOrderService(&config.Config{}, o.orders, o.products, repository.NewPromotionRepository(o.db), o.txs, o.outbox, o.txManager, o.payments, nil)
+}
+
+func (o *orderTest) seedProduct(t *testing.T, stock int, maxPerBuyer *int) uint {
+	t.Helper()
+
+	product := model.ProductModel{Name: "Kopi", MerchantID: "merchant_001", PriceCents: 1000000, Currency: "IDR", Stock: stock, MaxPerBuyer: maxPerBuyer}
+	if err := o.db.Create(&product).Error; err != nil {
+		t.Fatalf("seed product: %v", err)
+	}
+	return product.ID
+}
+
+func (o *orderTest) count(t *testing.T, table string) int64 {
+	t.Helper()
+
+	var n int64
+	if err := o.db.Table(table).Count(&n).Error; err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return n
+}
+
+func (o *orderTest) stock(t *testing.T, productID uint) int {
+	t.Helper()
+
+	var product model.ProductModel
+	if err := o.db.First(&product, productID).Error; err != nil {
+		t.Fatalf("read product: %v", err)
+	}
+	return product.Stock
+}
+
+// A write failing after the stock was decremented rolls the decrement back with the rest of the order, and the
+// payment intent opened for the order is cancelled.
+func TestCreateOrderRollsBackStockWhenLaterWriteFails(t *testing.T) {
+	tests := []struct {
+		name   string
+		inject func(o *orderTest)
+	}{
+		{"order insert", func(o *orderTest) { o.orders = failingOrderRepo{o.orders} }},
+		{"transaction batch", func(o *orderTest) { o.txs = failingTransactionRepo{o.txs} }},
+		{"outbox event", func(o *orderTest) { o.outbox = failingOutboxRepo{o.outbox} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOrderTest(t)
+			first := o.seedProduct(t, 10, nil)
+			second := o.seedProduct(t, 5, nil)
+			tt.inject(o)
+
+			_, err := o.service().CreateOrder(context.Background(), entity.OrderEntity{
+				BuyerID: "buyer_001",
+				Items:   []entity.OrderItemEntity{{ProductID: first, Quantity: 2}, {ProductID: second, Quantity: 1}},
+			})
+			if !errors.Is(err, errInjected) {
+				t.Fatalf("CreateOrder error = %v, want %v", err, errInjected)
+			}
+			if updates := o.products.updates.Load(); updates != 2 {
+				t.Fatalf("stock updated %d times before the fault, want 2", updates)
+			}
+
+			if stock := o.stock(t, first); stock != 10 {
+				t.Errorf("first product stock = %d, want 10", stock)
+			}
+			if stock := o.stock(t, second); stock != 5 {
+				t.Errorf("second product stock = %d, want 5", stock)
+			}
+			for _, table := range []string{"inventory_movements", "orders", "transactions", "outbox_events"} {
+				if n := o.count(t, table); n != 0 {
+					t.Errorf("%s has %d rows, want 0", table, n)
+				}
+			}
+			if len(o.payments.cancelled) != 1 {
+				t.Errorf("cancelled %d payment intents, want 1", len(o.payments.cancelled))
+			}
+		})
+	}
+}
+
+func TestCreateOrderCommitsStockWithOrder(t *testing.T) {
+	o := newOrderTest(t)
+	productID := o.seedProduct(t, 10, nil)
+
+	order, err := o.service().CreateOrder(context.Background(), entity.OrderEntity{
+		BuyerID: "buyer_001",
+		Items:   []entity.OrderItemEntity{{ProductID: productID, Quantity: 3}},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder: %v", err)
+	}
+
+	if stock := o.stock(t, productID); stock != 7 {
+		t.Errorf("stock = %d, want 7", stock)
+	}
+	if n := o.count(t, "inventory_movements"); n != 1 {
+		t.Errorf("inventory_movements has %d rows, want 1", n)
+	}
+	if _, err := o.orders.GetOrderByID(context.Background(), order.ID); err != nil {
+		t.Errorf("GetOrderByID: %v", err)
+	}
+	if !slices.Equal(o.payments.cancelled, nil) {
+		t.Errorf("cancelled payment intents %v, want none", o.payments.cancelled)
+	}
+}
+
+// Concurrent orders from one buyer never hold more of a product than its per-buyer limit: each waits on the
+// product row lock taken by the stock update, so its sum of held quantity includes every order committed before.
+func TestCreateOrderEnforcesPurchaseLimitUnderConcurrency(t *testing.T) {
+	const (
+		attempts = 12
+		quantity = 2
+		limit    = 5
+	)
+
+	o := newOrderTest(t)
+	maxPerBuyer := limit
+	productID := o.seedProduct(t, 100, &maxPerBuyer)
+	orders := o.service()
+
+	var (
+		start   = make(chan struct{})
+		wg      sync.WaitGroup
+		results = make([]error, attempts)
+	)
+	for i := range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, results[i] = orders.CreateOrder(context.Background(), entity.OrderEntity{
+				BuyerID: "buyer_001",
+				Items:   []entity.OrderItemEntity{{ProductID: productID, Quantity: quantity}},
+			})
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for i, err := range results {
+		switch {
+		case err == nil:
+			created++
+		case !errors.Is(err, errs.ErrPurchaseLimitExceeded):
+			t.Errorf("order %d: error = %v, want %v", i, err, errs.ErrPurchaseLimitExceeded)
+		}
+	}
+	if want := limit / quantity; created != want {
+		t.Errorf("created %d orders, want %d", created, want)
+	}
+
+	held, err := o.orders.SumBuyerQuantity(context.Background(), "buyer_001", productID)
+	if err != nil {
+		t.Fatalf("SumBuyerQuantity: %v", err)
+	}
+	if held > limit {
+		t.Errorf("buyer holds %d, over the limit of %d", held, limit)
+	}
+	if stock := o.stock(t, productID); stock != 100-held {
+		t.Errorf("stock = %d, want %d", stock, 100-held)
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"backend-service/config"
+	"backend-service/internal/adapter/eventsink"
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	outboxRetryBaseDelay = time.Second
+	outboxRetryMaxDelay  = 5 * time.Minute
+)
+
+type OutboxRelayInterface interface {
+	Relay(ctx context.Context) (int, error)
+}
+
+// OutboxRelay publishes committed outbox events to its sinks. Delivery is at least once: an event is marked
+// published only after every sink accepted it, and is retried with exponential backoff otherwise.
+type OutboxRelay struct {
+	outboxRepo repository.OutboxRepositoryInterface
+	txManager  repository.TxManagerInterface
+	sinks      []eventsink.EventSinkInterface
+	batchSize  int
+	lease      time.Duration
+}
+
+// Relay implements OutboxRelayInterface.
+// Each batch is claimed in a short transaction that leases its events, making them due only once the lease runs
+// out, so other relays skip them while the sinks are called outside any transaction. Publishing stops when the
+// lease runs out; events not published by then are left to whichever relay claims them next.
+func (o *OutboxRelay) Relay(ctx context.Context) (int, error) {
+
+	published := 0
+	for {
+		var (
+			events      []entity.OutboxEventEntity
+			leasedUntil = time.Now().Add(o.lease).Truncate(time.Microsecond)
+		)
+
+		err := o.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			if events, err = o.outboxRepo.GetDue(ctx, time.Now(), o.batchSize); err != nil {
+				return err
+			}
+
+			eventIDs := make([]uuid.UUID, len(events))
+			for i, event := range events {
+				eventIDs[i] = event.ID
+			}
+			return o.outboxRepo.Lease(ctx, eventIDs, leasedUntil)
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("[OutboxRelay-2] Relay: failed to claim outbox events")
+			return published, err
+		}
+
+		failed := 0
+		leaseCtx, cancel := context.WithDeadline(ctx, leasedUntil)
+		for _, event := range events {
+			if leaseCtx.Err() != nil {
+				break
+			}
+
+			var publishErr error
+			for _, sink := range o.sinks {
+				if err := sink.Publish(leaseCtx, event); err != nil {
+					publishErr = errors.Join(publishErr, fmt.Errorf("%s: %w", sink.Name(), err))
+				}
+			}
+
+			now := time.Now()
+			if publishErr == nil {
+				err = o.outboxRepo.MarkPublished(ctx, event.ID, now)
+			} else {
+				failed++
+				log.Warn().Err(publishErr).Str("event_id", event.ID.String()).Int("attempts", event.Attempts+1).Msg("[OutboxRelay-1] Relay: failed to publish event")
+				err = o.outboxRepo.MarkFailed(ctx, event.ID, publishErr.Error(), leasedUntil, now.Add(exponentialBackoff(outboxRetryBaseDelay, outboxRetryMaxDelay, event.Attempts+1)))
+			}
+			if err != nil {
+				cancel()
+				log.Error().Err(err).Str("event_id", event.ID.String()).Msg("[OutboxRelay-3] Relay: failed to record publish outcome")
+				return published, err
+			}
+			if publishErr == nil {
+				published++
+			}
+		}
+		expired := leaseCtx.Err() != nil
+		cancel()
+
+		// Failed events are not due again until their backoff passes, so a full batch of failures ends the run too.
+		if len(events) < o.batchSize || failed == len(events) || expired {
+			break
+		}
+	}
+
+	return published, nil
+
+}
+
+// exponentialBackoff returns the wait after the given number of failed attempts: base, doubling with every
+// further attempt, up to maxDelay.
+func exponentialBackoff(base time.Duration, maxDelay time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxDelay)
+}
+
+func NewOutboxRelay(cfg *config.Config, outboxRepo repository.OutboxRepositoryInterface, txManager repository.TxManagerInterface, sinks ...eventsink.EventSinkInterface) OutboxRelayInterface {
+
+	batchSize := 100
+	if cfg.Outbox.BatchSize > 0 {
+		batchSize = cfg.Outbox.BatchSize
+	}
+
+	lease := 2 * time.Minute
+	if cfg.Outbox.Lease > 0 {
+		lease = cfg.Outbox.Lease
+	}
+
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		txManager:  txManager,
+		sinks:      sinks,
+		batchSize:  batchSize,
+		lease:      lease,
+	}
+}
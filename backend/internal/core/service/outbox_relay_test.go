@@ -0,0 +1,252 @@
+package service
+
+import (
+	"backend-service/config"
+	"backend-service/internal/adapter/eventsink"
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	"backend-service/internal/testdb"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryOutboxRepo keeps events in memory, oldest first, with the lease rules of OutboxRepository.
+type memoryOutboxRepo struct {
+	repository.OutboxRepositoryInterface
+	mu     sync.Mutex
+	events []*entity.OutboxEventEntity
+}
+
+func (r *memoryOutboxRepo) add(n int) {
+	for range n {
+		r.events = append(r.events, &entity.OutboxEventEntity{ID: uuid.New(), EventType: entity.EventOrderCreated, NextAttemptAt: time.Now()})
+	}
+}
+
+func (r *memoryOutboxRepo) get(i int) entity.OutboxEventEntity {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.events[i]
+}
+
+func (r *memoryOutboxRepo) find(eventID uuid.UUID) *entity.OutboxEventEntity {
+	for _, event := range r.events {
+		if event.ID == eventID {
+			return event
+		}
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepo) GetDue(_ context.Context, now time.Time, limit int) ([]entity.OutboxEventEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []entity.OutboxEventEntity
+	for _, event := range r.events {
+		if event.PublishedAt == nil && !event.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, *event)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryOutboxRepo) Lease(_ context.Context, eventIDs []uuid.UUID, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range eventIDs {
+		r.find(id).NextAttemptAt = until
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepo) MarkPublished(_ context.Context, eventID uuid.UUID, publishedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event := r.find(eventID); event.PublishedAt == nil {
+		event.Attempts++
+		event.PublishedAt = &publishedAt
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepo) MarkFailed(_ context.Context, eventID uuid.UUID, lastError string, leasedUntil time.Time, nextAttemptAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event := r.find(eventID); event.PublishedAt == nil && event.NextAttemptAt.Equal(leasedUntil) {
+		event.Attempts++
+		event.LastError = &lastError
+		event.NextAttemptAt = nextAttemptAt
+	}
+	return nil
+}
+
+// checkingSink records the events it is given, checks they are leased and published outside a transaction,
+// and takes delay to publish each.
+type checkingSink struct {
+	t         *testing.T
+	tx        *recordingTx
+	repo      *memoryOutboxRepo
+	delay     time.Duration
+	published []uuid.UUID
+}
+
+func (s *checkingSink) Name() string {
+	return "checking"
+}
+
+func (s *checkingSink) Publish(ctx context.Context, event entity.OutboxEventEntity) error {
+	if s.tx.open.Load() {
+		s.t.Error("event published inside a transaction")
+	}
+
+	s.repo.mu.Lock()
+	leased := s.repo.find(event.ID).NextAttemptAt.After(time.Now())
+	s.repo.mu.Unlock()
+	if !leased {
+		s.t.Error("event published without being leased")
+	}
+
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	s.published = append(s.published, event.ID)
+	return nil
+}
+
+func TestRelayPublishesLeasedEventsOutsideTransaction(t *testing.T) {
+	repo := &memoryOutboxRepo{}
+	repo.add(5)
+	tx := &recordingTx{}
+	sink := &checkingSink{t: t, tx: tx, repo: repo}
+	o := &OutboxRelay{outboxRepo: repo, txManager: tx, sinks: []eventsink.EventSinkInterface{sink}, batchSize: 2, lease: time.Minute}
+
+	published, err := o.Relay(context.Background())
+	if err != nil {
+		t.Fatalf("Relay: %v", err)
+	}
+	if published != 5 {
+		t.Errorf("published %d events, want 5", published)
+	}
+
+	want := make([]uuid.UUID, 5)
+	for i := range want {
+		want[i] = repo.get(i).ID
+		if repo.get(i).PublishedAt == nil {
+			t.Errorf("event %d not marked published", i)
+		}
+	}
+	if !slices.Equal(sink.published, want) {
+		t.Errorf("published %v, want %v in order", sink.published, want)
+	}
+}
+
+// Events a relay has not reached when its lease runs out are left for the next claim, with no attempt spent.
+func TestRelayStopsWhenLeaseRunsOut(t *testing.T) {
+	repo := &memoryOutboxRepo{}
+	repo.add(3)
+	tx := &recordingTx{}
+	sink := &checkingSink{t: t, tx: tx, repo: repo, delay: 60 * time.Millisecond}
+	o := &OutboxRelay{outboxRepo: repo, txManager: tx, sinks: []eventsink.EventSinkInterface{sink}, batchSize: 3, lease: 100 * time.Millisecond}
+
+	published, err := o.Relay(context.Background())
+	if err != nil {
+		t.Fatalf("Relay: %v", err)
+	}
+	if published != 1 {
+		t.Fatalf("published %d events, want 1", published)
+	}
+
+	// The second event was cut off by the lease and failed; the third was never tried.
+	if second := repo.get(1); second.Attempts != 1 || second.LastError == nil {
+		t.Errorf("second event has %d attempts, want its cut-off attempt recorded", second.Attempts)
+	}
+	third := repo.get(2)
+	if third.Attempts != 0 || third.PublishedAt != nil {
+		t.Errorf("third event has %d attempts, want none", third.Attempts)
+	}
+	if wait := time.Until(third.NextAttemptAt); wait > 100*time.Millisecond {
+		t.Errorf("third event due in %s, want once the lease has run out", wait)
+	}
+}
+
+// The memory bus sees an order.created or job.completed event once the change it belongs to commits, and never
+// sees one whose change rolled back.
+func TestMemoryBusReceivesOnlyCommittedEvents(t *testing.T) {
+	db := testdb.Open(t)
+	txManager := repository.NewTxManager(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	bus := eventsink.NewMemoryBus()
+	relay := NewOutboxRelay(&config.Config{}, outboxRepo, txManager, bus)
+	ctx := context.Background()
+
+	received, unsubscribe := bus.Subscribe(10)
+	defer unsubscribe()
+
+	errRollback := errors.New("rolled back")
+	err := txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := recordOrderEvent(ctx, outboxRepo, entity.EventOrderCreated, entity.OrderEntity{ID: uuid.New(), Status: "PENDING"}); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("rolled-back transaction: %v", err)
+	}
+
+	order := entity.OrderEntity{ID: uuid.New(), Status: "PENDING"}
+	job := entity.JobEntity{ID: uuid.New(), Type: "SETTLEMENT", Status: "COMPLETED"}
+	err = txManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := recordOrderEvent(txCtx, outboxRepo, entity.EventOrderCreated, order); err != nil {
+			return err
+		}
+		if err := recordJobEvent(txCtx, outboxRepo, entity.EventJobCompleted, job); err != nil {
+			return err
+		}
+
+		// A relay running on another connection before the commit has nothing to publish.
+		published, err := relay.Relay(ctx)
+		if err != nil {
+			return err
+		}
+		if published != 0 || len(received) != 0 {
+			t.Errorf("published %d events before commit, want none", published)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("committed transaction: %v", err)
+	}
+
+	published, err := relay.Relay(ctx)
+	if err != nil {
+		t.Fatalf("Relay: %v", err)
+	}
+	if published != 2 {
+		t.Fatalf("published %d events after commit, want 2", published)
+	}
+
+	// Both events were written in one transaction, so they share a creation time and may come in either order.
+	got := make(map[string]string)
+	count := len(received)
+	for range count {
+		event := <-received
+		got[event.EventType] = event.AggregateID
+	}
+	want := map[string]string{entity.EventOrderCreated: order.ID.String(), entity.EventJobCompleted: job.ID.String()}
+	if count != 2 || !maps.Equal(got, want) {
+		t.Errorf("bus received %d events %v, want %v", count, got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"backend-service/internal/adapter/gateway"
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+type PaymentServiceInterface interface {
+	HandlePaymentResult(ctx context.Context, result entity.PaymentResultEntity) error
+	SimulatePayment(ctx context.Context, paymentIntentID string, status string, reason *string) error
+}
+
+type PaymentService struct {
+	transactionRepo repository.TransactionRepositoryInterface
+	orderService    OrderServiceInterface
+	txManager       repository.TxManagerInterface
+	paymentGateway  gateway.PaymentGatewayInterface
+}
+
+// SimulatePayment implements PaymentServiceInterface.
+func (p *PaymentService) SimulatePayment(ctx context.Context, paymentIntentID string, status string, reason *string) error {
+
+	simulator, ok := p.paymentGateway.(gateway.PaymentSimulatorInterface)
+	if !ok {
+		return errs.ErrPaymentSimulatorDisabled
+	}
+
+	if err := simulator.Settle(ctx, paymentIntentID, status, reason); err != nil {
+		log.Error().Err(err).Str("payment_intent_id", paymentIntentID).Msg("[PaymentService-1] SimulatePayment: failed to settle payment")
+		return err
+	}
+
+	return nil
+
+}
+
+// HandlePaymentResult implements PaymentServiceInterface.
+// Results may be delivered more than once; only transactions still PENDING are changed, so a repeat is a no-op.
+func (p *PaymentService) HandlePaymentResult(ctx context.Context, result entity.PaymentResultEntity) error {
+
+	err := p.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// The rows stay locked until commit, so a result cannot race a cancellation of the same order.
+		transactions, err := p.transactionRepo.GetByPaymentIntent(ctx, result.PaymentIntentID)
+		if err != nil {
+			return err
+		}
+
+		// The intent is created just before its order commits, so an early result is retried by the gateway.
+		if len(transactions) == 0 || transactions[0].OrderID == nil {
+			return errs.ErrPaymentIntentNotFound
+		}
+		orderID := *transactions[0].OrderID
+
+		pending := false
+		for _, txn := range transactions {
+			if txn.Status == "PENDING" {
+				pending = true
+				break
+			}
+		}
+
+		switch result.Status {
+		case "PAID":
+			if !pending {
+				// Money captured for an order that was cancelled or expired in the meantime goes back to the buyer.
+				return p.refund(ctx, result, "CANCELLED", "order closed before payment was received")
+			}
+
+			if _, err := p.orderService.ConfirmOrder(ctx, orderID); err != nil {
+				if errors.Is(err, errs.ErrOrderExpired) || errors.Is(err, errs.ErrOrderCannotBeConfirmed) {
+					return p.refund(ctx, result, "PENDING", err.Error())
+				}
+				return err
+			}
+
+			_, err := p.transactionRepo.UpdateStatusByPaymentIntent(ctx, result.PaymentIntentID, "PENDING", "PAID", &result.OccurredAt, nil)
+			return err
+
+		case "FAILED":
+			if !pending {
+				return nil
+			}
+
+			if _, err := p.transactionRepo.UpdateStatusByPaymentIntent(ctx, result.PaymentIntentID, "PENDING", "FAILED", nil, result.FailureReason); err != nil {
+				return err
+			}
+
+			// A failed payment releases the reservation straight away instead of waiting for it to expire.
+			_, err := p.orderService.CancelOrder(ctx, orderID, "payment failed")
+			if err != nil && !errors.Is(err, errs.ErrOrderAlreadyCancelled) && !errors.Is(err, errs.ErrOrderCannotBeCancelled) {
+				return err
+			}
+			return nil
+
+		default:
+			return fmt.Errorf("unknown payment status %q", result.Status)
+		}
+	})
+	if err != nil {
+		log.Error().Err(err).Str("payment_intent_id", result.PaymentIntentID).Msg("[PaymentService-2] HandlePaymentResult: failed to apply payment result")
+		return err
+	}
+
+	return nil
+
+}
+
+// refund records a captured payment that cannot complete its order as REFUNDED.
+func (p *PaymentService) refund(ctx context.Context, result entity.PaymentResultEntity, fromStatus string, reason string) error {
+
+	refunded, err := p.transactionRepo.UpdateStatusByPaymentIntent(ctx, result.PaymentIntentID, fromStatus, "REFUNDED", &result.OccurredAt, &reason)
+	if err != nil {
+		return err
+	}
+
+	if refunded > 0 {
+		log.Warn().Str("payment_intent_id", result.PaymentIntentID).Str("reason", reason).Msg("[PaymentService-3] refund: payment refunded")
+	}
+
+	return nil
+
+}
+
+func NewPaymentService(transactionRepo repository.TransactionRepositoryInterface, orderService OrderServiceInterface, txManager repository.TxManagerInterface, paymentGateway gateway.PaymentGatewayInterface) PaymentServiceInterface {
+	return &PaymentService{
+		transactionRepo: transactionRepo,
+		orderService:    orderService,
+		txManager:       txManager,
+		paymentGateway:  paymentGateway,
+	}
+}
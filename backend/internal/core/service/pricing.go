@@ -0,0 +1,229 @@
+package service
+
+import (
+	"backend-service/config"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// defaultTaxRateBps is Indonesian PPN at 11%, used when TAX_RATES does not set a DEFAULT rate.
+const defaultTaxRateBps = 1100
+
+// defaultMerchantFeeBps is the commission kept from a merchant's share when PAYMENT_MERCHANT_FEE_BPS is unset.
+const defaultMerchantFeeBps = 300
+
+// PricingPipeline turns priced order items into the order's breakdown: discount, tax per line, platform fee
+// and the amount payable. Rates are in basis points and every amount is rounded to whole minor units.
+type PricingPipeline struct {
+	taxInclusive   bool
+	taxRates       map[string]int
+	defaultTaxRate int
+	rounding       string
+	fees           map[string]int64
+	feeBps         int64
+	merchantFeeBps int64
+}
+
+// Price fills the breakdown on order. Its items must already carry UnitPrice, Total and Product, all in
+// order.Currency; promotion may be nil. Amounts in another currency fail with ErrCurrencyMismatch, and an order
+// in a currency PLATFORM_FEE leaves out fails with ErrUnsupportedCurrency.
+func (p *PricingPipeline) Price(order *entity.OrderEntity, promotion *entity.PromotionEntity) error {
+
+	fee, err := p.fixedFee(order.Currency)
+	if err != nil {
+		return err
+	}
+
+	zero := entity.NewMoney(0, order.Currency)
+	order.Gross, order.Discount, order.Tax = zero, zero, zero
+	order.TaxInclusive = p.taxInclusive
+
+	var discounts []entity.Money
+	if promotion != nil {
+		var err error
+		if discounts, err = promotion.Allocate(order.Items, order.Currency); err != nil {
+			return err
+		}
+	}
+
+	net := zero
+	for i := range order.Items {
+		item := &order.Items[i]
+		item.Discount = zero
+		if discounts != nil {
+			item.Discount = discounts[i]
+		}
+
+		// Tax is charged on what the buyer actually pays for the line, after its share of the discount.
+		base, err := item.Total.Sub(item.Discount)
+		if err != nil {
+			return err
+		}
+		item.TaxRateBps = p.taxRate(item.Product)
+		item.Tax = entity.NewMoney(p.tax(base.Amount, item.TaxRateBps), order.Currency)
+
+		if order.Gross, err = order.Gross.Add(item.Total); err != nil {
+			return err
+		}
+		if order.Discount, err = order.Discount.Add(item.Discount); err != nil {
+			return err
+		}
+		if order.Tax, err = order.Tax.Add(item.Tax); err != nil {
+			return err
+		}
+		if net, err = net.Add(base); err != nil {
+			return err
+		}
+	}
+
+	// The platform fee is charged on the discounted amount and is not itself taxed.
+	order.Fee = entity.NewMoney(fee+p.round(net.Amount*p.feeBps, 10000), order.Currency)
+
+	total, err := net.Add(order.Fee)
+	if err != nil {
+		return err
+	}
+	if !p.taxInclusive {
+		if total, err = total.Add(order.Tax); err != nil {
+			return err
+		}
+	}
+	order.Total = total
+
+	return nil
+}
+
+// fixedFee returns the fixed part of the platform fee in minor units of currency. Without PLATFORM_FEE there is
+// none in any currency; with it, a currency it does not list has no fee to charge and cannot be ordered in.
+func (p *PricingPipeline) fixedFee(currency string) (int64, error) {
+	if len(p.fees) == 0 {
+		return 0, nil
+	}
+
+	fee, ok := p.fees[currency]
+	if !ok {
+		return 0, fmt.Errorf("%w: no platform fee is configured for %s", errs.ErrUnsupportedCurrency, currency)
+	}
+	return fee, nil
+}
+
+// MerchantFee returns the commission kept from amount, a merchant's share of an order, in amount's currency.
+func (p *PricingPipeline) MerchantFee(amount entity.Money) entity.Money {
+	return entity.NewMoney(p.round(amount.Amount*p.merchantFeeBps, 10000), amount.Currency)
+}
+
+func (p *PricingPipeline) taxRate(product *entity.ProductEntity) int {
+	if product != nil {
+		if rate, ok := p.taxRates[strings.ToUpper(product.Category)]; ok {
+			return rate
+		}
+	}
+	return p.defaultTaxRate
+}
+
+// tax returns the tax on amount. With inclusive pricing the tax is the part of amount above its net value.
+func (p *PricingPipeline) tax(amount int64, rateBps int) int64 {
+	if rateBps == 0 || amount == 0 {
+		return 0
+	}
+	if p.taxInclusive {
+		return amount - p.round(amount*10000, int64(10000+rateBps))
+	}
+	return p.round(amount*int64(rateBps), 10000)
+}
+
+// round divides a non-negative numerator by denominator using the configured rounding mode.
+func (p *PricingPipeline) round(numerator int64, denominator int64) int64 {
+	quotient, remainder := numerator/denominator, numerator%denominator
+	switch p.rounding {
+	case "DOWN":
+	case "UP":
+		if remainder > 0 {
+			quotient++
+		}
+	default:
+		if remainder*2 >= denominator {
+			quotient++
+		}
+	}
+	return quotient
+}
+
+// parseTaxRates reads a "CATEGORY=bps,..." list. Malformed entries are logged and skipped.
+func parseTaxRates(raw string) map[string]int {
+	rates := make(map[string]int)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		category, value, found := strings.Cut(entry, "=")
+		rate, err := strconv.Atoi(strings.TrimSpace(value))
+		if !found || err != nil || rate < 0 {
+			log.Warn().Str("entry", entry).Msg("ignoring malformed TAX_RATES entry")
+			continue
+		}
+
+		rates[strings.ToUpper(strings.TrimSpace(category))] = rate
+	}
+	return rates
+}
+
+// parsePlatformFees reads a "CURRENCY:minor units,..." list. Malformed entries and unsupported currencies are
+// logged and skipped.
+func parsePlatformFees(raw string) map[string]int64 {
+	fees := make(map[string]int64)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		currency, value, found := strings.Cut(entry, ":")
+		currency = strings.ToUpper(strings.TrimSpace(currency))
+		fee, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if !found || err != nil || fee < 0 || !entity.IsSupportedCurrency(currency) {
+			log.Warn().Str("entry", entry).Msg("ignoring malformed PLATFORM_FEE entry")
+			continue
+		}
+
+		fees[currency] = fee
+	}
+	return fees
+}
+
+func NewPricingPipeline(cfg *config.Config) *PricingPipeline {
+
+	taxRates := parseTaxRates(cfg.Pricing.TaxRates)
+
+	defaultTaxRate := defaultTaxRateBps
+	if rate, ok := taxRates["DEFAULT"]; ok {
+		defaultTaxRate = rate
+	}
+
+	rounding := "HALF_UP"
+	if cfg.Pricing.TaxRounding != "" {
+		rounding = strings.ToUpper(cfg.Pricing.TaxRounding)
+	}
+
+	merchantFeeBps := defaultMerchantFeeBps
+	if cfg.Payments.MerchantFeeBps > 0 {
+		merchantFeeBps = cfg.Payments.MerchantFeeBps
+	}
+
+	return &PricingPipeline{
+		taxInclusive:   cfg.Pricing.TaxInclusive,
+		taxRates:       taxRates,
+		defaultTaxRate: defaultTaxRate,
+		rounding:       rounding,
+		fees:           parsePlatformFees(cfg.Pricing.PlatformFee),
+		feeBps:         int64(max(cfg.Pricing.PlatformFeeBps, 0)),
+		merchantFeeBps: int64(merchantFeeBps),
+	}
+}
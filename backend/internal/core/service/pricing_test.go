@@ -0,0 +1,78 @@
+package service
+
+import (
+	"backend-service/config"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func pricedOrder(amount int64, currency string) *entity.OrderEntity {
+	price := entity.NewMoney(amount, currency)
+	return &entity.OrderEntity{
+		Currency: currency,
+		Items: []entity.OrderItemEntity{
+			{ProductID: 1, Quantity: 1, UnitPrice: price, Total: price, Product: &entity.ProductEntity{Price: price}},
+		},
+	}
+}
+
+func TestPriceChargesPlatformFeeInOrderCurrency(t *testing.T) {
+	p := NewPricingPipeline(&config.Config{Pricing: config.Pricing{
+		TaxRates:       "DEFAULT=0",
+		PlatformFee:    "IDR:500000,usd:50",
+		PlatformFeeBps: 100,
+	}})
+
+	tests := []struct {
+		currency string
+		amount   int64
+		fee      int64
+	}{
+		{"IDR", 10000000, 500000 + 100000},
+		{"USD", 10000, 50 + 100},
+	}
+	for _, tt := range tests {
+		order := pricedOrder(tt.amount, tt.currency)
+		if err := p.Price(order, nil); err != nil {
+			t.Fatalf("Price(%s): %v", tt.currency, err)
+		}
+		if want := entity.NewMoney(tt.fee, tt.currency); order.Fee != want {
+			t.Errorf("%s fee = %s, want %s", tt.currency, order.Fee, want)
+		}
+		if want := entity.NewMoney(tt.amount+tt.fee, tt.currency); order.Total != want {
+			t.Errorf("%s total = %s, want %s", tt.currency, order.Total, want)
+		}
+	}
+}
+
+func TestPriceRejectsCurrencyWithoutPlatformFee(t *testing.T) {
+	p := NewPricingPipeline(&config.Config{Pricing: config.Pricing{PlatformFee: "IDR:500000"}})
+
+	if err := p.Price(pricedOrder(10000, "USD"), nil); !errors.Is(err, errs.ErrUnsupportedCurrency) {
+		t.Errorf("Price error = %v, want %v", err, errs.ErrUnsupportedCurrency)
+	}
+}
+
+func TestPriceWithoutPlatformFeeChargesNoFixedFee(t *testing.T) {
+	p := NewPricingPipeline(&config.Config{Pricing: config.Pricing{TaxRates: "DEFAULT=0"}})
+
+	order := pricedOrder(10000, "USD")
+	if err := p.Price(order, nil); err != nil {
+		t.Fatalf("Price: %v", err)
+	}
+	if !order.Fee.IsZero() {
+		t.Errorf("fee = %s, want none", order.Fee)
+	}
+}
+
+func TestParsePlatformFeesSkipsMalformedEntries(t *testing.T) {
+	got := parsePlatformFees(" idr:500000, USD=50, EUR:-1, XXX:10, JPY:abc, KRW:100,")
+
+	want := map[string]int64{"IDR": 500000, "KRW": 100}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsePlatformFees = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ProductServiceInterface interface {
+	GetStockHistory(ctx context.Context, productID uint, limit int) (*entity.StockHistoryEntity, error)
+	AdjustStock(ctx context.Context, movement entity.InventoryMovementEntity) (*entity.InventoryMovementEntity, error)
+	SetPurchaseLimit(ctx context.Context, productID uint, maxPerBuyer *int) error
+	GetProduct(ctx context.Context, productID uint) (*entity.ProductEntity, error)
+	SetSaleWindow(ctx context.Context, productID uint, startsAt *time.Time, endsAt *time.Time) error
+}
+
+type ProductService struct {
+	productRepo           repository.ProductRepositoryInterface
+	inventoryMovementRepo repository.InventoryMovementRepositoryInterface
+	outboxRepo            repository.OutboxRepositoryInterface
+	txManager             repository.TxManagerInterface
+}
+
+// SetSaleWindow implements ProductServiceInterface.
+func (p *ProductService) SetSaleWindow(ctx context.Context, productID uint, startsAt *time.Time, endsAt *time.Time) error {
+
+	if startsAt != nil && endsAt != nil && !startsAt.Before(*endsAt) {
+		return errs.ErrInvalidSaleWindow
+	}
+
+	if err := p.productRepo.UpdateSaleWindow(ctx, productID, startsAt, endsAt); err != nil {
+		log.Error().Err(err).Uint("product_id", productID).Msg("[ProductService-5] SetSaleWindow: failed to update sale window")
+		if err == gorm.ErrRecordNotFound {
+			return errs.ErrProductNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetProduct implements ProductServiceInterface.
+func (p *ProductService) GetProduct(ctx context.Context, productID uint) (*entity.ProductEntity, error) {
+
+	product, err := p.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		log.Error().Err(err).Uint("product_id", productID).Msg("[ProductService-6] GetProduct: failed to get product")
+		if err == gorm.ErrRecordNotFound {
+			return nil, errs.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	return product, nil
+}
+
+// SetPurchaseLimit implements ProductServiceInterface.
+func (p *ProductService) SetPurchaseLimit(ctx context.Context, productID uint, maxPerBuyer *int) error {
+
+	if err := p.productRepo.UpdatePurchaseLimit(ctx, productID, maxPerBuyer); err != nil {
+		log.Error().Err(err).Uint("product_id", productID).Msg("[ProductService-4] SetPurchaseLimit: failed to update purchase limit")
+		if err == gorm.ErrRecordNotFound {
+			return errs.ErrProductNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// AdjustStock implements ProductServiceInterface.
+func (p *ProductService) AdjustStock(ctx context.Context, movement entity.InventoryMovementEntity) (*entity.InventoryMovementEntity, error) {
+
+	if _, err := p.productRepo.GetByID(ctx, movement.ProductID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errs.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	if movement.Type == "RESTOCK" && movement.Quantity <= 0 {
+		return nil, errs.ErrInvalidStockAdjustment
+	}
+
+	if movement.Quantity == 0 {
+		return nil, errs.ErrInvalidStockAdjustment
+	}
+
+	var recorded *entity.InventoryMovementEntity
+	err := p.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if recorded, err = p.productRepo.UpdateStock(ctx, movement); err != nil {
+			return err
+		}
+		return recordStockEvent(ctx, p.outboxRepo, *recorded)
+	})
+	if err != nil {
+		log.Error().Err(err).Uint("product_id", movement.ProductID).Msg("[ProductService-1] AdjustStock: failed to update stock")
+		if err == gorm.ErrRecordNotFound {
+			return nil, errs.ErrOutOfStock
+		}
+		return nil, err
+	}
+
+	return recorded, nil
+}
+
+// GetStockHistory implements ProductServiceInterface.
+func (p *ProductService) GetStockHistory(ctx context.Context, productID uint, limit int) (*entity.StockHistoryEntity, error) {
+
+	history, err := p.inventoryMovementRepo.GetStockHistory(ctx, productID, limit)
+	if err != nil {
+		log.Error().Err(err).Uint("product_id", productID).Msg("[ProductService-2] GetStockHistory: failed to get stock history")
+		return nil, err
+	}
+
+	if history.CurrentStock != history.LedgerStock {
+		log.Warn().
+			Uint("product_id", productID).
+			Int("current_stock", history.CurrentStock).
+			Int("ledger_stock", history.LedgerStock).
+			Msg("[ProductService-3] GetStockHistory: inventory ledger does not match current stock")
+	}
+
+	return history, nil
+}
+
+func NewProductService(productRepo repository.ProductRepositoryInterface, inventoryMovementRepo repository.InventoryMovementRepositoryInterface, outboxRepo repository.OutboxRepositoryInterface, txManager repository.TxManagerInterface) ProductServiceInterface {
+	return &ProductService{
+		productRepo:           productRepo,
+		inventoryMovementRepo: inventoryMovementRepo,
+		outboxRepo:            outboxRepo,
+		txManager:             txManager,
+	}
+}
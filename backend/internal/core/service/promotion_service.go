@@ -0,0 +1,94 @@
+package service
+
+import (
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type PromotionServiceInterface interface {
+	CreatePromotion(ctx context.Context, promotion entity.PromotionEntity) (*entity.PromotionEntity, error)
+	GetPromotion(ctx context.Context, code string) (*entity.PromotionEntity, error)
+}
+
+type PromotionService struct {
+	promotionRepo repository.PromotionRepositoryInterface
+	productRepo   repository.ProductRepositoryInterface
+}
+
+// GetPromotion implements PromotionServiceInterface.
+func (p *PromotionService) GetPromotion(ctx context.Context, code string) (*entity.PromotionEntity, error) {
+	return p.promotionRepo.GetByCode(ctx, normalizePromoCode(code))
+}
+
+// CreatePromotion implements PromotionServiceInterface.
+func (p *PromotionService) CreatePromotion(ctx context.Context, promotion entity.PromotionEntity) (*entity.PromotionEntity, error) {
+
+	promotion.Code = normalizePromoCode(promotion.Code)
+
+	if promotion.Type == "PERCENTAGE" && promotion.Value > 100 {
+		return nil, fmt.Errorf("%w: percentage cannot exceed 100", errs.ErrInvalidPromotion)
+	}
+
+	// A FIXED amount only makes sense in one currency; a percentage applies to any.
+	if promotion.Type == "FIXED" {
+		if promotion.Currency == nil || !entity.IsSupportedCurrency(*promotion.Currency) {
+			return nil, fmt.Errorf("%w: fixed promotions need a supported currency", errs.ErrInvalidPromotion)
+		}
+		currency := strings.ToUpper(*promotion.Currency)
+		promotion.Currency = &currency
+	} else {
+		promotion.Currency = nil
+	}
+
+	if promotion.StartsAt != nil && promotion.EndsAt != nil && !promotion.StartsAt.Before(*promotion.EndsAt) {
+		return nil, fmt.Errorf("%w: validity window must start before it ends", errs.ErrInvalidPromotion)
+	}
+
+	if promotion.ProductID != nil {
+		product, err := p.productRepo.GetByID(ctx, *promotion.ProductID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, errs.ErrProductNotFound
+			}
+			return nil, err
+		}
+
+		if promotion.Currency != nil && *promotion.Currency != product.Price.Currency {
+			return nil, fmt.Errorf("%w: product %d is priced in %s", errs.ErrCurrencyMismatch, product.ID, product.Price.Currency)
+		}
+	}
+
+	if _, err := p.promotionRepo.GetByCode(ctx, promotion.Code); err == nil {
+		return nil, errs.ErrPromotionCodeTaken
+	} else if !errors.Is(err, errs.ErrPromotionNotFound) {
+		return nil, err
+	}
+
+	created, err := p.promotionRepo.Create(ctx, promotion)
+	if err != nil {
+		log.Error().Err(err).Str("code", promotion.Code).Msg("[PromotionService-1] CreatePromotion: failed to create promotion")
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// normalizePromoCode makes codes case-insensitive.
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func NewPromotionService(promotionRepo repository.PromotionRepositoryInterface, productRepo repository.ProductRepositoryInterface) PromotionServiceInterface {
+	return &PromotionService{
+		promotionRepo: promotionRepo,
+		productRepo:   productRepo,
+	}
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"backend-service/config"
+	"backend-service/internal/adapter/eventsink"
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/adapter/webhook"
+	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const webhookRetryMaxDelay = time.Hour
+
+// WebhookServiceInterface manages subscriptions and delivers events to them. It is also the outbox sink that
+// turns each event into one delivery per matching subscription.
+type WebhookServiceInterface interface {
+	eventsink.EventSinkInterface
+	CreateSubscription(ctx context.Context, subscription entity.WebhookSubscriptionEntity) (*entity.WebhookSubscriptionEntity, error)
+	GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*entity.WebhookSubscriptionEntity, error)
+	ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscriptionEntity, error)
+	DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]entity.WebhookDeliveryEntity, error)
+	GetDelivery(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDeliveryEntity, error)
+	Redeliver(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDeliveryEntity, error)
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type WebhookService struct {
+	webhookRepo    repository.WebhookRepositoryInterface
+	txManager      repository.TxManagerInterface
+	client         webhook.ClientInterface
+	maxAttempts    int
+	retryBaseDelay time.Duration
+	batchSize      int
+	lease          time.Duration
+}
+
+// DeliverDue implements WebhookServiceInterface.
+// Each batch is claimed in a short transaction that leases its deliveries, making them due only once the lease
+// runs out, so other instances skip them while they are sent outside any transaction. Sending stops when the
+// lease runs out; deliveries not attempted by then are left to whichever instance claims them next.
+func (w *WebhookService) DeliverDue(ctx context.Context) (int, error) {
+
+	delivered := 0
+	for {
+		var (
+			deliveries  []entity.WebhookDeliveryEntity
+			leasedUntil = time.Now().Add(w.lease).Truncate(time.Microsecond)
+		)
+
+		err := w.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			if deliveries, err = w.webhookRepo.GetDueDeliveries(ctx, time.Now(), w.batchSize); err != nil {
+				return err
+			}
+
+			deliveryIDs := make([]uuid.UUID, len(deliveries))
+			for i, delivery := range deliveries {
+				deliveryIDs[i] = delivery.ID
+			}
+			return w.webhookRepo.LeaseDeliveries(ctx, deliveryIDs, leasedUntil)
+		})
+		if err != nil {
+			log.Error().Err(err).Msg("[WebhookService-1] DeliverDue: failed to claim due deliveries")
+			return delivered, err
+		}
+
+		leaseCtx, cancel := context.WithDeadline(ctx, leasedUntil)
+		for _, delivery := range deliveries {
+			if leaseCtx.Err() != nil {
+				break
+			}
+
+			attempt := w.attempt(leaseCtx, &delivery)
+			if err := w.webhookRepo.RecordAttempt(ctx, delivery, attempt, leasedUntil); err != nil {
+				cancel()
+				log.Error().Err(err).Str("delivery_id", delivery.ID.String()).Msg("[WebhookService-5] DeliverDue: failed to record attempt")
+				return delivered, err
+			}
+			if delivery.Status == "SUCCEEDED" {
+				delivered++
+			}
+		}
+		expired := leaseCtx.Err() != nil
+		cancel()
+
+		if len(deliveries) < w.batchSize || expired {
+			break
+		}
+	}
+
+	return delivered, nil
+
+}
+
+// attempt POSTs delivery once and moves it to its next state: SUCCEEDED, FAILED once the attempt budget is
+// spent, or PENDING with a backed-off next attempt.
+func (w *WebhookService) attempt(ctx context.Context, delivery *entity.WebhookDeliveryEntity) entity.WebhookDeliveryAttemptEntity {
+
+	now := time.Now()
+	delivery.Attempts++
+
+	var result webhook.Result
+	if delivery.Subscription == nil || !delivery.Subscription.Active {
+		result = webhook.Result{Err: fmt.Errorf("subscription is no longer active")}
+		delivery.Attempts = w.maxAttempts
+	} else {
+		result = w.client.Send(ctx, delivery.Subscription.URL, delivery.Subscription.Secret, map[string]string{
+			"X-Webhook-ID":      delivery.ID.String(),
+			"X-Webhook-Attempt": strconv.Itoa(delivery.Attempts),
+			"X-Event-ID":        delivery.EventID.String(),
+			"X-Event-Type":      delivery.EventType,
+		}, []byte(delivery.Payload))
+	}
+
+	attempt := entity.WebhookDeliveryAttemptEntity{
+		DeliveryID: delivery.ID,
+		Duration:   result.Duration,
+	}
+	delivery.LastStatusCode, delivery.LastError = nil, nil
+	if result.StatusCode != 0 {
+		attempt.StatusCode = &result.StatusCode
+		attempt.ResponseBody = &result.ResponseBody
+		delivery.LastStatusCode = &result.StatusCode
+	}
+
+	switch {
+	case result.Succeeded():
+		delivery.Status = "SUCCEEDED"
+		delivery.DeliveredAt = &now
+		return attempt
+	case result.Err != nil:
+		message := result.Err.Error()
+		attempt.Error, delivery.LastError = &message, &message
+	default:
+		message := fmt.Sprintf("receiver responded with status %d", result.StatusCode)
+		attempt.Error, delivery.LastError = &message, &message
+	}
+
+	if delivery.Attempts >= w.maxAttempts {
+		delivery.Status = "FAILED"
+		log.Warn().Str("delivery_id", delivery.ID.String()).Int("attempts", delivery.Attempts).Msg("[WebhookService-2] attempt: webhook delivery failed permanently")
+	} else {
+		delivery.Status = "PENDING"
+		delivery.NextAttemptAt = now.Add(exponentialBackoff(w.retryBaseDelay, webhookRetryMaxDelay, delivery.Attempts))
+	}
+
+	return attempt
+
+}
+
+// Redeliver implements WebhookServiceInterface.
+// The delivery is queued for the next run with a fresh attempt budget, whatever its current status.
+func (w *WebhookService) Redeliver(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDeliveryEntity, error) {
+
+	if err := w.webhookRepo.Redeliver(ctx, deliveryID, time.Now()); err != nil {
+		log.Error().Err(err).Str("delivery_id", deliveryID.String()).Msg("[WebhookService-3] Redeliver: failed to queue redelivery")
+		return nil, err
+	}
+
+	return w.webhookRepo.GetDelivery(ctx, deliveryID)
+
+}
+
+// GetDelivery implements WebhookServiceInterface.
+func (w *WebhookService) GetDelivery(ctx context.Context, deliveryID uuid.UUID) (*entity.WebhookDeliveryEntity, error) {
+	return w.webhookRepo.GetDelivery(ctx, deliveryID)
+}
+
+// ListDeliveries implements WebhookServiceInterface.
+func (w *WebhookService) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]entity.WebhookDeliveryEntity, error) {
+
+	if _, err := w.webhookRepo.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+
+	return w.webhookRepo.ListDeliveries(ctx, subscriptionID, limit)
+
+}
+
+// DeleteSubscription implements WebhookServiceInterface.
+// The subscription is deactivated rather than removed so its delivery log stays available.
+func (w *WebhookService) DeleteSubscription(ctx context.Context, subscriptionID uuid.UUID) error {
+	return w.webhookRepo.DeactivateSubscription(ctx, subscriptionID)
+}
+
+// ListSubscriptions implements WebhookServiceInterface.
+func (w *WebhookService) ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscriptionEntity, error) {
+	return w.webhookRepo.ListSubscriptions(ctx, false)
+}
+
+// GetSubscription implements WebhookServiceInterface.
+func (w *WebhookService) GetSubscription(ctx context.Context, subscriptionID uuid.UUID) (*entity.WebhookSubscriptionEntity, error) {
+	return w.webhookRepo.GetSubscription(ctx, subscriptionID)
+}
+
+// CreateSubscription implements WebhookServiceInterface.
+// A secret is generated when none is given; it is only returned here, so the caller must store it.
+func (w *WebhookService) CreateSubscription(ctx context.Context, subscription entity.WebhookSubscriptionEntity) (*entity.WebhookSubscriptionEntity, error) {
+
+	target, err := url.Parse(subscription.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http or https URL", errs.ErrInvalidWebhook)
+	}
+
+	eventTypes := make([]string, 0, len(subscription.EventTypes))
+	for _, eventType := range subscription.EventTypes {
+		if eventType != "*" && !slices.Contains(entity.WebhookEventTypes, eventType) {
+			return nil, fmt.Errorf("%w: unknown event type %q", errs.ErrInvalidWebhook, eventType)
+		}
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	subscription.EventTypes = eventTypes
+
+	if subscription.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		subscription.Secret = "whsec_" + hex.EncodeToString(secret)
+	}
+
+	created, err := w.webhookRepo.CreateSubscription(ctx, subscription)
+	if err != nil {
+		log.Error().Err(err).Msg("[WebhookService-4] CreateSubscription: failed to create subscription")
+		return nil, err
+	}
+
+	return created, nil
+
+}
+
+// Name implements eventsink.EventSinkInterface.
+func (w *WebhookService) Name() string {
+	return "webhooks"
+}
+
+// Publish implements eventsink.EventSinkInterface.
+// It only queues deliveries; DeliverDue sends them, so one slow receiver cannot hold up the outbox relay. An event
+// published again gets no second delivery to a subscription that already has one.
+func (w *WebhookService) Publish(ctx context.Context, event entity.OutboxEventEntity) error {
+
+	subscriptions, err := w.webhookRepo.ListSubscriptions(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	deliveries := make([]entity.WebhookDeliveryEntity, 0)
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(event.EventType) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = json.Marshal(eventsink.NewEnvelope(event)); err != nil {
+				return err
+			}
+		}
+
+		deliveries = append(deliveries, entity.WebhookDeliveryEntity{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.EventType,
+			Payload:        string(payload),
+			NextAttemptAt:  time.Now(),
+		})
+	}
+
+	return w.webhookRepo.CreateDeliveries(ctx, deliveries)
+
+}
+
+func NewWebhookService(cfg *config.Config, webhookRepo repository.WebhookRepositoryInterface, txManager repository.TxManagerInterface, client webhook.ClientInterface) WebhookServiceInterface {
+
+	maxAttempts := 8
+	if cfg.Webhooks.MaxAttempts > 0 {
+		maxAttempts = cfg.Webhooks.MaxAttempts
+	}
+
+	retryBaseDelay := 5 * time.Second
+	if cfg.Webhooks.RetryBaseDelay > 0 {
+		retryBaseDelay = cfg.Webhooks.RetryBaseDelay
+	}
+
+	lease := 2 * time.Minute
+	if cfg.Webhooks.Lease > 0 {
+		lease = cfg.Webhooks.Lease
+	}
+
+	return &WebhookService{
+		webhookRepo:    webhookRepo,
+		txManager:      txManager,
+		client:         client,
+		maxAttempts:    maxAttempts,
+		retryBaseDelay: retryBaseDelay,
+		batchSize:      20,
+		lease:          lease,
+	}
+}
@@ -0,0 +1,319 @@
+package service
+
+import (
+	"backend-service/internal/adapter/repository"
+	"backend-service/internal/adapter/webhook"
+	"backend-service/internal/core/domain/entity"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// recordingTx runs the unit of work without a database and reports whether one is in progress.
+type recordingTx struct {
+	open atomic.Bool
+}
+
+func (r *recordingTx) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	r.open.Store(true)
+	defer r.open.Store(false)
+	return fn(ctx)
+}
+
+// memoryWebhookRepo keeps deliveries to one subscription in memory, with the lease rules of WebhookRepository.
+type memoryWebhookRepo struct {
+	repository.WebhookRepositoryInterface
+	mu           sync.Mutex
+	subscription entity.WebhookSubscriptionEntity
+	deliveries   map[uuid.UUID]*entity.WebhookDeliveryEntity
+	attempts     []entity.WebhookDeliveryAttemptEntity
+}
+
+func newMemoryWebhookRepo(url string, secret string) *memoryWebhookRepo {
+	return &memoryWebhookRepo{
+		subscription: entity.WebhookSubscriptionEntity{ID: uuid.New(), URL: url, Secret: secret, EventTypes: []string{"*"}, Active: true},
+		deliveries:   make(map[uuid.UUID]*entity.WebhookDeliveryEntity),
+	}
+}
+
+func (r *memoryWebhookRepo) add(payload string) uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery := &entity.WebhookDeliveryEntity{
+		ID:             uuid.New(),
+		SubscriptionID: r.subscription.ID,
+		EventID:        uuid.New(),
+		EventType:      entity.EventOrderCreated,
+		Payload:        payload,
+		Status:         "PENDING",
+		NextAttemptAt:  time.Now(),
+	}
+	r.deliveries[delivery.ID] = delivery
+	return delivery.ID
+}
+
+func (r *memoryWebhookRepo) get(deliveryID uuid.UUID) entity.WebhookDeliveryEntity {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.deliveries[deliveryID]
+}
+
+// makeDue moves a delivery's next attempt into the past, as if its backoff had passed.
+func (r *memoryWebhookRepo) makeDue(deliveryID uuid.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[deliveryID].NextAttemptAt = time.Now().Add(-time.Second)
+}
+
+func (r *memoryWebhookRepo) GetDueDeliveries(_ context.Context, now time.Time, limit int) ([]entity.WebhookDeliveryEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []entity.WebhookDeliveryEntity
+	for _, delivery := range r.deliveries {
+		if delivery.Status == "PENDING" && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			d := *delivery
+			subscription := r.subscription
+			d.Subscription = &subscription
+			due = append(due, d)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryWebhookRepo) LeaseDeliveries(_ context.Context, deliveryIDs []uuid.UUID, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range deliveryIDs {
+		r.deliveries[id].NextAttemptAt = until
+	}
+	return nil
+}
+
+func (r *memoryWebhookRepo) RecordAttempt(_ context.Context, delivery entity.WebhookDeliveryEntity, attempt entity.WebhookDeliveryAttemptEntity, leasedUntil time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.attempts = append(r.attempts, attempt)
+	stored := r.deliveries[delivery.ID]
+	if !stored.NextAttemptAt.Equal(leasedUntil) {
+		return nil
+	}
+	delivery.Subscription = nil
+	*stored = delivery
+	return nil
+}
+
+func (r *memoryWebhookRepo) Redeliver(_ context.Context, deliveryID uuid.UUID, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delivery := r.deliveries[deliveryID]
+	delivery.Status, delivery.Attempts, delivery.NextAttemptAt = "PENDING", 0, now
+	return nil
+}
+
+func (r *memoryWebhookRepo) GetDelivery(_ context.Context, deliveryID uuid.UUID) (*entity.WebhookDeliveryEntity, error) {
+	delivery := r.get(deliveryID)
+	return &delivery, nil
+}
+
+// webhookReceiver answers with the queued status codes, then 200, and checks every request the way a
+// subscriber would.
+type webhookReceiver struct {
+	t        *testing.T
+	secret   string
+	tx       *recordingTx
+	repo     *memoryWebhookRepo
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	onSend   func()
+}
+
+func (w *webhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	if err := webhook.Verify(w.secret, req.Header.Get(webhook.SignatureHeader), body, time.Minute, time.Now()); err != nil {
+		w.t.Errorf("signature: %v", err)
+	}
+	if w.tx.open.Load() {
+		w.t.Error("webhook sent inside a transaction")
+	}
+	if id, err := uuid.Parse(req.Header.Get("X-Webhook-ID")); err == nil && !w.repo.get(id).NextAttemptAt.After(time.Now()) {
+		w.t.Error("webhook sent without its delivery being leased")
+	}
+	if w.onSend != nil {
+		w.onSend()
+	}
+
+	w.mu.Lock()
+	w.requests = append(w.requests, req)
+	status := http.StatusOK
+	if len(w.statuses) > 0 {
+		status, w.statuses = w.statuses[0], w.statuses[1:]
+	}
+	w.mu.Unlock()
+
+	rw.WriteHeader(status)
+}
+
+func (w *webhookReceiver) sent() []*http.Request {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.requests
+}
+
+func newWebhookTest(t *testing.T, statuses ...int) (*WebhookService, *memoryWebhookRepo, *webhookReceiver) {
+	t.Helper()
+
+	const secret = "whsec_test"
+	tx := &recordingTx{}
+	receiver := &webhookReceiver{t: t, secret: secret, tx: tx, statuses: statuses}
+	server := httptest.NewServer(receiver)
+	t.Cleanup(server.Close)
+
+	repo := newMemoryWebhookRepo(server.URL, secret)
+	receiver.repo = repo
+
+	w := &WebhookService{
+		webhookRepo:    repo,
+		txManager:      tx,
+		client:         webhook.NewClient(5 * time.Second),
+		maxAttempts:    3,
+		retryBaseDelay: time.Minute,
+		batchSize:      20,
+		lease:          time.Minute,
+	}
+	return w, repo, receiver
+}
+
+// assertRetryAfter checks a delivery is PENDING again, due delay after the attempt that failed.
+func assertRetryAfter(t *testing.T, delivery entity.WebhookDeliveryEntity, attempts int, delay time.Duration, attemptedBefore time.Time) {
+	t.Helper()
+
+	if delivery.Status != "PENDING" || delivery.Attempts != attempts {
+		t.Fatalf("delivery is %s after %d attempts, want PENDING after %d", delivery.Status, delivery.Attempts, attempts)
+	}
+	if wait := delivery.NextAttemptAt.Sub(attemptedBefore); wait < delay || wait > delay+5*time.Second {
+		t.Errorf("next attempt in %s, want %s", wait, delay)
+	}
+}
+
+func TestDeliverDueSignsRetriesAndRedelivers(t *testing.T) {
+	w, repo, receiver := newWebhookTest(t, http.StatusServiceUnavailable)
+	ctx := context.Background()
+	id := repo.add(`{"type":"order.created"}`)
+
+	before := time.Now()
+	if delivered, err := w.DeliverDue(ctx); err != nil || delivered != 0 {
+		t.Fatalf("first DeliverDue = %d, %v; want 0 delivered", delivered, err)
+	}
+	delivery := repo.get(id)
+	assertRetryAfter(t, delivery, 1, time.Minute, before)
+	if delivery.LastStatusCode == nil || *delivery.LastStatusCode != http.StatusServiceUnavailable {
+		t.Errorf("last status code = %v, want 503", delivery.LastStatusCode)
+	}
+
+	// Not due until the backoff has passed.
+	if _, err := w.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue during backoff: %v", err)
+	}
+	if sent := len(receiver.sent()); sent != 1 {
+		t.Fatalf("sent %d requests during backoff, want 1", sent)
+	}
+
+	repo.makeDue(id)
+	if delivered, err := w.DeliverDue(ctx); err != nil || delivered != 1 {
+		t.Fatalf("retry DeliverDue = %d, %v; want 1 delivered", delivered, err)
+	}
+	if delivery := repo.get(id); delivery.Status != "SUCCEEDED" || delivery.Attempts != 2 || delivery.DeliveredAt == nil {
+		t.Errorf("delivery is %s after %d attempts, want SUCCEEDED after 2", delivery.Status, delivery.Attempts)
+	}
+
+	if _, err := w.Redeliver(ctx, id); err != nil {
+		t.Fatalf("Redeliver: %v", err)
+	}
+	if delivered, err := w.DeliverDue(ctx); err != nil || delivered != 1 {
+		t.Fatalf("redelivery DeliverDue = %d, %v; want 1 delivered", delivered, err)
+	}
+
+	sent := receiver.sent()
+	if len(sent) != 3 {
+		t.Fatalf("sent %d requests, want 3", len(sent))
+	}
+	for i, want := range []string{"1", "2", "1"} {
+		if got := sent[i].Header.Get("X-Webhook-Attempt"); got != want {
+			t.Errorf("request %d: X-Webhook-Attempt = %s, want %s", i, got, want)
+		}
+		if got := sent[i].Header.Get("X-Webhook-ID"); got != id.String() {
+			t.Errorf("request %d: X-Webhook-ID = %s, want %s", i, got, id)
+		}
+	}
+	if len(repo.attempts) != 3 {
+		t.Errorf("logged %d attempts, want 3", len(repo.attempts))
+	}
+}
+
+func TestDeliverDueBacksOffUntilAttemptsRunOut(t *testing.T) {
+	w, repo, receiver := newWebhookTest(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	ctx := context.Background()
+	id := repo.add(`{}`)
+
+	for attempt, delay := range []time.Duration{time.Minute, 2 * time.Minute} {
+		before := time.Now()
+		if _, err := w.DeliverDue(ctx); err != nil {
+			t.Fatalf("DeliverDue: %v", err)
+		}
+		assertRetryAfter(t, repo.get(id), attempt+1, delay, before)
+		repo.makeDue(id)
+	}
+
+	if _, err := w.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	if delivery := repo.get(id); delivery.Status != "FAILED" || delivery.Attempts != 3 {
+		t.Errorf("delivery is %s after %d attempts, want FAILED after 3", delivery.Status, delivery.Attempts)
+	}
+
+	repo.makeDue(id)
+	if _, err := w.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	if sent := len(receiver.sent()); sent != 3 {
+		t.Errorf("sent %d requests, want 3", sent)
+	}
+}
+
+// A redelivery asked for while an attempt is in flight wins over that attempt's outcome.
+func TestDeliverDueKeepsRedeliveryQueuedDuringAttempt(t *testing.T) {
+	w, repo, receiver := newWebhookTest(t, http.StatusInternalServerError)
+	ctx := context.Background()
+	id := repo.add(`{}`)
+
+	receiver.onSend = func() {
+		receiver.onSend = nil
+		if err := repo.Redeliver(ctx, id, time.Now()); err != nil {
+			t.Errorf("Redeliver: %v", err)
+		}
+	}
+
+	if _, err := w.DeliverDue(ctx); err != nil {
+		t.Fatalf("DeliverDue: %v", err)
+	}
+	if delivery := repo.get(id); delivery.Status != "PENDING" || delivery.Attempts != 0 || delivery.NextAttemptAt.After(time.Now()) {
+		t.Errorf("delivery is %s after %d attempts, due %s; want PENDING, 0 attempts and due now", delivery.Status, delivery.Attempts, delivery.NextAttemptAt)
+	}
+	if len(repo.attempts) != 1 {
+		t.Errorf("logged %d attempts, want 1", len(repo.attempts))
+	}
+}
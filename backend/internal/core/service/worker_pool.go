@@ -3,38 +3,75 @@ package service
 import (
 	"backend-service/internal/adapter/repository"
 	"backend-service/internal/core/domain/entity"
+	errs "backend-service/internal/core/domain/error"
 	"context"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 )
 
+// errJobCancelled and errJobPaused are the causes a job's context is cancelled with, telling its worker what
+// to record once the query or write in flight has aborted.
+var (
+	errJobCancelled = errors.New("job cancelled")
+	errJobPaused    = errors.New("job paused")
+)
+
+// PeriodicTask is background work the pool runs on a fixed interval next to its queue workers.
+type PeriodicTask struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
 type WorkerPool struct {
-	jobQueue        chan entity.SettlementJob
+	queue           *JobQueue
+	periodicTasks   []PeriodicTask
 	workerCount     int
+	timeoutRequeues int
 	transactionRepo repository.TransactionRepositoryInterface
 	settlementRepo  repository.SettlementRepositoryInterface
 	jobRepo         repository.JobRepositoryInterface
+	outboxRepo      repository.OutboxRepositoryInterface
+	txManager       repository.TxManagerInterface
+	progress        *JobProgressBroker
+	watchdog        watchdogCounters
+	mu              sync.Mutex
+	// running holds the cancel function of the context each job taken by a worker runs under.
+	running map[uuid.UUID]context.CancelCauseFunc
 }
 
 func NewWorkerPool(
 	workerCount int,
+	queue *JobQueue,
+	timeoutRequeues int,
 	transactionRepo repository.TransactionRepositoryInterface,
 	settlementRepo repository.SettlementRepositoryInterface,
 	jobRepo repository.JobRepositoryInterface,
+	outboxRepo repository.OutboxRepositoryInterface,
+	txManager repository.TxManagerInterface,
+	progress *JobProgressBroker,
 ) *WorkerPool {
 	return &WorkerPool{
-		jobQueue:        make(chan entity.SettlementJob, 100),
+		queue:           queue,
 		workerCount:     workerCount,
+		timeoutRequeues: timeoutRequeues,
 		transactionRepo: transactionRepo,
 		settlementRepo:  settlementRepo,
 		jobRepo:         jobRepo,
+		outboxRepo:      outboxRepo,
+		txManager:       txManager,
+		progress:        progress,
+		running:         make(map[uuid.UUID]context.CancelCauseFunc),
 	}
 }
 
@@ -42,108 +79,252 @@ func (w *WorkerPool) Start(ctx context.Context) {
 	for i := 0; i < w.workerCount; i++ {
 		go w.worker(ctx, i)
 	}
+	for _, task := range w.periodicTasks {
+		go w.runPeriodic(ctx, task)
+	}
 	log.Info().Int("workers", w.workerCount).Msg("Settlement worker pool started")
 }
 
 func (w *WorkerPool) AddJob(job entity.SettlementJob) {
-	w.jobQueue <- job
+	w.queue.Push("SETTLEMENT", job.Priority, job)
 }
 
-func (w *WorkerPool) worker(ctx context.Context, workerID int) {
+// RemoveJob takes a job that has not started yet out of the queue, reporting whether it was still waiting.
+func (w *WorkerPool) RemoveJob(jobID uuid.UUID) bool {
+	return w.queue.Remove(jobID)
+}
+
+// StopJob cancels the context of a job a worker has taken, cause telling it why, and reports whether one had.
+func (w *WorkerPool) StopJob(jobID uuid.UUID, cause error) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stop, exists := w.running[jobID]
+	if exists {
+		stop(cause)
+	}
+	return exists
+}
+
+// HasJob reports whether a worker has taken the job.
+func (w *WorkerPool) HasJob(jobID uuid.UUID) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, exists := w.running[jobID]
+	return exists
+}
+
+// AddPeriodicTask registers task to run once Start is called.
+func (w *WorkerPool) AddPeriodicTask(task PeriodicTask) {
+	w.periodicTasks = append(w.periodicTasks, task)
+}
+
+func (w *WorkerPool) runPeriodic(ctx context.Context, task PeriodicTask) {
+	ticker := time.NewTicker(task.Interval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
+			log.Info().Str("task", task.Name).Msg("Periodic task stopped")
+			return
+		case <-ticker.C:
+			if err := task.Run(ctx); err != nil {
+				log.Error().Err(err).Str("task", task.Name).Msg("Periodic task failed")
+			}
+		}
+	}
+}
+
+func (w *WorkerPool) worker(ctx context.Context, workerID int) {
+	for {
+		item, ok := w.queue.Next(ctx)
+		if !ok {
 			log.Info().Int("worker", workerID).Msg("Worker stopped")
 			return
-		case job := <-w.jobQueue:
-			log.Info().Str("job_id", job.ID.String()).Int("worker", workerID).Msg("Processing settlement job")
-			w.processSettlementJob(ctx, job)
 		}
+
+		log.Info().Str("job_id", item.Job.ID.String()).Int("worker", workerID).Int("priority", item.Priority).Msg("Processing settlement job")
+		w.run(ctx, item.Job)
+		w.queue.Done(item)
 	}
 }
 
+// run processes job under a context of its own, which StopJob cancels.
+func (w *WorkerPool) run(ctx context.Context, job entity.SettlementJob) {
+	jobCtx, stop := context.WithCancelCause(ctx)
+	w.mu.Lock()
+	w.running[job.ID] = stop
+	w.mu.Unlock()
+
+	defer func() {
+		w.mu.Lock()
+		delete(w.running, job.ID)
+		w.mu.Unlock()
+		stop(nil)
+	}()
+
+	w.processSettlementJob(jobCtx, job)
+}
+
 func (w *WorkerPool) processSettlementJob(ctx context.Context, job entity.SettlementJob) {
 
-	err := w.jobRepo.UpdateStatus(ctx, job.ID, "RUNNING", nil)
+	snapshot := entity.JobEntity{
+		ID:        job.ID,
+		Type:      "SETTLEMENT",
+		Status:    "QUEUED",
+		Priority:  job.Priority,
+		StartedAt: job.StartedAt,
+	}
+
+	const batchSize = 10000
+	var (
+		processed int64 = 0
+		cursor    *entity.TransactionCursor
+	)
+
+	settlementsMap := make(map[string]*entity.SettlementEntity)
+	if job.Checkpoint != nil {
+		cursor = job.Checkpoint.After
+		processed = job.Checkpoint.Processed
+		for _, settlement := range job.Checkpoint.Settlements {
+			settlementsMap[settlementKey(settlement.MerchantID, settlement.Date.Format("2006-01-02"), settlement.Currency)] = &settlement
+		}
+	}
+	resumedFrom := processed
+	snapshot.Processed = processed
+
+	// A job cancelled or paused while it waited for this worker is no longer QUEUED and never starts.
+	started, err := w.jobRepo.TransitionStatus(ctx, job.ID, "QUEUED", "RUNNING")
 	if err != nil {
+		if w.stopped(ctx, job, snapshot, cursor, settlementsMap) {
+			return
+		}
 		log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to update job status to RUNNING")
 		return
 	}
+	if !started {
+		log.Info().Str("job_id", job.ID.String()).Msg("Job no longer queued, skipping")
+		return
+	}
+	snapshot.Status = "RUNNING"
+	snapshot.Requeues = job.Requeues
+
+	// Every run gets the whole max runtime; the watchdog also reads the deadline, for runs whose replica is gone.
+	if job.Timeout > 0 {
+		deadline := time.Now().Add(job.Timeout)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadlineCause(ctx, deadline, fmt.Errorf("%w: ran past its max runtime of %s", errJobTimedOut, job.Timeout))
+		defer cancel()
+		snapshot.DeadlineAt = &deadline
+	}
+	if err := w.jobRepo.UpdateDeadline(ctx, job.ID, snapshot.DeadlineAt); err != nil {
+		log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to update deadline")
+	}
 
+	// A resumed job keeps its original start; its rate is measured from now, over the rows it reads from here.
 	now := time.Now()
-	err = w.jobRepo.UpdateStartedAt(ctx, job.ID, &now)
-	if err != nil {
-		log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to update started_at")
+	if snapshot.StartedAt == nil {
+		snapshot.StartedAt = &now
+		err = w.jobRepo.UpdateStartedAt(ctx, job.ID, &now)
+		if err != nil {
+			log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to update started_at")
+		}
 	}
 
-	const batchSize = 10000
-	var processed int64 = 0
-
 	total, err := w.transactionRepo.Count(ctx, job.From, job.To)
 	if err != nil {
+		if w.stopped(ctx, job, snapshot, cursor, settlementsMap) {
+			return
+		}
 		log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to count total transactions")
-		w.markJobAsFailed(ctx, job.ID, err.Error())
+		w.markJobAsFailed(ctx, snapshot, err.Error())
 		return
 	}
 
-	settlementsMap := make(map[string]*entity.SettlementEntity)
+	snapshot.Total = total
+	snapshot.Progress = progressOf(processed, total)
+	w.progress.Publish(entity.JobUpdateEntity{Event: entity.JobUpdateStarted, Job: snapshot})
 
-	for offset := int64(0); offset < total; offset += batchSize {
-		select {
-		case <-job.Cancelled:
-			log.Info().Str("job_id", job.ID.String()).Msg("Job cancelled")
-			w.markJobAsCancelled(ctx, job.ID)
-			return
-		case <-ctx.Done():
+	// Transactions paid in the range while the job runs may add batches beyond total; it reads until one comes
+	// back short.
+	for {
+		if w.stopped(ctx, job, snapshot, cursor, settlementsMap) {
 			return
-		default:
 		}
 
-		transactions, err := w.transactionRepo.GetBatch(ctx, job.From, job.To, offset, batchSize)
+		transactions, err := w.transactionRepo.GetBatch(ctx, job.From, job.To, cursor, batchSize)
 		if err != nil {
+			if w.stopped(ctx, job, snapshot, cursor, settlementsMap) {
+				return
+			}
 			log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to get transaction batch")
-			w.markJobAsFailed(ctx, job.ID, err.Error())
+			w.markJobAsFailed(ctx, snapshot, err.Error())
 			return
 		}
+		if len(transactions) == 0 {
+			break
+		}
 
 		for _, txn := range transactions {
 			dateKey := txn.PaidAt.Format("2006-01-02")
-			key := fmt.Sprintf("%s_%s", txn.MerchantID, dateKey)
-
-			if settlement, exists := settlementsMap[key]; exists {
-				settlement.GrossCents += int64(txn.AmountCents)
-				settlement.FeeCents += int64(txn.FeeCents)
-				settlement.NetCents += int64(txn.AmountCents) - int64(txn.FeeCents)
-				settlement.TxnCount++
-			} else {
+			// Settlements are per merchant, day and currency; amounts in different currencies are never summed.
+			key := settlementKey(txn.MerchantID, dateKey, txn.Amount.Currency)
+
+			settlement, exists := settlementsMap[key]
+			if !exists {
 				date, _ := time.Parse("2006-01-02", dateKey)
-				settlementsMap[key] = &entity.SettlementEntity{
+				zero := entity.NewMoney(0, txn.Amount.Currency)
+				settlement = &entity.SettlementEntity{
 					MerchantID:  txn.MerchantID,
 					Date:        date,
-					GrossCents:  int64(txn.AmountCents),
-					FeeCents:    int64(txn.FeeCents),
-					NetCents:    int64(txn.AmountCents) - int64(txn.FeeCents),
-					TxnCount:    1,
+					Currency:    txn.Amount.Currency,
+					Gross:       zero,
+					Fee:         zero,
+					Net:         zero,
 					GeneratedAt: time.Now(),
 					UniqueRunID: job.RunID,
 				}
+				settlementsMap[key] = settlement
+			}
+
+			if err := settlement.AddTransaction(txn); err != nil {
+				log.Error().Err(err).Str("job_id", job.ID.String()).Str("transaction_id", txn.ID.String()).Msg("Failed to add transaction to settlement")
+				w.markJobAsFailed(ctx, snapshot, err.Error())
+				return
 			}
 		}
 
+		last := transactions[len(transactions)-1]
+		cursor = &entity.TransactionCursor{PaidAt: *last.PaidAt, ID: last.ID}
 		processed += int64(len(transactions))
 
-		progress := int((processed * 100) / total)
-		err = w.jobRepo.UpdateProgress(ctx, job.ID, progress, processed)
+		progress := progressOf(processed, total)
+		batchDone := time.Now()
+		throughput := rowsPerSecond(processed-resumedFrom, batchDone.Sub(now))
+		err = w.jobRepo.UpdateProgress(ctx, job.ID, progress, processed, throughput)
 		if err != nil {
 			log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to update progress")
 		}
 
+		snapshot.Progress = progress
+		snapshot.Processed = processed
+		snapshot.Throughput = throughput
+		snapshot.EstimatedCompletionAt = estimateCompletion(throughput, max(total-processed, 0), batchDone)
+		w.progress.Publish(entity.JobUpdateEntity{Event: entity.JobUpdateProgress, Job: snapshot})
+
 		log.Info().
 			Str("job_id", job.ID.String()).
 			Int64("processed", processed).
 			Int64("total", total).
 			Int("progress", progress).
+			Float64("rows_per_second", throughput).
 			Msg("Batch processed")
+
+		if int64(len(transactions)) < batchSize {
+			break
+		}
 	}
 
 	settlements := make([]entity.SettlementEntity, 0, len(settlementsMap))
@@ -151,27 +332,59 @@ func (w *WorkerPool) processSettlementJob(ctx context.Context, job entity.Settle
 		settlements = append(settlements, *settlement)
 	}
 
-	err = w.settlementRepo.UpsertBatch(ctx, settlements)
-	if err != nil {
-		log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to upsert settlements")
-		w.markJobAsFailed(ctx, job.ID, err.Error())
-		return
-	}
-
-	csvPath, err := w.generateCSV(job.ID, settlements)
+	csvPath, err := w.generateCSV(ctx, job.ID, settlements)
 	if err != nil {
+		if w.stopped(ctx, job, snapshot, cursor, settlementsMap) {
+			return
+		}
 		log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to generate CSV")
-		w.markJobAsFailed(ctx, job.ID, err.Error())
+		w.markJobAsFailed(ctx, snapshot, err.Error())
 		return
 	}
 
+	// The final rate covers writing the settlements and the CSV too, which is what estimates for later runs need.
+	// The settlements are written with the completion, so a job stopped on the way leaves none of its rows behind.
 	completedAt := time.Now()
-	err = w.jobRepo.Complete(ctx, job.ID, csvPath, &completedAt)
+	completed := snapshot
+	completed.Status = "COMPLETED"
+	completed.Progress = 100
+	completed.Throughput = rowsPerSecond(processed-resumedFrom, completedAt.Sub(now))
+	completed.ResultPath = &csvPath
+	completed.CompletedAt = &completedAt
+	completed.EstimatedCompletionAt = nil
+	err = w.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := w.settlementRepo.UpsertBatch(ctx, settlements); err != nil {
+			return err
+		}
+		if err := w.jobRepo.Complete(ctx, job.ID, csvPath, &completedAt, completed.Throughput); err != nil {
+			return err
+		}
+		if completed.Throughput > 0 {
+			if err := w.jobRepo.RecordThroughput(ctx, completed.Type, completed.Throughput, throughputSmoothing); err != nil {
+				return err
+			}
+		}
+		return recordJobEvent(ctx, w.outboxRepo, entity.EventJobCompleted, completed)
+	})
 	if err != nil {
+		if removeErr := os.Remove(csvPath); removeErr != nil {
+			log.Error().Err(removeErr).Str("job_id", job.ID.String()).Str("csv_path", csvPath).Msg("Failed to remove CSV")
+		}
+
+		if w.stopped(ctx, job, snapshot, cursor, settlementsMap) {
+			return
+		}
+		if errors.Is(err, errs.ErrJobNotRunning) {
+			log.Info().Str("job_id", job.ID.String()).Msg("Job left RUNNING before its completion was recorded")
+			return
+		}
 		log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to mark job as completed")
+		w.markJobAsFailed(ctx, snapshot, err.Error())
 		return
 	}
 
+	w.progress.Publish(entity.JobUpdateEntity{Event: entity.JobUpdateCompleted, Job: completed})
+
 	log.Info().
 		Str("job_id", job.ID.String()).
 		Str("csv_path", csvPath).
@@ -179,7 +392,21 @@ func (w *WorkerPool) processSettlementJob(ctx context.Context, job entity.Settle
 		Msg("Settlement job completed successfully")
 }
 
-func (w *WorkerPool) generateCSV(jobID uuid.UUID, settlements []entity.SettlementEntity) (string, error) {
+// progressOf is processed as a percentage of total, held at 100 when transactions paid since the count push
+// processed past it.
+func progressOf(processed int64, total int64) int {
+	if total <= 0 {
+		return 0
+	}
+	return int(min(processed*100/total, 100))
+}
+
+func settlementKey(merchantID string, date string, currency string) string {
+	return fmt.Sprintf("%s_%s_%s", merchantID, date, currency)
+}
+
+// generateCSV writes the settlements to the job's CSV file, removing what it wrote if it fails or ctx ends first.
+func (w *WorkerPool) generateCSV(ctx context.Context, jobID uuid.UUID, settlements []entity.SettlementEntity) (string, error) {
 	dir := "tmp/settlements"
 	err := os.MkdirAll(dir, 0755)
 	if err != nil {
@@ -193,60 +420,203 @@ func (w *WorkerPool) generateCSV(jobID uuid.UUID, settlements []entity.Settlemen
 	if err != nil {
 		return "", fmt.Errorf("failed to create CSV file: %w", err)
 	}
-	defer file.Close()
 
+	err = writeSettlementsCSV(ctx, file, settlements)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to close CSV file: %w", closeErr)
+	}
+	if err != nil {
+		if removeErr := os.Remove(filepath); removeErr != nil {
+			log.Error().Err(removeErr).Str("job_id", jobID.String()).Str("csv_path", filepath).Msg("Failed to remove partial CSV")
+		}
+		return "", err
+	}
+
+	return filepath, nil
+}
+
+func writeSettlementsCSV(ctx context.Context, file *os.File, settlements []entity.SettlementEntity) error {
 	writer := csv.NewWriter(file)
-	defer writer.Flush()
 
-	header := []string{"merchant_id", "date", "gross", "fee", "net", "txn_count"}
+	header := []string{"merchant_id", "date", "currency", "gross", "fee", "net", "txn_count"}
 	if err := writer.Write(header); err != nil {
-		return "", fmt.Errorf("failed to write CSV header: %w", err)
+		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
-	for _, settlement := range settlements {
+	for i, settlement := range settlements {
+		if i%1000 == 0 && ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+
 		record := []string{
 			settlement.MerchantID,
 			settlement.Date.Format("2006-01-02"),
-			strconv.FormatInt(settlement.GrossCents, 10),
-			strconv.FormatInt(settlement.FeeCents, 10),
-			strconv.FormatInt(settlement.NetCents, 10),
+			settlement.Currency,
+			settlement.Gross.Decimal(),
+			settlement.Fee.Decimal(),
+			settlement.Net.Decimal(),
 			strconv.Itoa(settlement.TxnCount),
 		}
 		if err := writer.Write(record); err != nil {
-			return "", fmt.Errorf("failed to write CSV record: %w", err)
+			return fmt.Errorf("failed to write CSV record: %w", err)
 		}
 	}
 
-	return filepath, nil
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV: %w", err)
+	}
+
+	return nil
 }
 
-func (w *WorkerPool) markJobAsFailed(ctx context.Context, jobID uuid.UUID, errorMsg string) {
+// stopped reports whether the job's context has ended. A cancel or pause is recorded against the job, under a
+// context that outlives the cancelled one; a pool shutdown leaves the job as it is.
+func (w *WorkerPool) stopped(ctx context.Context, job entity.SettlementJob, snapshot entity.JobEntity, cursor *entity.TransactionCursor, settlementsMap map[string]*entity.SettlementEntity) bool {
+	if ctx.Err() == nil {
+		return false
+	}
+
+	cause := context.Cause(ctx)
+	record := context.WithoutCancel(ctx)
+	if errors.Is(cause, errJobCancelled) {
+		log.Info().Str("job_id", job.ID.String()).Msg("Job cancelled")
+		w.markJobAsCancelled(record, snapshot)
+	} else if errors.Is(cause, errJobPaused) {
+		log.Info().Str("job_id", job.ID.String()).Int64("processed", snapshot.Processed).Msg("Job paused")
+		w.markJobAsPaused(record, snapshot, cursor, settlementsMap)
+	} else if errors.Is(cause, errJobTimedOut) || errors.Is(cause, errJobStalled) {
+		checkpoint := newSettlementCheckpoint(snapshot, cursor, settlementsMap)
+		checkpointJSON, err := json.Marshal(checkpoint)
+		if err != nil {
+			log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to marshal checkpoint")
+			w.markJobAsFailed(record, snapshot, cause.Error())
+			return true
+		}
+		job.Checkpoint = &checkpoint
+		checkpointString := string(checkpointJSON)
+		w.timeOut(record, job, snapshot, cause, &checkpointString)
+	} else {
+		log.Info().Err(cause).Str("job_id", job.ID.String()).Msg("Job stopped")
+	}
+
+	return true
+}
+
+// markJobAsFailed and markJobAsCancelled take the worker's last snapshot of the job so the terminal update
+// subscribers see keeps the progress reached so far. markJobAsFailed records nothing if the job has left the
+// status in the snapshot, as when the watchdog has already failed or requeued it, and reports whether it did.
+func (w *WorkerPool) markJobAsFailed(ctx context.Context, job entity.JobEntity, errorMsg string) bool {
+	jobID := job.ID
+	fromStatus := job.Status
 	completedAt := time.Now()
-	err := w.jobRepo.UpdateStatus(ctx, jobID, "FAILED", &errorMsg)
+	job.Status = "FAILED"
+	job.ErrorMessage = &errorMsg
+	job.CompletedAt = &completedAt
+	job.EstimatedCompletionAt = nil
+	failed := false
+	err := w.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		changed, err := w.jobRepo.TransitionStatus(ctx, jobID, fromStatus, "FAILED")
+		if err != nil || !changed {
+			return err
+		}
+
+		if err := w.jobRepo.UpdateStatus(ctx, jobID, "FAILED", &errorMsg); err != nil {
+			return err
+		}
+
+		if err := w.jobRepo.UpdateCompletedAt(ctx, jobID, &completedAt); err != nil {
+			return err
+		}
+
+		failed = true
+		return recordJobEvent(ctx, w.outboxRepo, entity.EventJobFailed, job)
+	})
 	if err != nil {
 		log.Error().Err(err).Str("job_id", jobID.String()).Msg("Failed to mark job as failed")
+		return false
+	}
+	if !failed {
+		return false
 	}
 
-	err = w.jobRepo.UpdateCompletedAt(ctx, jobID, &completedAt)
+	w.progress.Publish(entity.JobUpdateEntity{Event: entity.JobUpdateFailed, Job: job})
+	return true
+}
+
+// markJobAsPaused stores where the job stopped, cursor being the last transaction it read, and what it had
+// aggregated, so a resumed run carries on from there.
+func (w *WorkerPool) markJobAsPaused(ctx context.Context, job entity.JobEntity, cursor *entity.TransactionCursor, settlementsMap map[string]*entity.SettlementEntity) {
+	checkpointJSON, err := json.Marshal(newSettlementCheckpoint(job, cursor, settlementsMap))
 	if err != nil {
-		log.Error().Err(err).Str("job_id", jobID.String()).Msg("Failed to update completed_at for failed job")
+		log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to marshal checkpoint")
+		w.markJobAsFailed(ctx, job, err.Error())
+		return
+	}
+
+	if err := w.jobRepo.Pause(ctx, job.ID, string(checkpointJSON), job.Progress, job.Processed); err != nil {
+		if errors.Is(err, errs.ErrJobNotRunning) {
+			log.Info().Str("job_id", job.ID.String()).Msg("Job left RUNNING before its pause was recorded")
+			return
+		}
+		log.Error().Err(err).Str("job_id", job.ID.String()).Msg("Failed to mark job as paused")
+		return
+	}
+
+	job.Status = "PAUSED"
+	job.Throughput = 0
+	job.EstimatedCompletionAt = nil
+	w.progress.Publish(entity.JobUpdateEntity{Event: entity.JobUpdatePaused, Job: job})
+}
+
+// newSettlementCheckpoint captures the job's progress up to cursor and the settlements aggregated so far.
+func newSettlementCheckpoint(job entity.JobEntity, cursor *entity.TransactionCursor, settlementsMap map[string]*entity.SettlementEntity) entity.SettlementCheckpoint {
+	checkpoint := entity.SettlementCheckpoint{
+		After:       cursor,
+		Processed:   job.Processed,
+		Settlements: make([]entity.SettlementEntity, 0, len(settlementsMap)),
+	}
+	for _, settlement := range settlementsMap {
+		checkpoint.Settlements = append(checkpoint.Settlements, *settlement)
 	}
+	return checkpoint
 }
 
-func (w *WorkerPool) markJobAsCancelled(ctx context.Context, jobID uuid.UUID) {
+// markJobAsCancelled records the cancellation only if the job is still in the status the worker last saw, so
+// the CANCELLED update and its event are recorded once even when CancelJob races the worker.
+func (w *WorkerPool) markJobAsCancelled(ctx context.Context, job entity.JobEntity) {
+	jobID := job.ID
+	fromStatus := job.Status
 	completedAt := time.Now()
-	err := w.jobRepo.UpdateStatus(ctx, jobID, "CANCELLED", nil)
+	job.Status = "CANCELLED"
+	job.Cancelled = true
+	job.CompletedAt = &completedAt
+	job.EstimatedCompletionAt = nil
+	cancelled := false
+	err := w.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		changed, err := w.jobRepo.TransitionStatus(ctx, jobID, fromStatus, "CANCELLED")
+		if err != nil || !changed {
+			return err
+		}
+
+		if err := w.jobRepo.UpdateCancelledFlag(ctx, jobID, true); err != nil {
+			return err
+		}
+
+		if err := w.jobRepo.UpdateCompletedAt(ctx, jobID, &completedAt); err != nil {
+			return err
+		}
+
+		cancelled = true
+		return recordJobEvent(ctx, w.outboxRepo, entity.EventJobCancelled, job)
+	})
 	if err != nil {
 		log.Error().Err(err).Str("job_id", jobID.String()).Msg("Failed to mark job as cancelled")
+		return
 	}
-
-	err = w.jobRepo.UpdateCancelledFlag(ctx, jobID, true)
-	if err != nil {
-		log.Error().Err(err).Str("job_id", jobID.String()).Msg("Failed to update cancelled flag")
+	if !cancelled {
+		return
 	}
 
-	err = w.jobRepo.UpdateCompletedAt(ctx, jobID, &completedAt)
-	if err != nil {
-		log.Error().Err(err).Str("job_id", jobID.String()).Msg("Failed to update completed_at for cancelled job")
-	}
+	w.progress.Publish(entity.JobUpdateEntity{Event: entity.JobUpdateCancelled, Job: job})
 }
@@ -0,0 +1,221 @@
+// Package cron parses standard five-field cron expressions (minute, hour, day of month, month, day of week)
+// and works out when they next occur.
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression. Each field is a bit set of the values it matches.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// Vixie cron semantics: when both day fields are restricted a day matches either of them, otherwise both.
+	domAny, dowAny bool
+}
+
+type bounds struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minutes = bounds{min: 0, max: 59}
+	hours   = bounds{min: 0, max: 23}
+	doms    = bounds{min: 1, max: 31}
+	months  = bounds{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// 7 is accepted for Sunday as well as 0.
+	dows = bounds{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses expr, either five space-separated fields or one of @yearly, @annually, @monthly, @weekly,
+// @daily, @midnight and @hourly. Fields take *, values, ranges (a-b), steps (*/n, a-b/n) and comma-separated
+// lists of those; months and days of the week also take three-letter English names.
+func Parse(expr string) (*Schedule, error) {
+
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := descriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron: expected 5 fields, got %d in %q", len(fields), expr)
+	}
+
+	var (
+		s   Schedule
+		err error
+	)
+	if s.minute, err = parseField(fields[0], minutes); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hours); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], doms); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], months); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dows); err != nil {
+		return nil, err
+	}
+
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny = fields[2] == "*"
+	s.dowAny = fields[4] == "*"
+
+	return &s, nil
+}
+
+// Next returns the first time after t that the schedule matches, in t's location and to the minute. It returns
+// the zero time when nothing matches within five years, which only happens for dates like February 30th.
+func (s *Schedule) Next(t time.Time) time.Time {
+
+	loc := t.Location()
+	next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := next.AddDate(5, 0, 0)
+
+	// Each step moves to the start of the next month, day, hour or minute that could still match; time.Date
+	// normalises overflow, and forward moves on from the daylight saving gaps it does not.
+	for next.Before(limit) {
+		if !has(s.month, int(next.Month())) {
+			next = forward(next, time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !s.dayMatches(next) {
+			next = forward(next, time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, loc))
+			continue
+		}
+		if !has(s.hour, next.Hour()) {
+			next = forward(next, time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, loc))
+			continue
+		}
+		if !has(s.minute, next.Minute()) {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+
+	return time.Time{}
+}
+
+// forward returns step, the start of a later month, day or hour than t. A start the clocks skip, such as 02:00
+// when they go from 01:59 to 03:00, comes back from time.Date before the change and so possibly not after t; it
+// is moved on by hours until it is, landing in the first hour after the change.
+func forward(t, step time.Time) time.Time {
+	for !step.After(t) {
+		step = step.Add(time.Hour)
+	}
+	return step
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := has(s.dom, t.Day())
+	dowMatch := has(s.dow, int(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+func has(set uint64, value int) bool {
+	return set&(1<<uint(value)) != 0
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		bits, err := parsePart(part, b)
+		if err != nil {
+			return 0, err
+		}
+		set |= bits
+	}
+
+	return set, nil
+}
+
+// parsePart parses one list item: *, a value, or a range, each optionally followed by /step.
+func parsePart(part string, b bounds) (uint64, error) {
+
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	start, end := b.min, b.max
+	if rangePart != "*" {
+		low, high, isRange := strings.Cut(rangePart, "-")
+
+		var err error
+		if start, err = parseValue(low, b); err != nil {
+			return 0, err
+		}
+		end = start
+		if isRange {
+			if end, err = parseValue(high, b); err != nil {
+				return 0, err
+			}
+		} else if hasStep {
+			// "5/15" means every 15 from 5 up to the field's maximum.
+			end = b.max
+		}
+	}
+
+	if start > end {
+		return 0, fmt.Errorf("cron: range %q runs backwards", part)
+	}
+
+	step := 1
+	if hasStep {
+		var err error
+		if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+			return 0, fmt.Errorf("cron: invalid step in %q", part)
+		}
+	}
+
+	var bits uint64
+	for value := start; value <= end; value += step {
+		bits |= 1 << uint(value)
+	}
+
+	return bits, nil
+}
+
+func parseValue(value string, b bounds) (int, error) {
+
+	if n, ok := b.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("cron: invalid value %q", value)
+	}
+	if n < b.min || n > b.max {
+		return 0, fmt.Errorf("cron: value %d out of range %d-%d", n, b.min, b.max)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,121 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	santiago, err := time.LoadLocation("America/Santiago")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+
+	utc := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		// 2025-01-01 is a Wednesday.
+		{"hourly", "@hourly", utc(2025, 1, 1, 10, 15), utc(2025, 1, 1, 11, 0)},
+		{"daily", "@daily", utc(2025, 1, 1, 10, 15), utc(2025, 1, 2, 0, 0)},
+		{"midnight", "@midnight", utc(2025, 1, 1, 0, 0), utc(2025, 1, 2, 0, 0)},
+		{"weekly", "@weekly", utc(2025, 1, 1, 0, 0), utc(2025, 1, 5, 0, 0)},
+		{"monthly", "@monthly", utc(2025, 1, 31, 12, 0), utc(2025, 2, 1, 0, 0)},
+		{"yearly", "@yearly", utc(2025, 3, 1, 0, 0), utc(2026, 1, 1, 0, 0)},
+		{"annually", "@annually", utc(2025, 3, 1, 0, 0), utc(2026, 1, 1, 0, 0)},
+		{"descriptor in upper case", "@DAILY", utc(2025, 1, 1, 10, 15), utc(2025, 1, 2, 0, 0)},
+		{"seconds are dropped", "* * * * *", time.Date(2025, 1, 1, 10, 0, 30, 0, time.UTC), utc(2025, 1, 1, 10, 1)},
+		{"strictly after from", "0 10 * * *", utc(2025, 1, 1, 10, 0), utc(2025, 1, 2, 10, 0)},
+
+		{"step", "*/15 * * * *", utc(2025, 1, 1, 10, 7), utc(2025, 1, 1, 10, 15)},
+		{"step from a value", "5/20 * * * *", utc(2025, 1, 1, 10, 26), utc(2025, 1, 1, 10, 45)},
+		{"stepped range", "0 9-17/4 * * *", utc(2025, 1, 1, 10, 0), utc(2025, 1, 1, 13, 0)},
+		{"stepped range past its end", "0 9-17/4 * * *", utc(2025, 1, 1, 17, 0), utc(2025, 1, 2, 9, 0)},
+		{"list", "0 8,12,18 * * *", utc(2025, 1, 1, 12, 0), utc(2025, 1, 1, 18, 0)},
+		{"weekday range", "0 0 * * 1-5", utc(2025, 1, 3, 12, 0), utc(2025, 1, 6, 0, 0)},
+		{"day names", "30 6 * * mon,WED", utc(2025, 1, 1, 7, 0), utc(2025, 1, 6, 6, 30)},
+		{"month names", "0 0 1 jun-aug *", utc(2025, 1, 1, 0, 0), utc(2025, 6, 1, 0, 0)},
+
+		{"7 is Sunday", "0 0 * * 7", utc(2025, 1, 1, 0, 0), utc(2025, 1, 5, 0, 0)},
+		{"0 is Sunday", "0 0 * * 0", utc(2025, 1, 1, 0, 0), utc(2025, 1, 5, 0, 0)},
+		{"range ending in 7", "0 0 * * 6-7", utc(2025, 1, 5, 0, 0), utc(2025, 1, 11, 0, 0)},
+
+		// With both day fields restricted a day matches either; with one of them * only the other counts.
+		{"dom or dow, dow first", "0 0 13 * 5", utc(2025, 1, 1, 0, 0), utc(2025, 1, 3, 0, 0)},
+		{"dom or dow, dom first", "0 0 13 * 5", utc(2025, 1, 10, 0, 0), utc(2025, 1, 13, 0, 0)},
+		{"dom only", "0 0 13 * *", utc(2025, 1, 1, 0, 0), utc(2025, 1, 13, 0, 0)},
+		{"dow only", "0 0 * * 5", utc(2025, 1, 4, 0, 0), utc(2025, 1, 10, 0, 0)},
+
+		{"day rollover", "0 0 * * *", utc(2025, 1, 31, 23, 59), utc(2025, 2, 1, 0, 0)},
+		{"month without the day", "0 0 31 * *", utc(2025, 1, 31, 0, 0), utc(2025, 3, 31, 0, 0)},
+		{"year rollover", "59 23 31 12 *", utc(2025, 12, 31, 23, 59), utc(2026, 12, 31, 23, 59)},
+		{"leap day", "0 0 29 2 *", utc(2025, 1, 1, 0, 0), utc(2028, 2, 29, 0, 0)},
+
+		// 2025-03-09 02:00 does not exist in New York: clocks go from 01:59 EST to 03:00 EDT.
+		{"hourly across a daylight saving gap", "0 * * * *", time.Date(2025, 3, 9, 1, 30, 0, 0, newYork), time.Date(2025, 3, 9, 3, 0, 0, 0, newYork)},
+		{"time in a daylight saving gap", "30 2 * * *", time.Date(2025, 3, 8, 3, 0, 0, 0, newYork), time.Date(2025, 3, 10, 2, 30, 0, 0, newYork)},
+		{"daily after a daylight saving gap", "0 9 * * *", time.Date(2025, 3, 8, 9, 0, 0, 0, newYork), time.Date(2025, 3, 9, 9, 0, 0, 0, newYork)},
+		// 2025-09-07 starts at 01:00 in Santiago: clocks go from 23:59 to 01:00.
+		{"day starting in a daylight saving gap", "0 9 * * *", time.Date(2025, 9, 6, 9, 0, 0, 0, santiago), time.Date(2025, 9, 7, 9, 0, 0, 0, santiago)},
+		{"midnight in a daylight saving gap", "0 0 * * *", time.Date(2025, 9, 6, 0, 0, 0, 0, santiago), time.Date(2025, 9, 8, 0, 0, 0, 0, santiago)},
+
+		{"never matches", "0 0 30 2 *", utc(2025, 1, 1, 0, 0), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.expr, err)
+			}
+
+			got := schedule.Next(tt.from)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.from, got, tt.want)
+			}
+			if !got.IsZero() && got.Location() != tt.from.Location() {
+				t.Errorf("Next(%s) is in %s, want %s", tt.from, got.Location(), tt.from.Location())
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"too few fields", "* * * *"},
+		{"too many fields", "* * * * * *"},
+		{"unknown descriptor", "@every 5m"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "0 24 * * *"},
+		{"day of month zero", "0 0 0 * *"},
+		{"month out of range", "0 0 1 13 *"},
+		{"day of week out of range", "0 0 * * 8"},
+		{"backwards range", "5-1 * * * *"},
+		{"zero step", "*/0 * * * *"},
+		{"invalid step", "*/x * * * *"},
+		{"unknown name", "0 0 * foo *"},
+		{"name in the wrong field", "0 0 * * jan"},
+		{"empty list item", "1,,2 * * * *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.expr); err == nil {
+				t.Errorf("Parse(%q) succeeded, want an error", tt.expr)
+			}
+		})
+	}
+}